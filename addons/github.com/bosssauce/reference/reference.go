@@ -10,7 +10,6 @@ import (
 	"html"
 	"html/template"
 	"log"
-	"strings"
 
 	"github.com/ponzu-cms/ponzu/management/editor"
 	"github.com/ponzu-cms/ponzu/system/addon"
@@ -52,8 +51,7 @@ func SelectRepeater(fieldName string, p interface{}, attrs map[string]string, co
 	}
 
 	// find the field values in p to determine if an option is pre-selected
-	fieldVals := editor.ValueFromStructField(fieldName, p)
-	vals := strings.Split(fieldVals, "__ponzu")
+	vals := editor.ValuesFromStructField(fieldName, p)
 
 	options, err := encodeDataToOptions(contentType, tmplString)
 	if err != nil {
@@ -149,3 +147,20 @@ func encodeDataToOptions(contentType, tmplString string) (map[string]string, err
 
 	return options, nil
 }
+
+// WeightedSelectRepeater returns the []byte of a repeatable <select> HTML element
+// of references, where each reference also carries an editable weight and can be
+// dragged to reorder it. Each item submits the referenced content and its weight,
+// see editor.WeightedSelectRepeater and editor.ParseWeighted.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func WeightedSelectRepeater(fieldName string, p interface{}, attrs map[string]string, contentType, tmplString string) []byte {
+	options, err := encodeDataToOptions(contentType, tmplString)
+	if err != nil {
+		log.Println("Error encoding data to options for", contentType, err)
+		return nil
+	}
+
+	return editor.WeightedSelectRepeater(fieldName, p, attrs, options)
+}
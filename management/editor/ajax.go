@@ -0,0 +1,158 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ValidationErrors maps the form names of fields to the message describing
+// why their submitted value was rejected. Items of repeated fields are named
+// by their index, e.g. "links.2", see AddIndexed.
+//
+// When the editor is submitted with FormOptions.AJAX, a hook such as
+// BeforeAdminUpdate can reject the submission by writing its errors with
+// WriteValidationErrors and returning an error. The editor then shows each
+// message alongside its field without reloading the page.
+type ValidationErrors map[string]string
+
+// Add sets the message for the field named name
+func (v ValidationErrors) Add(name, message string) {
+	v[name] = message
+}
+
+// AddIndexed sets the message for item i of the repeated field named name
+func (v ValidationErrors) AddIndexed(name string, i int, message string) {
+	v[fmt.Sprintf("%s.%d", name, i)] = message
+}
+
+// Error implements error, listing each field and its message
+func (v ValidationErrors) Error() string {
+	var msgs []string
+	for name, message := range v {
+		msgs = append(msgs, name+": "+message)
+	}
+	sort.Strings(msgs)
+
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Rejected is implemented by content holding the ValidationErrors of a
+// submission which was rejected, so that a save handler can render its editor
+// again, with the values which were submitted and each message alongside the
+// field it names. Repeaters show the message of an item, e.g. "links.2", below
+// that item, whose index is its position among the values returned by
+// ParseRepeated, and any other message for the field, such as one naming an
+// item it doesn't hold, below all of its items.
+type Rejected interface {
+	ValidationErrors() ValidationErrors
+}
+
+// validationResponse is the JSON body of a response rejecting a submission,
+// e.g. {"errors": {"title": "Title is required", "links.2": "Invalid URL"}}
+type validationResponse struct {
+	Errors ValidationErrors `json:"errors"`
+}
+
+// WriteValidationErrors responds to an editor submission with errs as JSON in
+// the form {"errors": {"<field name>": "<message>", ...}} and the status 422
+// Unprocessable Entity, which the editor shows alongside each field when it
+// was submitted with FormOptions.AJAX
+func WriteValidationErrors(res http.ResponseWriter, errs ValidationErrors) error {
+	body, err := json.Marshal(validationResponse{Errors: errs})
+	if err != nil {
+		return err
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusUnprocessableEntity)
+
+	_, err = res.Write(body)
+	return err
+}
+
+// IsAJAX reports whether req was submitted by an editor with FormOptions.AJAX,
+// and so expects validation errors to be written with WriteValidationErrors
+func IsAJAX(req *http.Request) bool {
+	return req.Header.Get("X-Ponzu-Ajax") == "true"
+}
+
+// ajaxScript returns the script which submits the editor form in the background
+// when it is saved, and shows any validation errors in the response alongside
+// their fields. Once saved, the browser follows any redirect of the response,
+// or else stays on the page and shows a toast.
+func ajaxScript() string {
+	return `
+		form.on('submit', function(e) {
+			var action = form.attr('action') || '';
+			if (!/^\/admin\/edit(\?|$)/.test(action) || !window.fetch || !window.FormData) {
+				return;
+			}
+
+			e.preventDefault();
+
+			form.find('.__ponzu-field-error').remove();
+			form.find('.invalid').removeClass('invalid');
+			save.prop('disabled', true);
+
+			fetch(action, {
+				method: 'POST',
+				body: new FormData(form.get(0)),
+				credentials: 'same-origin',
+				headers: {'X-Ponzu-Ajax': 'true', 'Accept': 'application/json'}
+			}).then(function(res) {
+				if (res.status === 422) {
+					return res.json().then(showErrors);
+				}
+
+				if (!res.ok) {
+					throw new Error(res.statusText);
+				}
+
+				if (res.redirected && res.url !== window.location.href) {
+					window.location = res.url;
+					return;
+				}
+
+				Materialize.toast(` + jsString(text("ajax.saved")) + `, 3000);
+			}).catch(function(err) {
+				Materialize.toast(` + jsString(text("ajax.failed")) + `.replace('{error}', err.message), 5000);
+			}).then(function() {
+				save.prop('disabled', false);
+			});
+		});
+
+		// showErrors shows each message of the response alongside the field it
+		// names, or at the top of the form if there is no such field
+		var showErrors = function(body) {
+			var errors = (body && body.errors) || {},
+				first = null;
+
+			$.each(errors, function(name, message) {
+				var el = form.find('[name="' + name.replace(/["\\]/g, '\\$&') + '"]').first(),
+					msg = $('<span class="__ponzu-field-error red-text"></span>').text(message);
+
+				if (el.length === 0) {
+					form.find('.editor-fields > td').first().prepend(msg.text(name + ': ' + message));
+					return;
+				}
+
+				el.addClass('invalid');
+				var container = el.closest('.input-field');
+				(container.length ? container : el).after(msg);
+				first = first || el;
+			});
+
+			// expand collapsed repeaters to show their errors
+			var ev = document.createEvent('Event');
+			ev.initEvent('ponzu:invalid', true, false);
+			form.get(0).dispatchEvent(ev);
+
+			if (first) {
+				$('html, body').animate({scrollTop: first.offset().top - 100}, 200);
+			}
+		}
+`
+}
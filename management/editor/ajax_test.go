@@ -0,0 +1,71 @@
+package editor
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteValidationErrors(t *testing.T) {
+	errs := ValidationErrors{}
+	errs.Add("title", "Title is required")
+	errs.AddIndexed("links", 2, "Invalid URL")
+	errs.AddIndexed("links", 0, `Must not contain "<b>"`)
+
+	res := httptest.NewRecorder()
+	if err := WriteValidationErrors(res, errs); err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Code != 422 {
+		t.Errorf("Expected the status 422, got %d", res.Code)
+	}
+
+	if ct := res.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected a JSON response, got %q", ct)
+	}
+
+	var body struct {
+		Errors map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON body, got %s: %s", res.Body, err)
+	}
+
+	want := map[string]string{
+		"title":   "Title is required",
+		"links.0": `Must not contain "<b>"`,
+		"links.2": "Invalid URL",
+	}
+	if !reflect.DeepEqual(body.Errors, want) {
+		t.Errorf("Expected the errors keyed by field name, got %v, want %v", body.Errors, want)
+	}
+
+	if errs.Error() != `validation failed: links.0: Must not contain "<b>"; links.2: Invalid URL; title: Title is required` {
+		t.Errorf("Expected the errors sorted by field name, got: %s", errs.Error())
+	}
+}
+
+func TestIsAJAX(t *testing.T) {
+	req := httptest.NewRequest("POST", "/admin/edit", nil)
+	if IsAJAX(req) {
+		t.Error("Expected a request without the header not to be AJAX")
+	}
+
+	req.Header.Set("X-Ponzu-Ajax", "false")
+	if IsAJAX(req) {
+		t.Error("Expected a request with the header set to false not to be AJAX")
+	}
+
+	req.Header.Set("X-Ponzu-Ajax", "true")
+	if !IsAJAX(req) {
+		t.Error("Expected a request with the header to be AJAX")
+	}
+
+	// the header IsAJAX checks is the one the editor sends
+	if !strings.Contains(ajaxScript(), `headers: {'X-Ponzu-Ajax': 'true', 'Accept': 'application/json'}`) {
+		t.Errorf("Expected the editor to send the header, got: %s", ajaxScript())
+	}
+}
@@ -0,0 +1,174 @@
+package editor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CheckAttrs turns on checking the attrs passed to field functions, which is
+// meant for development. Keys not recognized for the field, such as a typo
+// like "placholder", are reported as warnings through the Logger set with
+// SetLogger, since they would otherwise silently do nothing or be rendered as
+// bogus HTML attributes.
+var CheckAttrs = false
+
+// globalAttrs are HTML attributes recognized on any field which renders its
+// attrs onto an HTML element. Any "data-*" and "aria-*" attribute is also
+// recognized, as is "help", the text shown below the field.
+var globalAttrs = []string{
+	"label", "class", "id", "style", "title", "placeholder", "disabled",
+	"readonly", "required", "autofocus", "autocomplete", "tabindex",
+	"spellcheck", "dir", "lang", "hidden", "help",
+}
+
+// textAttrs are recognized on fields holding free text
+var textAttrs = []string{
+	"minlength", "maxlength", "pattern", "inputmode", "size", "list", "trim", "emoji",
+}
+
+// repeatControlAttrs are recognized on repeaters, and label the controls which
+// add and remove their items, see RepeatControllerOptions, plus the "help"
+// shown once below all of the items, "required", which requires the first
+// item to have a value, "wrapperClass" and "wrapperId", which are set on the
+// container of the items, and "collapsible" and "collapseAbove", which let the
+// items be collapsed
+var repeatControlAttrs = []string{
+	"addLabel", "delLabel", "addIcon", "delIcon", "help", "required", "wrapperClass", "wrapperId",
+	"collapsible", "collapseAbove",
+}
+
+// RecognizedAttrs are the attrs keys recognized by each field function, keyed
+// by the name of the function. Fields which render their attrs onto an HTML
+// element also recognize the globalAttrs, plus any "data-*" and "aria-*"
+// attribute. Fields recognizing "default" show attrs["default"] in place of an
+// empty value, but only while the content is new, so that a value emptied on
+// purpose stays empty once stored.
+var RecognizedAttrs = map[string][]string{
+	"Input": join(globalAttrs, textAttrs, []string{
+		"type", "min", "max", "step", "multiple", "accept", "prefix", "suffix", "default",
+	}),
+	"Textarea":               join(globalAttrs, textAttrs, []string{"rows", "cols", "wrap", "countwords", "default"}),
+	"Markdown":               join(globalAttrs, textAttrs, []string{"rows", "cols", "wrap", "toolbar", "preview", "default"}),
+	"Code":                   join(globalAttrs, []string{"rows", "cols", "wrap", "mode", "default"}),
+	"Timestamp":              join(globalAttrs, []string{"type"}),
+	"DateTime":               {"label", "mode"},
+	"Time":                   {"label", "clock", "step", "help"},
+	"LatLng":                 {"label", "help", "tiles", "zoom", "attribution"},
+	"Toggle":                 {"label", "help", "on", "off"},
+	"TimeRepeater":           join([]string{"label", "clock", "step", "minItems", "maxItems", "numbered", "sortable"}, repeatControlAttrs),
+	"File":                   {"label", "accept", "minwidth", "minheight", "exactwidth", "exactheight"},
+	"Richtext":               join(globalAttrs),
+	"RichText":               {"label", "placeholder", "toolbar", "allowedTags"},
+	"Select":                 join(globalAttrs, []string{"multiple", "size", "default"}),
+	"Checkbox":               join(globalAttrs, []string{"default"}),
+	"Color":                  join(globalAttrs, []string{"size", "default"}),
+	"ColorRepeater":          join(globalAttrs, []string{"size", "minItems", "maxItems", "numbered", "sortable", "default"}, repeatControlAttrs),
+	"Tags":                   {"label", "placeholder"},
+	"Password":               join(globalAttrs, []string{"minlength", "maxlength", "pattern", "size", "default"}),
+	"Autocomplete":           join(globalAttrs, textAttrs, []string{"type", "maxSuggestions", "default"}),
+	"AutocompleteRepeater":   join(globalAttrs, textAttrs, []string{"type", "maxSuggestions", "minItems", "maxItems", "numbered", "sortable", "default"}, repeatControlAttrs),
+	"InputRepeater":          join(globalAttrs, textAttrs, []string{"type", "min", "max", "step", "countwords", "prefix", "suffix", "minItems", "maxItems", "numbered", "sortable", "unique", "default"}, repeatControlAttrs),
+	"NumberRepeater":         join(globalAttrs, []string{"min", "max", "step", "inputmode", "minItems", "maxItems", "numbered", "sortable", "default"}, repeatControlAttrs),
+	"TextareaRepeater":       join(globalAttrs, textAttrs, []string{"rows", "cols", "wrap", "minItems", "maxItems", "numbered", "sortable", "default"}, repeatControlAttrs),
+	"SelectRepeater":         join(globalAttrs, []string{"minItems", "maxItems", "numbered", "sortable", "unique", "ctaLabel", "hideReset", "default"}, repeatControlAttrs),
+	"SelectRepeaterGrouped":  join(globalAttrs, []string{"minItems", "maxItems", "numbered", "sortable", "unique", "ctaLabel", "hideReset", "default"}, repeatControlAttrs),
+	"FileRepeater":           join([]string{"label", "accept", "maxsize", "minItems", "maxItems", "numbered", "sortable"}, repeatControlAttrs),
+	"URL":                    join(globalAttrs, []string{"minlength", "maxlength", "pattern", "size", "list", "trim", "schemes", "default"}),
+	"Money":                  join(globalAttrs, []string{"currency", "locale", "size", "default"}),
+	"Slug":                   join(globalAttrs, []string{"minlength", "maxlength", "pattern", "size", "default"}),
+	"SemVer":                 join(globalAttrs, []string{"size", "trim", "bump", "default"}),
+	"KeyValue":               join([]string{"label", "minItems", "maxItems", "numbered", "sortable"}, repeatControlAttrs),
+	"LinkList":               join([]string{"label", "schemes", "minItems", "maxItems", "numbered", "sortable"}, repeatControlAttrs),
+	"TokenInput":             {"label", "placeholder", "allowNew"},
+	"Segmented":              {"label"},
+	"RadioCards":             {"label"},
+	"DependentSelect":        {"label", "endpoint"},
+	"CascadingSelect":        {"label", "parentLabel", "endpoint"},
+	"Reference":              {"label", "placeholder", "endpoint", "display", "store"},
+	"ReferenceRepeater":      join([]string{"label", "placeholder", "endpoint", "display", "store", "minItems", "maxItems", "numbered", "sortable"}, repeatControlAttrs),
+	"EnumPills":              {"label"},
+	"NumberRange":            {"label", "step", "min", "max"},
+	"Range":                  join(globalAttrs, []string{"min", "max", "step", "list", "default"}),
+	"DistinctValuesSelect":   {"label", "endpoint"},
+	"TimezoneSelect":         join(globalAttrs),
+	"BlockRepeater":          {"label", "toggle"},
+	"WeightedSelectRepeater": {"label"},
+	"RepeaterGroup":          {"label"},
+	"CheckboxGroup":          {"label"},
+	"MultiSelect":            {"label", "size", "default"},
+	"RadioGroup":             {"label"},
+}
+
+// join returns the concatenation of lists
+func join(lists ...[]string) []string {
+	var all []string
+	for _, l := range lists {
+		all = append(all, l...)
+	}
+
+	return all
+}
+
+// rxAttrName matches valid HTML attribute names, which may not contain
+// whitespace, quotes, '>', '/', '=' or control characters
+var rxAttrName = regexp.MustCompile(`^[^\s"'>/=\x00-\x1f\x7f]+$`)
+
+// validAttrName reports whether name can be rendered as an HTML attribute name
+func validAttrName(name string) bool {
+	return rxAttrName.MatchString(name)
+}
+
+// UnknownAttrs returns the keys of attrs, in order, which are not recognized
+// by the field function named field (see RecognizedAttrs). Every key is
+// recognized for fields which are not in RecognizedAttrs.
+func UnknownAttrs(field string, attrs map[string]string) []string {
+	recognized, ok := RecognizedAttrs[field]
+	if !ok {
+		return nil
+	}
+
+	// fields rendering the global attributes render any data-* or aria-* too
+	global := hasString(recognized, "class")
+
+	var unknown []string
+	for k := range attrs {
+		if hasString(recognized, k) {
+			continue
+		}
+
+		if global && (strings.HasPrefix(k, "data-") || strings.HasPrefix(k, "aria-")) {
+			continue
+		}
+
+		unknown = append(unknown, k)
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// checkAttrs reports any keys of attrs not recognized by the field function
+// named field, and any which are invalid HTML attribute names, when CheckAttrs
+// is on
+func checkAttrs(field, fieldName string, attrs map[string]string) {
+	if !CheckAttrs {
+		return
+	}
+
+	for _, k := range UnknownAttrs(field, attrs) {
+		msg := "editor: unrecognized attr"
+		if !validAttrName(k) {
+			msg = "editor: invalid attr name"
+		}
+
+		logf(msg,
+			"attr", k,
+			"func", field,
+			"field", fieldName,
+			"caller", externalCaller(),
+			"recognized", fmt.Sprintf("%v", RecognizedAttrs[field]),
+		)
+	}
+}
@@ -0,0 +1,56 @@
+package editor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnknownAttrs(t *testing.T) {
+	attrs := map[string]string{
+		"label":      "Name",
+		"type":       "text",
+		"placholder": "typo",
+		"data-x":     "1",
+		"aria-label": "Name",
+		"bogus":      "1",
+	}
+
+	unknown := UnknownAttrs("Input", attrs)
+	if expected := []string{"bogus", "placholder"}; !reflect.DeepEqual(unknown, expected) {
+		t.Errorf("Expected unknown attrs %v, got: %v", expected, unknown)
+	}
+
+	if unknown := UnknownAttrs("FileRepeater", map[string]string{"data-x": "1"}); len(unknown) != 1 {
+		t.Errorf("Expected data-* to be unknown for FileRepeater, got: %v", unknown)
+	}
+
+	if unknown := UnknownAttrs("NotAField", attrs); unknown != nil {
+		t.Errorf("Expected no unknown attrs for an unlisted field, got: %v", unknown)
+	}
+}
+
+func TestCheckAttrsLogsAndRejects(t *testing.T) {
+	var logged []string
+	SetLogger(LoggerFunc(func(msg string, keyvals ...interface{}) {
+		logged = append(logged, msg)
+	}))
+	defer SetLogger(nil)
+
+	CheckAttrs = true
+	defer func() { CheckAttrs = false }()
+
+	view := string(Input("Name", &testContact{}, map[string]string{
+		"label":           "Name",
+		"placholder":      "typo",
+		`onclick="x()" a`: "y",
+	}))
+
+	if len(logged) < 2 {
+		t.Errorf("Expected warnings for both attrs, got: %v", logged)
+	}
+
+	if view == "" || strings.Contains(view, "onclick") {
+		t.Errorf("Expected invalid attr name to be rejected, got: %s", view)
+	}
+}
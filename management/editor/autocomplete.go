@@ -0,0 +1,132 @@
+package editor
+
+import (
+	"bytes"
+	"html"
+	"log"
+	"strconv"
+)
+
+// DefaultMaxSuggestions is the most suggestions offered by an Autocomplete when
+// attrs["maxSuggestions"] isn't set
+const DefaultMaxSuggestions = 100
+
+// Autocomplete returns the []byte of an <input> HTML element which suggests
+// values from suggestions as the editor types, using a <datalist>, while still
+// accepting any other value. Empty and repeated suggestions are left out, and
+// only the first attrs["maxSuggestions"] are offered, or DefaultMaxSuggestions.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Autocomplete(fieldName string, p interface{}, attrs map[string]string, suggestions []string) []byte {
+	checkAttrs("Autocomplete", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	list := name + "-suggestions"
+
+	e := NewElement("input", attrs["label"], fieldName, p, autocompleteAttrs(attrs, list))
+
+	return []byte(`<div class="__ponzu-autocomplete ` + name + `">` + string(DOMElementSelfClose(e)) +
+		datalist(list, suggestions, attrs) + `</div>`)
+}
+
+// AutocompleteRepeater returns the []byte of an Autocomplete for each of the
+// values of a slice field, all offering the same suggestions. It also includes
+// repeat controllers (+ / -) so the values can be dynamically multiplied or
+// reduced.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func AutocompleteRepeater(fieldName string, p interface{}, attrs map[string]string, suggestions []string) []byte {
+	checkAttrs("AutocompleteRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	vals := defaultValues(p, ValuesFromStructField(fieldName, p), attrs)
+	scope := TagNameFromStructField(fieldName, p)
+	list := scope + "-suggestions"
+	inputAttrs := repeatItemAttrs(autocompleteAttrs(attrs, list))
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(repeatOpen("__ponzu-autocomplete-repeat "+scope, attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to AutocompleteRepeater buffer")
+		return nil
+	}
+
+	for i, val := range vals {
+		el := &Element{
+			TagName: "input",
+			Attrs:   inputAttrs,
+			Name:    TagNameFromStructFieldMulti(fieldName, i, p),
+			Data:    val,
+			ViewBuf: &bytes.Buffer{},
+		}
+
+		// only add the label to the first input in repeated list
+		if i == 0 {
+			el.Label = attrs["label"]
+		}
+
+		_, err = view.Write(DOMElementSelfClose(el))
+		if err != nil {
+			log.Println("Error writing DOMElementSelfClose to AutocompleteRepeater buffer")
+			return nil
+		}
+	}
+
+	// the datalist is outside of the repeated items, so that it isn't cloned
+	// along with them
+	_, err = view.WriteString(repeatClose(attrs) + datalist(list, suggestions, attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to AutocompleteRepeater buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), RepeatControllerWithOptions(fieldName, p, "input", ".input-field", repeatControllerOptions(attrs))...)
+}
+
+// autocompleteAttrs returns a copy of attrs for an input suggesting the values
+// of the datalist whose id is list
+func autocompleteAttrs(attrs map[string]string, list string) map[string]string {
+	inputAttrs := make(map[string]string, len(attrs)+2)
+	for k, v := range attrs {
+		inputAttrs[k] = v
+	}
+
+	if inputAttrs["type"] == "" {
+		inputAttrs["type"] = "text"
+	}
+	inputAttrs["list"] = list
+
+	return inputAttrs
+}
+
+// datalist returns the markup of a <datalist> whose id is id, holding the
+// suggestions which are offered, see Autocomplete
+func datalist(id string, suggestions []string, attrs map[string]string) string {
+	max := DefaultMaxSuggestions
+	if n, err := strconv.Atoi(attrs["maxSuggestions"]); err == nil && n > 0 {
+		max = n
+	}
+
+	view := `<datalist id="` + html.EscapeString(id) + `">`
+
+	var offered []string
+	for _, s := range suggestions {
+		if len(offered) == max {
+			break
+		}
+
+		if s == "" || hasString(offered, s) {
+			continue
+		}
+
+		offered = append(offered, s)
+		view += `<option value="` + html.EscapeString(s) + `"></option>`
+	}
+
+	return view + `</datalist>`
+}
@@ -0,0 +1,34 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutocomplete(t *testing.T) {
+	p := &testContact{Name: "Lovelace", Links: []string{"a", "b"}}
+	suggestions := []string{"Babbage", "", `"Hopper" <Grace>`, "Babbage", "Turing", "Lovelace"}
+
+	view := string(Autocomplete("Name", p, map[string]string{"label": "Name", "maxSuggestions": "3"}, suggestions))
+
+	if !strings.Contains(view, `list="name-suggestions"`) || !strings.Contains(view, `value="Lovelace"`) {
+		t.Errorf("Expected an input wired to the datalist, got: %s", view)
+	}
+
+	expected := `<datalist id="name-suggestions">` +
+		`<option value="Babbage"></option>` +
+		`<option value="&#34;Hopper&#34; &lt;Grace&gt;"></option>` +
+		`<option value="Turing"></option></datalist>`
+	if !strings.Contains(view, expected) {
+		t.Errorf("Expected escaped, unique and capped suggestions, got: %s", view)
+	}
+
+	if strings.Contains(view, "maxSuggestions") {
+		t.Errorf("Expected maxSuggestions not to be rendered, got: %s", view)
+	}
+
+	view = string(AutocompleteRepeater("Links", p, map[string]string{}, suggestions))
+	if strings.Count(view, `list="links-suggestions"`) != 2 || strings.Count(view, "<datalist") != 1 {
+		t.Errorf("Expected every input to share one datalist, got: %s", view)
+	}
+}
@@ -0,0 +1,350 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BlockType is one kind of item which can be added to a BlockRepeater, such as
+// a text block, an image block or a quote block
+type BlockType struct {
+	// Name identifies the block type and is submitted as the item's "type"
+	Name string
+
+	// Label is displayed on each block and when choosing a block to add
+	Label string
+
+	// Render returns the fields of a single block. The form name of every
+	// field must begin with name and a dot, e.g. name+".caption", and values
+	// holds the stored values of an existing block, keyed by the same suffix
+	// ("caption"). values is empty when rendering a new block.
+	Render func(name string, values map[string]string) []byte
+}
+
+// BlockRepeater returns the []byte of a repeatable list of heterogeneous
+// "content blocks", where each item is one of the provided block types and
+// renders the fields for its type. Adding an item first asks the editor to pick
+// a block type. Each item submits its type plus its fields under indexed names,
+// e.g. "blocks.0.type", "blocks.0.caption", which can be read back with
+// ParseBlocks.
+// When attrs["toggle"] is "true", each block also has an on/off switch so it
+// can be disabled without being deleted. The block then submits "enabled" as
+// "true" or "false" along with its fields (e.g. "blocks.0.enabled"), which is
+// stored and read back like any other value of the block, see BlockEnabled.
+// The field must be a slice of map[string]string or of structs with `json`
+// tags, and each stored item must have a "type" value.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func BlockRepeater(fieldName string, p interface{}, blocks []BlockType, attrs map[string]string) []byte {
+	checkAttrs("BlockRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	scope := TagNameFromStructField(fieldName, p)
+	field, err := fieldByPath(fieldName, p)
+	if err != nil {
+		logResolveFailure(fieldName, p, err)
+		return nil
+	}
+
+	view := &bytes.Buffer{}
+	_, err = view.WriteString(`<div class="__ponzu-blocks ` + scope + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to BlockRepeater buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to BlockRepeater buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<div class="__ponzu-block-items">`)
+	if err != nil {
+		log.Println("Error writing HTML string to BlockRepeater buffer")
+		return nil
+	}
+
+	toggle := attrs["toggle"] == "true"
+
+	if field.Kind() == reflect.Slice {
+		for i := 0; i < field.Len(); i++ {
+			name := fmt.Sprintf("%s.%d", scope, i)
+			_, err = view.WriteString(renderBlock(name, blocks, blockValues(field.Index(i)), toggle))
+			if err != nil {
+				log.Println("Error writing HTML string to BlockRepeater buffer")
+				return nil
+			}
+		}
+	}
+
+	_, err = view.WriteString(`</div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to BlockRepeater buffer")
+		return nil
+	}
+
+	// the controls to pick a block type and add a new block of that type
+	add := `<div class="__ponzu-block-add row"><div class="col s8"><select class="browser-default">`
+	for _, b := range blocks {
+		add += `<option value="` + html.EscapeString(b.Name) + `">` + html.EscapeString(b.Label) + `</option>`
+	}
+	add += `</select></div><div class="col s4"><button class="btn waves-effect waves-light">` + htmlText("block.add") + `</button></div></div>`
+
+	// a template per block type which is cloned when a new block is added
+	for _, b := range blocks {
+		add += `<template class="__ponzu-block-template" data-type="` + html.EscapeString(b.Name) + `">` +
+			renderBlock(scope+".__index__", blocks, map[string]string{"type": b.Name}, toggle) +
+			`</template>`
+	}
+
+	_, err = view.WriteString(add + `</div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to BlockRepeater buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), BlockController(fieldName, p)...)
+}
+
+// renderBlock returns the markup of a single block named name (e.g. "blocks.0")
+// holding values, with an on/off switch if toggle is true. Values of a block
+// whose type is not among blocks are kept in hidden inputs so that they are not
+// lost on save.
+func renderBlock(name string, blocks []BlockType, values map[string]string, toggle bool) string {
+	typ := values["type"]
+	var block *BlockType
+	for i := range blocks {
+		if blocks[i].Name == typ {
+			block = &blocks[i]
+			break
+		}
+	}
+
+	label := typ
+	if block != nil {
+		label = block.Label
+	}
+
+	class := "__ponzu-block card-panel"
+	if toggle && !BlockEnabled(values) {
+		class += " __ponzu-block-disabled"
+	}
+
+	view := `<div class="` + class + `" data-type="` + html.EscapeString(typ) + `">` +
+		`<input type="hidden" class="__ponzu-block-type" name="` + name + `.type" value="` + html.EscapeString(typ) + `" />` +
+		`<span class="__ponzu-block-label">` + html.EscapeString(label) + `</span>` +
+		`<button class="__ponzu-block-del right btn-flat waves-effect waves-red">-</button>`
+
+	// reserved holds the keys rendered by the block itself, not its fields
+	reserved := map[string]bool{"type": true}
+
+	if toggle {
+		reserved["enabled"] = true
+
+		enabled := BlockEnabled(values)
+		var checked string
+		if enabled {
+			checked = " checked"
+		}
+
+		view += `<div class="switch right"><label>` + htmlText("toggle.off") +
+			`<input type="checkbox" class="__ponzu-block-enabled"` + checked + ` />` +
+			`<span class="lever"></span>` + htmlText("toggle.on") + `</label></div>` +
+			`<input type="hidden" class="__ponzu-block-enabled-value" name="` + name + `.enabled" value="` +
+			fmt.Sprintf("%t", enabled) + `" />`
+	}
+
+	if block != nil && block.Render != nil {
+		fields := make(map[string]string)
+		for k, v := range values {
+			if !reserved[k] {
+				fields[k] = v
+			}
+		}
+
+		view += string(block.Render(name, fields))
+	} else {
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			if !reserved[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			view += `<input type="hidden" name="` + name + `.` + html.EscapeString(k) + `" value="` + html.EscapeString(values[k]) + `" />`
+		}
+	}
+
+	return view + `</div>`
+}
+
+// BlockEnabled reports whether the block holding values is enabled. Blocks are
+// enabled unless their "enabled" value is "false", so blocks stored before the
+// BlockRepeater's on/off switch was turned on remain enabled.
+func BlockEnabled(values map[string]string) bool {
+	return values["enabled"] != "false"
+}
+
+// blockValues returns the values of a stored block, which is either a
+// map[string]string or a struct whose fields are keyed by their `json` tags, or
+// by their names when they have none
+func blockValues(v reflect.Value) map[string]string {
+	values := make(map[string]string)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return values
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			values[fmt.Sprintf("%v", k)] = fmt.Sprintf("%v", v.MapIndex(k))
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+
+			key := t.Field(i).Name
+			if tag, ok := t.Field(i).Tag.Lookup("json"); ok {
+				key = strings.Split(tag, ",")[0]
+				if key == "-" {
+					continue
+				}
+
+				if key == "" {
+					key = t.Field(i).Name
+				}
+			}
+
+			values[key] = fmt.Sprintf("%v", v.Field(i))
+		}
+	}
+
+	return values
+}
+
+// BlockController generates the javascript to add, remove and renumber the
+// blocks of a BlockRepeater so that the indexes in the names of each block's
+// fields stay contiguous
+func BlockController(fieldName string, p interface{}) []byte {
+	scope := TagNameFromStructField(fieldName, p)
+	script := `
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-blocks.` + selectorClass(scope) + `'),
+				items = scope.find('.__ponzu-block-items'),
+				pattern = new RegExp('^' + ` + jsString(regexp.QuoteMeta(scope)) + ` + '\\.(\\d+|__index__)\\.');
+
+			var resetFieldNames = function() {
+				items.children('.__ponzu-block').each(function(i, block) {
+					var prefix = ` + jsString(scope) + ` + '.' + String(i) + '.';
+
+					$(block).find('[name]').each(function(j, el) {
+						var $el = $(el);
+						$el.attr('name', $el.attr('name').replace(pattern, prefix));
+					});
+				});
+			}
+
+			scope.on('change', '.__ponzu-block-enabled', function(e) {
+				var enabled = $(e.target).is(':checked'),
+					block = $(e.target).closest('.__ponzu-block');
+
+				block.find('.__ponzu-block-enabled-value').first().val(String(enabled));
+				block.toggleClass('__ponzu-block-disabled', !enabled);
+			});
+
+			scope.on('click', '.__ponzu-block-del', function(e) {
+				e.preventDefault();
+
+				$(e.target).closest('.__ponzu-block').remove();
+				resetFieldNames();
+			});
+
+			scope.find('.__ponzu-block-add button').on('click', function(e) {
+				e.preventDefault();
+
+				var type = scope.find('.__ponzu-block-add select').val(),
+					tmpl = scope.find('.__ponzu-block-template').filter(function() {
+						return $(this).attr('data-type') === type;
+					});
+
+				if (tmpl.length === 0) {
+					return;
+				}
+
+				items.append($(tmpl.html()));
+				resetFieldNames();
+			});
+
+			resetFieldNames();
+		});
+	</script>`
+
+	return []byte(script)
+}
+
+// ParseBlocks reconstructs the blocks submitted by a BlockRepeater from the
+// form values. Each block is returned as a map of its field values, including
+// its "type", in the order they appeared in the editor.
+func ParseBlocks(form url.Values, fieldName string) []map[string]string {
+	prefix := fieldName + "."
+	byIndex := make(map[int]map[string]string)
+
+	for key, vals := range form {
+		if !strings.HasPrefix(key, prefix) || len(vals) == 0 {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(key, prefix), ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		i, err := strconv.Atoi(parts[0])
+		if err != nil || i < 0 {
+			continue
+		}
+
+		if byIndex[i] == nil {
+			byIndex[i] = make(map[string]string)
+		}
+		byIndex[i][parts[1]] = vals[0]
+	}
+
+	indexes := make([]int, 0, len(byIndex))
+	for i := range byIndex {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	blocks := make([]map[string]string, 0, len(indexes))
+	for _, i := range indexes {
+		blocks = append(blocks, byIndex[i])
+	}
+
+	return blocks
+}
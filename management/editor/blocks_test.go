@@ -0,0 +1,117 @@
+package editor
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type testArticle struct {
+	Blocks []map[string]string `json:"blocks"`
+}
+
+var testBlocks = []BlockType{
+	{
+		Name:  "text",
+		Label: "Text",
+		Render: func(name string, values map[string]string) []byte {
+			return []byte(`<textarea name="` + name + `.body">` + values["body"] + `</textarea>`)
+		},
+	},
+	{
+		Name:  "quote",
+		Label: "Quote",
+		Render: func(name string, values map[string]string) []byte {
+			return []byte(`<input name="` + name + `.quote" value="` + values["quote"] + `" />` +
+				`<input name="` + name + `.author" value="` + values["author"] + `" />`)
+		},
+	},
+}
+
+func TestBlockRepeater(t *testing.T) {
+	p := &testArticle{Blocks: []map[string]string{
+		{"type": "text", "body": "Hello"},
+		{"type": "quote", "quote": "Be brief", "author": "Ada", "enabled": "false"},
+		{"type": "video", "src": "a.mp4"},
+	}}
+
+	view := string(BlockRepeater("Blocks", p, testBlocks, map[string]string{"label": "Blocks", "toggle": "true"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	for _, s := range []string{
+		`<input type="hidden" class="__ponzu-block-type" name="blocks.0.type" value="text" />`,
+		`<textarea name="blocks.0.body">Hello</textarea>`,
+		`<div class="__ponzu-block card-panel __ponzu-block-disabled" data-type="quote">`,
+		`name="blocks.1.enabled" value="false"`,
+		`<input name="blocks.1.author" value="Ada" />`,
+		`<input type="hidden" name="blocks.2.src" value="a.mp4" />`,
+		`<option value="quote">Quote</option>`,
+		`<button class="btn waves-effect waves-light">Add block</button>`,
+		`<template class="__ponzu-block-template" data-type="text">`,
+		`<textarea name="blocks.__index__.body"></textarea>`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	if strings.Count(view, `<label>Off<input type="checkbox" class="__ponzu-block-enabled"`) != 5 {
+		t.Errorf("Expected a switch on each block and template, got: %s", view)
+	}
+}
+
+func TestParseBlocks(t *testing.T) {
+	form := url.Values{
+		"blocks.7.type":   {"quote"},
+		"blocks.7.quote":  {"Be brief"},
+		"blocks.2.type":   {"text"},
+		"blocks.2.body":   {"Hello"},
+		"blocks.10.type":  {"text"},
+		"blocks.10.body":  {""},
+		"blocks.-1.type":  {"text"},
+		"blocks.x.type":   {"text"},
+		"blocks.3":        {"no field"},
+		"blocksx.0.type":  {"text"},
+		"blocks.4.type":   {},
+		"other.0.type":    {"text"},
+		"blocks.2.nested": {"a.b"},
+	}
+
+	want := []map[string]string{
+		{"type": "text", "body": "Hello", "nested": "a.b"},
+		{"type": "quote", "quote": "Be brief"},
+		{"type": "text", "body": ""},
+	}
+
+	if got := ParseBlocks(form, "blocks"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBlocks() = %v, want %v", got, want)
+	}
+
+	if got := ParseBlocks(url.Values{}, "blocks"); len(got) != 0 {
+		t.Errorf("Expected no blocks, got %v", got)
+	}
+}
+
+func TestBlocksRoundTrip(t *testing.T) {
+	stored := []map[string]string{
+		{"type": "quote", "quote": "Be brief", "author": "Ada", "enabled": "true"},
+		{"type": "text", "body": "Hello", "enabled": "false"},
+		{"type": "video", "src": "a.mp4", "enabled": "true"},
+	}
+
+	view := string(BlockRepeater("Blocks", &testArticle{Blocks: stored}, testBlocks, map[string]string{"toggle": "true"}))
+	view = view[:strings.Index(view, `<div class="__ponzu-block-add`)]
+
+	// submit the named fields of the rendered blocks
+	form := url.Values{}
+	field := regexp.MustCompile(`<(?:input|textarea)[^>]* name="([^"]+)"(?: value="([^"]*)")?[^>]*>(?:([^<]*)</textarea>)?`)
+	for _, m := range field.FindAllStringSubmatch(view, -1) {
+		form.Add(m[1], m[2]+m[3])
+	}
+
+	if got := ParseBlocks(form, "blocks"); !reflect.DeepEqual(got, stored) {
+		t.Errorf("ParseBlocks() = %v, want %v", got, stored)
+	}
+}
@@ -0,0 +1,520 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+)
+
+// Option is a single choice offered by a field, holding the Value which is
+// stored and the Label which is displayed for it
+type Option struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// sortedOptions returns the options map of value -> label as a slice ordered
+// by label, then by value, so that options render in a deterministic order
+func sortedOptions(options map[string]string) []Option {
+	opts := make([]Option, 0, len(options))
+	for k, v := range options {
+		opts = append(opts, Option{Value: k, Label: v})
+	}
+
+	sort.Slice(opts, func(i, j int) bool {
+		if opts[i].Label == opts[j].Label {
+			return opts[i].Value < opts[j].Value
+		}
+
+		return opts[i].Label < opts[j].Label
+	})
+
+	return opts
+}
+
+// CardOption is a single selectable card rendered by RadioCards. Image is
+// optional, and when empty the card only displays its Label
+type CardOption struct {
+	Value string
+	Label string
+	Image string
+}
+
+// RadioCards returns the []byte of a group of <input type="radio"> HTML elements
+// each presented as a card (an optional image plus a label), and is useful for
+// visually rich single-choice fields such as layout or theme pickers. The card
+// matching the stored value is pre-selected, and the field submits exactly like
+// a group of radio inputs.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func RadioCards(fieldName string, p interface{}, options []CardOption, attrs map[string]string) []byte {
+	checkAttrs("RadioCards", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+
+	html := &bytes.Buffer{}
+	_, err := html.WriteString(`<div class="__ponzu-radio-cards ` + name + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to RadioCards buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = html.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to RadioCards buffer")
+			return nil
+		}
+	}
+
+	_, err = html.WriteString(`<div class="row">`)
+	if err != nil {
+		log.Println("Error writing HTML string to RadioCards buffer")
+		return nil
+	}
+
+	for i, opt := range options {
+		_, err = html.WriteString(radioCard(name, i, opt, opt.Value == value))
+		if err != nil {
+			log.Println("Error writing HTML string to RadioCards buffer")
+			return nil
+		}
+	}
+
+	_, err = html.WriteString(`</div></div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to RadioCards buffer")
+		return nil
+	}
+
+	return html.Bytes()
+}
+
+func radioCard(name string, i int, opt CardOption, checked bool) string {
+	id := fmt.Sprintf("%s-card-%d", name, i)
+
+	var isChecked string
+	if checked {
+		isChecked = ` checked`
+	}
+
+	var img string
+	if opt.Image != "" {
+		img = `<div class="card-image"><img src="` + html.EscapeString(opt.Image) +
+			`" alt="` + html.EscapeString(opt.Label) + `"/></div>`
+	}
+
+	return `<label class="__ponzu-radio-card col s6 m4" for="` + id + `">` +
+		`<input type="radio" id="` + id + `" name="` + name + `" value="` +
+		html.EscapeString(opt.Value) + `"` + isChecked + ` />` +
+		`<div class="card">` + img +
+		`<div class="card-content"><span>` + html.EscapeString(opt.Label) + `</span></div>` +
+		`</div></label>`
+}
+
+// Segmented returns the []byte of a segmented control, a row of toggle buttons
+// of which exactly one is selected, backed by a hidden <input> HTML element
+// holding the selected option's value. It is a compact alternative to a group
+// of radio inputs for small bounded choices, such as a rating from 1 to 5. The
+// stored value is pre-selected, and the control can be used from the keyboard
+// with the arrow keys, Home and End.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Segmented(fieldName string, p interface{}, options []Option, attrs map[string]string) []byte {
+	checkAttrs("Segmented", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-segmented ` + name + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to Segmented buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to Segmented buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<input type="hidden" name="` + name + `" value="` + html.EscapeString(value) + `" />` +
+		`<div class="__ponzu-segments" role="radiogroup" aria-label="` + html.EscapeString(attrs["label"]) + `">`)
+	if err != nil {
+		log.Println("Error writing HTML string to Segmented buffer")
+		return nil
+	}
+
+	for i, opt := range options {
+		selected := opt.Value == value
+
+		// only the selected button, or the first if none is, can be tabbed to
+		tabindex := "-1"
+		if selected || (i == 0 && !hasOption(options, value)) {
+			tabindex = "0"
+		}
+
+		class := "btn-flat waves-effect"
+		if selected {
+			class += " active"
+		}
+
+		_, err = view.WriteString(fmt.Sprintf(
+			`<button type="button" class="%s" role="radio" aria-checked="%t" tabindex="%s" data-value="%s">%s</button>`,
+			class, selected, tabindex, html.EscapeString(opt.Value), html.EscapeString(opt.Label),
+		))
+		if err != nil {
+			log.Println("Error writing HTML string to Segmented buffer")
+			return nil
+		}
+	}
+
+	script := `</div></div>
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-segmented.` + selectorClass(name) + `'),
+				input = scope.find('input[type=hidden]');
+
+			var buttons = function() {
+				return scope.find('.__ponzu-segments button');
+			}
+
+			var choose = function(btn) {
+				buttons().removeClass('active').attr({'aria-checked': 'false', tabindex: '-1'});
+				$(btn).addClass('active').attr({'aria-checked': 'true', tabindex: '0'});
+				input.val($(btn).attr('data-value')).trigger('change');
+			}
+
+			scope.on('click', '.__ponzu-segments button', function(e) {
+				e.preventDefault();
+				choose(this);
+			});
+
+			scope.on('keydown', '.__ponzu-segments button', function(e) {
+				var all = buttons(),
+					i = all.index(this),
+					next;
+
+				switch (e.which) {
+				case 37: // left
+				case 38: // up
+					next = (i - 1 + all.length) % all.length;
+					break;
+				case 39: // right
+				case 40: // down
+					next = (i + 1) % all.length;
+					break;
+				case 36: // home
+					next = 0;
+					break;
+				case 35: // end
+					next = all.length - 1;
+					break;
+				default:
+					return;
+				}
+
+				e.preventDefault();
+				choose(all.get(next));
+				all.get(next).focus();
+			});
+		});
+	</script>`
+
+	_, err = view.WriteString(script)
+	if err != nil {
+		log.Println("Error writing HTML string to Segmented buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// CheckboxGroup returns the []byte of a group of <input type="checkbox"> HTML
+// elements, one per option, for fields where any number of options can be
+// chosen, such as categories or feature flags. The options whose values are
+// stored are pre-checked, and the checked values are submitted under indexed
+// names, e.g. "categories.0", "categories.1", so they round-trip like the values
+// of the other repeaters. Options are displayed in order of their labels, and
+// links to select all or none of them are included.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func CheckboxGroup(fieldName string, p interface{}, attrs, options map[string]string) []byte {
+	checkAttrs("CheckboxGroup", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+
+	checked := make(map[string]bool)
+	for _, v := range ValuesFromStructField(fieldName, p) {
+		if v != "" {
+			checked[v] = true
+		}
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-checkbox-group ` + name + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to CheckboxGroup buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to CheckboxGroup buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<div class="__ponzu-checkbox-group-toggle">` +
+		`<a href="#" data-select="all">` + htmlText("checkbox.all") + `</a> / ` +
+		`<a href="#" data-select="none">` + htmlText("checkbox.none") + `</a></div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to CheckboxGroup buffer")
+		return nil
+	}
+
+	// only the checked options are named, with contiguous indexes, which the
+	// script below maintains as options are checked and unchecked
+	n := 0
+	for i, opt := range sortedOptions(options) {
+		id := fmt.Sprintf("%s-checkbox-%d", name, i)
+
+		var attr string
+		if checked[opt.Value] {
+			attr = fmt.Sprintf(` name="%s.%d" checked`, name, n)
+			n++
+		}
+
+		_, err = view.WriteString(`<p class="col s6">` +
+			`<input type="checkbox" id="` + id + `" value="` + html.EscapeString(opt.Value) + `"` + attr + ` />` +
+			`<label for="` + id + `">` + html.EscapeString(opt.Label) + `</label></p>`)
+		if err != nil {
+			log.Println("Error writing HTML string to CheckboxGroup buffer")
+			return nil
+		}
+	}
+
+	script := `</div><div class="clear padding">&nbsp;</div>
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-checkbox-group.` + selectorClass(name) + `');
+
+			var resetFieldNames = function() {
+				var i = 0;
+				scope.find('input[type=checkbox]').each(function(j, el) {
+					if (el.checked) {
+						$(el).attr('name', ` + jsString(name) + ` + '.' + String(i));
+						i++;
+					} else {
+						$(el).removeAttr('name');
+					}
+				});
+			}
+
+			scope.on('change', 'input[type=checkbox]', resetFieldNames);
+
+			scope.find('.__ponzu-checkbox-group-toggle a').on('click', function(e) {
+				e.preventDefault();
+
+				var all = $(this).attr('data-select') === 'all';
+				scope.find('input[type=checkbox]').prop('checked', all);
+				resetFieldNames();
+			});
+		});
+	</script>`
+
+	_, err = view.WriteString(script)
+	if err != nil {
+		log.Println("Error writing HTML string to CheckboxGroup buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// RadioGroup returns the []byte of a group of <input type="radio"> HTML elements
+// sharing the field's name, one per option, for single-choice fields with too
+// few options to warrant a dropdown. A "None" option which stores an empty
+// string comes first, like the reset option of SelectRepeater, followed by the
+// options in order of their labels. The option matching the stored value is
+// pre-selected, or "None" if no option matches.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func RadioGroup(fieldName string, p interface{}, attrs, options map[string]string) []byte {
+	checkAttrs("RadioGroup", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+
+	opts := append([]Option{{Value: "", Label: text("radio.none")}}, sortedOptions(options)...)
+	if !hasOption(opts, value) {
+		value = ""
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-radio-group ` + name + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to RadioGroup buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to RadioGroup buffer")
+			return nil
+		}
+	}
+
+	for i, opt := range opts {
+		id := fmt.Sprintf("%s-radio-%d", name, i)
+
+		var checked string
+		if opt.Value == value {
+			checked = ` checked`
+		}
+
+		_, err = view.WriteString(`<p class="col s6">` +
+			`<input type="radio" id="` + id + `" name="` + name + `" value="` + html.EscapeString(opt.Value) + `"` + checked + ` />` +
+			`<label for="` + id + `">` + html.EscapeString(opt.Label) + `</label></p>`)
+		if err != nil {
+			log.Println("Error writing HTML string to RadioGroup buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`</div><div class="clear padding">&nbsp;</div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to RadioGroup buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// MultiSelect returns the []byte of a single <select multiple> HTML element for
+// fields where any number of options can be chosen, as a more compact
+// alternative to a SelectRepeater or CheckboxGroup. The options whose values
+// are stored are pre-selected, and the selected values are submitted under
+// indexed names, e.g. "categories.0", "categories.1", so they round-trip like
+// the values of the repeaters. Options are displayed in order of their labels.
+// A stored value which is no longer one of the options is kept, selected, as an
+// option labeled as unavailable, so that saving doesn't drop it unless it is
+// deselected.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func MultiSelect(fieldName string, p interface{}, attrs, options map[string]string) []byte {
+	checkAttrs("MultiSelect", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	opts := sortedOptions(options)
+
+	selected := make(map[string]bool)
+	var stale []Option
+	for _, v := range defaultValues(p, ValuesFromStructField(fieldName, p), attrs) {
+		if v == "" || selected[v] {
+			continue
+		}
+
+		selected[v] = true
+		if !hasOption(opts, v) {
+			stale = append(stale, Option{Value: v, Label: text("select.unavailable", "value", v)})
+		}
+	}
+	opts = append(opts, stale...)
+
+	var size string
+	if attrs["size"] != "" {
+		size = ` size="` + html.EscapeString(attrs["size"]) + `"`
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-multi-select ` + name + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to MultiSelect buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to MultiSelect buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<select multiple class="browser-default __ponzu-multi-select-input"` + size + `>`)
+	if err != nil {
+		log.Println("Error writing HTML string to MultiSelect buffer")
+		return nil
+	}
+
+	// the select itself is never named, instead the selected values are held
+	// by hidden inputs with contiguous indexes, which the script below
+	// maintains as options are selected and deselected
+	var values string
+	n := 0
+	for _, opt := range opts {
+		var attr string
+		if selected[opt.Value] {
+			attr = ` selected`
+			values += fmt.Sprintf(`<input type="hidden" name="%s.%d" value="%s" />`, name, n, html.EscapeString(opt.Value))
+			n++
+		}
+
+		_, err = view.WriteString(`<option value="` + html.EscapeString(opt.Value) + `"` + attr + `>` +
+			html.EscapeString(opt.Label) + `</option>`)
+		if err != nil {
+			log.Println("Error writing HTML string to MultiSelect buffer")
+			return nil
+		}
+	}
+
+	script := `</select><span class="__ponzu-multi-select-values">` + values + `</span></div>
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-multi-select.` + selectorClass(name) + `'),
+				values = scope.find('.__ponzu-multi-select-values');
+
+			scope.find('.__ponzu-multi-select-input').on('change', function() {
+				values.empty();
+				$(this).find('option:selected').each(function(i, opt) {
+					values.append($('<input type="hidden" />')
+						.attr('name', ` + jsString(name) + ` + '.' + String(i))
+						.val(opt.value));
+				});
+			});
+		});
+	</script>`
+
+	_, err = view.WriteString(script)
+	if err != nil {
+		log.Println("Error writing HTML string to MultiSelect buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// hasOption reports whether value is the Value of one of options
+func hasOption(options []Option, value string) bool {
+	for _, opt := range options {
+		if opt.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
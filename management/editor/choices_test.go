@@ -0,0 +1,173 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCheckboxGroup(t *testing.T) {
+	options := map[string]string{"go": "Go", "js": "JavaScript", "c": "C & <C++>"}
+
+	p := &testContact{Links: []string{"js", "c"}}
+	view := string(CheckboxGroup("Links", p, map[string]string{"label": "Languages"}, options))
+
+	if strings.Count(view, `type="checkbox"`) != 3 || strings.Count(view, " checked") != 2 {
+		t.Errorf("Expected 3 checkboxes with 2 checked, got: %s", view)
+	}
+
+	// options are ordered by label, and checked ones are named contiguously
+	c := strings.Index(view, `value="c" name="links.0" checked`)
+	g := strings.Index(view, `value="go" />`)
+	j := strings.Index(view, `value="js" name="links.1" checked`)
+	if c < 0 || g < c || j < g {
+		t.Errorf("Expected ordered options with contiguous names, got: %s", view)
+	}
+
+	if !strings.Contains(view, "C &amp; &lt;C++&gt;") {
+		t.Errorf("Expected the option label to be escaped, got: %s", view)
+	}
+
+	p = &testContact{}
+	view = string(CheckboxGroup("Links", p, map[string]string{}, options))
+	if strings.Contains(view, " checked") || strings.Contains(view, `name="links.`) {
+		t.Errorf("Expected nothing checked for no stored values, got: %s", view)
+	}
+}
+
+func TestRadioGroup(t *testing.T) {
+	options := map[string]string{"b": "Beta", "a": `Alpha "1"`}
+
+	p := &testContact{Name: "b"}
+	view := string(RadioGroup("Name", p, map[string]string{"label": "Channel"}, options))
+
+	if strings.Count(view, `type="radio"`) != 3 || strings.Count(view, ` checked`) != 1 {
+		t.Errorf("Expected 3 radios with 1 checked, got: %s", view)
+	}
+
+	if !strings.Contains(view, `name="name" value="b" checked`) {
+		t.Errorf("Expected the stored value to be checked, got: %s", view)
+	}
+
+	none := strings.Index(view, ">None<")
+	alpha := strings.Index(view, ">Alpha &#34;1&#34;<")
+	beta := strings.Index(view, ">Beta<")
+	if none < 0 || alpha < none || beta < alpha {
+		t.Errorf("Expected None first then options ordered by label, got: %s", view)
+	}
+
+	p = &testContact{Name: "unknown"}
+	view = string(RadioGroup("Name", p, map[string]string{}, options))
+	if !strings.Contains(view, `name="name" value="" checked`) {
+		t.Errorf("Expected None to be checked for an unknown value, got: %s", view)
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	options := map[string]string{"go": "Go", "js": "JavaScript", "c": "C & <C++>"}
+
+	p := &testContact{Links: []string{"js", "cobol", "c"}}
+	view := string(MultiSelect("Links", p, map[string]string{"label": "Languages"}, options))
+
+	// options are ordered by label, then the stored values which are no
+	// longer options
+	order := []string{
+		`<option value="c" selected>C &amp; &lt;C++&gt;</option>`,
+		`<option value="go">Go</option>`,
+		`<option value="js" selected>JavaScript</option>`,
+		`<option value="cobol" selected>cobol (unavailable)</option>`,
+	}
+	last := -1
+	for _, opt := range order {
+		i := strings.Index(view, opt)
+		if i <= last {
+			t.Errorf("Expected %s after the previous options, got: %s", opt, view)
+		}
+		last = i
+	}
+
+	for i, v := range []string{"c", "js", "cobol"} {
+		if !strings.Contains(view, fmt.Sprintf(`<input type="hidden" name="links.%d" value="%s" />`, i, v)) {
+			t.Errorf("Expected %q to be submitted as links.%d, got: %s", v, i, view)
+		}
+	}
+
+	if strings.Contains(view, `<select multiple class="browser-default __ponzu-multi-select-input" name=`) {
+		t.Errorf("Expected the select to be unnamed, got: %s", view)
+	}
+}
+
+func TestRadioCards(t *testing.T) {
+	options := []CardOption{
+		{Value: "wide", Label: "Wide", Image: "/img/wide.png?a=1&b=2"},
+		{Value: `"tall"`, Label: `Tall & <narrow>`},
+		{Value: "grid", Label: "Grid"},
+	}
+
+	p := &testContact{Name: `"tall"`}
+	view := string(RadioCards("Name", p, options, map[string]string{"label": "Layout"}))
+
+	if strings.Count(view, `type="radio"`) != 3 || strings.Count(view, " checked") != 1 {
+		t.Errorf("Expected 3 radios with 1 checked, got: %s", view)
+	}
+
+	for _, want := range []string{
+		`<input type="radio" id="name-card-1" name="name" value="&#34;tall&#34;" checked />`,
+		`<span>Tall &amp; &lt;narrow&gt;</span>`,
+		`<img src="/img/wide.png?a=1&amp;b=2" alt="Wide"/>`,
+		`<label class="active">Layout</label>`,
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %s, got: %s", want, view)
+		}
+	}
+
+	if strings.Count(view, "<img") != 1 {
+		t.Errorf("Expected only the card with an image to show one, got: %s", view)
+	}
+
+	view = string(RadioCards("Name", &testContact{Name: "missing"}, options, map[string]string{}))
+	if strings.Contains(view, " checked") || strings.Contains(view, "<label class=\"active\">") {
+		t.Errorf("Expected nothing checked for an unknown value, and no label, got: %s", view)
+	}
+
+	view = string(RadioCards("Name", p, nil, map[string]string{}))
+	if view != `<div class="__ponzu-radio-cards name input-field col s12"><div class="row"></div></div>` {
+		t.Errorf("Expected an empty group without choices, got: %s", view)
+	}
+}
+
+func TestSegmented(t *testing.T) {
+	options := []Option{{Value: "1", Label: "One"}, {Value: `"2"`, Label: `Two & <b>`}, {Value: "3", Label: "Three"}}
+
+	p := &testContact{Name: `"2"`}
+	view := string(Segmented("Name", p, options, map[string]string{"label": `Rating "stars"`}))
+	markup := view[:strings.Index(view, "<script>")]
+
+	for _, want := range []string{
+		`<input type="hidden" name="name" value="&#34;2&#34;" />`,
+		`<div class="__ponzu-segments" role="radiogroup" aria-label="Rating &#34;stars&#34;">`,
+		`<button type="button" class="btn-flat waves-effect" role="radio" aria-checked="false" tabindex="-1" data-value="1">One</button>`,
+		`<button type="button" class="btn-flat waves-effect active" role="radio" aria-checked="true" tabindex="0" data-value="&#34;2&#34;">Two &amp; &lt;b&gt;</button>`,
+	} {
+		if !strings.Contains(markup, want) {
+			t.Errorf("Expected %s, got: %s", want, markup)
+		}
+	}
+
+	if strings.Count(markup, `aria-checked="true"`) != 1 || strings.Count(markup, `tabindex="0"`) != 1 {
+		t.Errorf("Expected exactly one selected button to be tabbable, got: %s", markup)
+	}
+
+	// without a stored option, nothing is selected and the first button can
+	// be tabbed to
+	view = string(Segmented("Name", &testContact{Name: "9"}, options, map[string]string{}))
+	if strings.Contains(view, `aria-checked="true"`) || !strings.Contains(view, `tabindex="0" data-value="1">`) {
+		t.Errorf("Expected nothing selected and the first button tabbable, got: %s", view)
+	}
+
+	view = string(Segmented("Name", &testContact{Name: "9"}, nil, map[string]string{}))
+	if strings.Contains(view, "<button") || !strings.Contains(view, `<input type="hidden" name="name" value="9" />`) {
+		t.Errorf("Expected no buttons without choices, keeping the stored value, got: %s", view)
+	}
+}
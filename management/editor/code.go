@@ -0,0 +1,275 @@
+package editor
+
+import (
+	"bytes"
+	"html"
+	"log"
+)
+
+// codeModes are the languages a Code editor can highlight with attrs["mode"]
+var codeModes = map[string]bool{
+	"json": true,
+	"yaml": true,
+	"html": true,
+}
+
+// DefaultCodeMode is the mode of a Code editor without attrs["mode"]
+const DefaultCodeMode = "json"
+
+// Code returns the []byte of a code editor for source such as JSON, YAML or
+// HTML, with syntax highlighting and line numbers. attrs["mode"] is the
+// language of the source, out of json, yaml and html, and defaults to
+// DefaultCodeMode. The stored source is loaded into the editor, and is
+// submitted under the field's name through a hidden input bound to it. JSON is
+// validated whenever the editor loses focus, and the editor can't be saved
+// while it has a syntax error. Without javascript, or if the editor fails to
+// start, the field is a plain textarea.
+// The field should be a string.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Code(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Code", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+
+	mode := attrs["mode"]
+	if mode == "" {
+		mode = DefaultCodeMode
+	} else if !codeModes[mode] {
+		logf("editor: unknown Code mode, source not highlighted", "field", fieldName, "mode", mode)
+	}
+
+	sourceAttrs := make(map[string]string, len(attrs)+4)
+	for k, v := range attrs {
+		sourceAttrs[k] = v
+	}
+	sourceAttrs["spellcheck"] = "false"
+	sourceAttrs["autocomplete"] = "off"
+	if sourceAttrs["wrap"] == "" {
+		sourceAttrs["wrap"] = "off"
+	}
+	if sourceAttrs["rows"] == "" {
+		sourceAttrs["rows"] = "12"
+	}
+	if sourceAttrs["class"] != "" {
+		sourceAttrs["class"] += " __ponzu-code-source"
+	} else {
+		sourceAttrs["class"] = "__ponzu-code-source"
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-code ` + name + ` col s12" data-mode="` + html.EscapeString(mode) + `">`)
+	if err != nil {
+		log.Println("Error writing HTML string to Code buffer")
+		return nil
+	}
+
+	// the textarea keeps the field's name until the script binds the hidden
+	// input to it, so that it is submitted by itself without javascript
+	_, err = view.Write(DOMElement(NewElement("textarea", attrs["label"], fieldName, p, sourceAttrs)))
+	if err != nil {
+		log.Println("Error writing HTML string to Code buffer")
+		return nil
+	}
+
+	_, err = view.WriteString(`<input type="hidden" class="__ponzu-code-value" />` +
+		`<span class="__ponzu-code-error red-text" hidden></span></div>` + codeScript())
+	if err != nil {
+		log.Println("Error writing HTML string to Code buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// codeScript returns the script which turns every Code field of the page into
+// an editor, highlighting its source as it is edited. A field whose editor
+// fails to start is left a plain textarea.
+func codeScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuCode) {
+			return;
+		}
+		window.__ponzuCode = true;
+
+		var message = ` + jsString(text("code.invalidJSON")) + `;
+
+		// modes are the tokens highlighted in each mode, tried in order
+		var modes = {
+			json: [
+				['key', /"(?:[^"\\\n]|\\.)*"(?=\s*:)/],
+				['string', /"(?:[^"\\\n]|\\.)*"/],
+				['number', /-?\b\d+(?:\.\d+)?(?:[eE][+-]?\d+)?\b/],
+				['literal', /\b(?:true|false|null)\b/],
+				['punctuation', /[{}\[\],:]/]
+			],
+			yaml: [
+				['comment', /(?:^|[ \t])#.*/],
+				['key', /[\w.-]+(?=[ \t]*:(?:\s|$))/],
+				['string', /"(?:[^"\\\n]|\\.)*"|'(?:[^'\n]|'')*'/],
+				['number', /-?\b\d+(?:\.\d+)?\b/],
+				['literal', /\b(?:true|false|null|yes|no)\b|~/],
+				['punctuation', /-(?=\s)|[:{}\[\],|>]/]
+			],
+			html: [
+				['comment', /<!--[\s\S]*?-->/],
+				['tag', /<\/?[A-Za-z][\w:-]*|\/?>/],
+				['attr', /[\w:-]+(?==)/],
+				['string', /"[^"]*"|'[^']*'/],
+				['entity', /&#?\w+;/]
+			]
+		};
+
+		var escape = function(s) {
+			return s.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
+		}
+
+		// highlight returns the escaped HTML of source, with each token of
+		// rules wrapped in a span classed by its kind
+		var highlight = function(source, rules) {
+			if (!rules) {
+				return escape(source);
+			}
+
+			var re = new RegExp($.map(rules, function(rule) {
+					return '(' + rule[1].source + ')';
+				}).join('|'), 'gm'),
+				out = '', last = 0, m;
+
+			while ((m = re.exec(source)) !== null) {
+				if (m[0] === '') {
+					re.lastIndex++;
+					continue;
+				}
+
+				for (var i = 1; i < m.length; i++) {
+					if (m[i] !== undefined) {
+						out += escape(source.slice(last, m.index)) +
+							'<span class="__ponzu-code-' + rules[i - 1][0] + '">' + escape(m[0]) + '</span>';
+						break;
+					}
+				}
+				last = re.lastIndex;
+			}
+
+			return out + escape(source.slice(last));
+		}
+
+		// validate flags the source of a JSON editor when it isn't valid JSON,
+		// and reports whether it is
+		var validate = function(editor) {
+			var source = editor.find('.__ponzu-code-source'),
+				error = editor.find('.__ponzu-code-error'),
+				value = source.val(),
+				problem = '';
+
+			if (editor.attr('data-mode') === 'json' && $.trim(value) !== '') {
+				try {
+					JSON.parse(value);
+				} catch (err) {
+					problem = message.replace('{error}', err.message);
+				}
+			}
+
+			source.get(0).setCustomValidity(problem);
+			source.toggleClass('invalid', problem !== '');
+			error.text(problem).prop('hidden', problem === '');
+
+			return problem === '';
+		}
+
+		var init = function(editor) {
+			var source = editor.find('.__ponzu-code-source'),
+				value = editor.find('.__ponzu-code-value'),
+				rules = modes[editor.attr('data-mode')],
+				lines = $('<pre class="__ponzu-code-lines" aria-hidden="true"></pre>'),
+				code = $('<code></code>');
+
+			source.wrap('<div class="__ponzu-code-editor"><div class="__ponzu-code-area"></div></div>');
+			source.before($('<pre class="__ponzu-code-highlight" aria-hidden="true"></pre>').append(code));
+			source.parent().before(lines);
+
+			var render = function() {
+				var src = source.val(),
+					n = src.split('\n').length,
+					numbers = [];
+
+				for (var i = 1; i <= n; i++) {
+					numbers.push(i);
+				}
+
+				// a trailing newline needs a line of its own to be scrolled to
+				code.html(highlight(src, rules) + '\n');
+				lines.text(numbers.join('\n') + '\n');
+				value.val(src);
+				scroll();
+			}
+
+			var scroll = function() {
+				code.parent().scrollTop(source.scrollTop()).scrollLeft(source.scrollLeft());
+				lines.scrollTop(source.scrollTop());
+			}
+
+			source.on('input', render).on('scroll', scroll).on('blur', function() {
+				validate(editor);
+			});
+
+			render();
+
+			// bind the hidden input last, once nothing else can fail
+			value.attr('name', source.attr('name')).prop('required', source.prop('required'));
+			source.removeAttr('name');
+			editor.addClass('__ponzu-code-ready');
+		}
+
+		// fallback leaves editor a plain textarea holding the field's name
+		var fallback = function(editor) {
+			var source = editor.find('.__ponzu-code-source'),
+				value = editor.find('.__ponzu-code-value');
+
+			editor.removeClass('__ponzu-code-ready');
+			editor.find('.__ponzu-code-lines, .__ponzu-code-highlight').remove();
+			if (!source.attr('name') && value.attr('name')) {
+				source.attr('name', value.attr('name'));
+			}
+			value.removeAttr('name').prop('required', false);
+		}
+
+		$('form').on('submit', function(e) {
+			var invalid = null;
+			$(this).find('.__ponzu-code[data-mode="json"]').each(function() {
+				var editor = $(this);
+				if (!validate(editor) && !invalid) {
+					invalid = editor;
+				}
+			});
+
+			if (!invalid) {
+				return;
+			}
+
+			e.preventDefault();
+			e.stopImmediatePropagation();
+
+			Materialize.toast(invalid.find('.__ponzu-code-error').text(), 4000);
+			invalid.find('.__ponzu-code-source').focus();
+		});
+
+		$('.__ponzu-code').each(function() {
+			var editor = $(this);
+			try {
+				init(editor);
+			} catch (err) {
+				fallback(editor);
+				if (window.console) {
+					console.error(err);
+				}
+			}
+		});
+	});
+</script>
+`
+}
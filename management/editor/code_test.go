@@ -0,0 +1,43 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+type testSettings struct {
+	Config string `json:"config"`
+}
+
+func TestCode(t *testing.T) {
+	p := &testSettings{Config: `{"a": "<b>"}`}
+
+	view := string(Code("Config", p, map[string]string{"label": "Config"}))
+	if !strings.Contains(view, `<div class="__ponzu-code config col s12" data-mode="json">`) {
+		t.Errorf("Expected the json mode by default, got: %s", view)
+	}
+
+	markup := view[:strings.Index(view, "<script>")]
+	if !strings.Contains(markup, `>{&#34;a&#34;: &#34;&lt;b&gt;&#34;}</textarea>`) {
+		t.Errorf("Expected the stored source to be escaped into the textarea, got: %s", markup)
+	}
+
+	// the textarea holds the name until the script binds the hidden input
+	if strings.Count(markup, `name="`) != 1 || !strings.Contains(markup, `name="config"`) ||
+		!strings.Contains(markup, `<input type="hidden" class="__ponzu-code-value" />`) {
+		t.Errorf("Expected only the textarea to be named, got: %s", markup)
+	}
+
+	for _, attr := range []string{`class="__ponzu-code-source"`, `spellcheck="false"`, `wrap="off"`, `rows="12"`} {
+		if !strings.Contains(markup, attr) {
+			t.Errorf("Expected %s on the textarea, got: %s", attr, markup)
+		}
+	}
+
+	view = string(Code("Config", p, map[string]string{"mode": "yaml", "rows": "4", "class": "wide"}))
+	markup = view[:strings.Index(view, "<script>")]
+	if !strings.Contains(markup, `data-mode="yaml"`) || strings.Contains(markup, ` mode=`) ||
+		!strings.Contains(markup, `rows="4"`) || !strings.Contains(markup, `class="wide __ponzu-code-source"`) {
+		t.Errorf("Expected the yaml mode and the textarea attrs, got: %s", markup)
+	}
+}
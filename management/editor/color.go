@@ -0,0 +1,177 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// colorPattern matches a hex color, e.g. "#ff8800" or "#f80". It is valid both
+// as a Go regexp and as the pattern attribute of an HTML input.
+const colorPattern = `#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})`
+
+var rxColor = regexp.MustCompile(`^` + colorPattern + `$`)
+
+// Color returns the []byte of a color picker alongside an <input> HTML element
+// showing its hex code, e.g. "#ff8800", which are kept in sync so that a color
+// can either be picked or typed. The typed code is marked invalid, and the form
+// can't be submitted, unless it is a valid hex color. Check the same constraint
+// on the server with ValidateColor, since client-side checks can be bypassed.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Color(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Color", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	e := NewElement("input", attrs["label"], fieldName, p, colorAttrs(attrs))
+
+	return []byte(`<div class="__ponzu-color ` + name + `">` + colorPicker(e.Data) +
+		string(DOMElementSelfClose(e)) + `</div>` + colorScript)
+}
+
+// ColorRepeater returns the []byte of a Color for each of the colors of a slice
+// field, such as a palette. It also includes repeat controllers (+ / -) so the
+// colors can be dynamically multiplied or reduced.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func ColorRepeater(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("ColorRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	vals := defaultValues(p, ValuesFromStructField(fieldName, p), attrs)
+	scope := TagNameFromStructField(fieldName, p)
+	inputAttrs := repeatItemAttrs(colorAttrs(attrs))
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(repeatOpen("__ponzu-color-repeat "+scope, attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to ColorRepeater buffer")
+		return nil
+	}
+
+	for i, val := range vals {
+		el := &Element{
+			TagName: "input",
+			Attrs:   inputAttrs,
+			Name:    TagNameFromStructFieldMulti(fieldName, i, p),
+			Data:    val,
+			ViewBuf: &bytes.Buffer{},
+		}
+
+		// only add the label to the first input in repeated list
+		if i == 0 {
+			el.Label = attrs["label"]
+		}
+
+		_, err = view.WriteString(`<div class="__ponzu-color">` + colorPicker(val) + string(DOMElementSelfClose(el)) + `</div>`)
+		if err != nil {
+			log.Println("Error writing HTML string to ColorRepeater buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(repeatClose(attrs) + colorScript)
+	if err != nil {
+		log.Println("Error writing HTML string to ColorRepeater buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), RepeatControllerWithOptions(fieldName, p, "input.__ponzu-color-value", "div.__ponzu-color", repeatControllerOptions(attrs))...)
+}
+
+// colorAttrs returns a copy of attrs for the hex code input of a Color
+func colorAttrs(attrs map[string]string) map[string]string {
+	inputAttrs := make(map[string]string, len(attrs)+4)
+	for k, v := range attrs {
+		inputAttrs[k] = v
+	}
+
+	inputAttrs["type"] = "text"
+	inputAttrs["pattern"] = colorPattern
+	if inputAttrs["class"] != "" {
+		inputAttrs["class"] += " __ponzu-color-value"
+	} else {
+		inputAttrs["class"] = "__ponzu-color-value"
+	}
+	if inputAttrs["placeholder"] == "" {
+		inputAttrs["placeholder"] = "#000000"
+	}
+	if inputAttrs["title"] == "" {
+		inputAttrs["title"] = "A hex color, e.g. #ff8800"
+	}
+
+	return inputAttrs
+}
+
+// colorPicker returns the markup of the color picker of a Color holding value.
+// The picker is marked with data-ponzu-display, so that it is never named by
+// RepeatController.
+func colorPicker(value string) string {
+	return `<input type="color" class="__ponzu-color-picker" data-ponzu-display="true" value="` + pickerColor(value) + `" />`
+}
+
+// pickerColor returns value as the value of a color picker, which only accepts
+// lowercase six digit hex colors, or black if value isn't a valid hex color
+func pickerColor(value string) string {
+	if !rxColor.MatchString(value) {
+		return "#000000"
+	}
+
+	value = strings.ToLower(value)
+	if len(value) == 4 {
+		value = "#" + strings.Repeat(value[1:2], 2) + strings.Repeat(value[2:3], 2) + strings.Repeat(value[3:4], 2)
+	}
+
+	return value
+}
+
+// ValidateColor returns an error unless s is a hex color of three or six
+// digits, e.g. "#ff8800" or "#f80"
+func ValidateColor(s string) error {
+	if !rxColor.MatchString(s) {
+		return fmt.Errorf("%q is not a valid hex color, e.g. #ff8800", s)
+	}
+
+	return nil
+}
+
+// colorScript keeps the picker and the hex code of every Color of the page in
+// sync, including those added by RepeatController
+const colorScript = `
+<script>
+	$(function() {
+		if (window.__ponzuColor) {
+			return;
+		}
+		window.__ponzuColor = true;
+
+		var rx = /^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$/;
+
+		$(document).on('input change', '.__ponzu-color-picker', function() {
+			$(this).closest('.__ponzu-color').find('.__ponzu-color-value').val(this.value).removeClass('invalid');
+		});
+
+		$(document).on('input change', '.__ponzu-color-value', function() {
+			var v = $.trim(this.value),
+				valid = v === '' || rx.test(v);
+
+			$(this).toggleClass('invalid', !valid);
+			if (!valid || v === '') {
+				return;
+			}
+
+			if (v.length === 4) {
+				v = '#' + v.charAt(1) + v.charAt(1) + v.charAt(2) + v.charAt(2) + v.charAt(3) + v.charAt(3);
+			}
+			$(this).closest('.__ponzu-color').find('.__ponzu-color-picker').val(v.toLowerCase());
+		});
+	});
+</script>
+`
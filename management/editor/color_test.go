@@ -0,0 +1,46 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColor(t *testing.T) {
+	p := &testContact{Name: "#F80", Links: []string{"#336699", "not a color"}}
+
+	view := string(Color("Name", p, map[string]string{"label": "Brand color"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	if !strings.Contains(view, `<input type="color" class="__ponzu-color-picker" data-ponzu-display="true" value="#ff8800" />`) {
+		t.Errorf("Expected the picker to hold the stored color, got: %s", view)
+	}
+
+	if !strings.Contains(view, `value="#F80"`) || !strings.Contains(view, `name="name"`) ||
+		!strings.Contains(view, `pattern="`+colorPattern+`"`) {
+		t.Errorf("Expected the hex code input, got: %s", view)
+	}
+
+	view = string(ColorRepeater("Links", p, map[string]string{"label": "Palette"}))
+	if strings.Count(view, `class="__ponzu-color-picker"`) != 2 || !strings.Contains(view, `value="#336699" />`) ||
+		!strings.Contains(view, `name="links.1"`) {
+		t.Errorf("Expected a color per stored value, got: %s", view)
+	}
+
+	if !strings.Contains(view, `data-ponzu-display="true" value="#000000"`) {
+		t.Errorf("Expected an invalid color to leave the picker black, got: %s", view)
+	}
+}
+
+func TestValidateColor(t *testing.T) {
+	for _, s := range []string{"#fff", "#FF8800", "#a1b2c3"} {
+		if err := ValidateColor(s); err != nil {
+			t.Errorf("Expected %q to be valid, got: %s", s, err)
+		}
+	}
+
+	for _, s := range []string{"", "fff", "#ffff", "#gg0000", "red"} {
+		if err := ValidateColor(s); err == nil {
+			t.Errorf("Expected %q to be invalid", s)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package editor
+
+import (
+	"encoding/json"
+	"html"
+)
+
+// ShowWhen returns f displayed only while the field whose form name is name,
+// e.g. "type", has one of values, such as an "External URL" field which only
+// matters when the type is "link". The controlling field may be any input,
+// select or textarea, and an unchecked checkbox or radio group has an empty
+// value. While f is hidden, the names of its inputs are cleared so that its
+// stale values are not submitted, and they are restored when it is shown
+// again. Fields containing repeaters can be shown conditionally, since the
+// names are cleared after RepeatController has named them. A required field
+// doesn't stop the form from being submitted while it is hidden.
+func ShowWhen(f Field, name string, values ...string) Field {
+	vals, err := json.Marshal(values)
+	if err != nil || values == nil {
+		vals = []byte("[]")
+	}
+
+	view := []byte(`<div class="__ponzu-show-when" data-show-name="` + html.EscapeString(name) +
+		`" data-show-values="` + html.EscapeString(string(vals)) + `">`)
+	view = append(view, f.View...)
+	view = append(view, `</div>`+showWhenScript...)
+
+	f.View = view
+	return f
+}
+
+// showWhenScript toggles every field of the page shown conditionally by
+// ShowWhen whenever a field of the page changes
+const showWhenScript = `
+<script>
+	$(function() {
+		if (window.__ponzuShowWhen) {
+			return;
+		}
+		window.__ponzuShowWhen = true;
+
+		// value returns the current value of the field named name, including
+		// one which is itself hidden
+		var value = function(name) {
+			var v = '';
+			$('[name], [data-ponzu-hidden-name]').each(function(i, el) {
+				var n = el.getAttribute('name') || el.getAttribute('data-ponzu-hidden-name');
+				if (n !== name) {
+					return;
+				}
+
+				if ((el.type === 'checkbox' || el.type === 'radio') && !el.checked) {
+					return;
+				}
+
+				v = $(el).val() || '';
+				return false;
+			});
+
+			return v;
+		}
+
+		var apply = function() {
+			$('.__ponzu-show-when').each(function(i, el) {
+				var $el = $(el),
+					values = JSON.parse($el.attr('data-show-values')),
+					show = $.inArray(value($el.attr('data-show-name')), values) !== -1,
+					inputs = $el.find('input, select, textarea');
+
+				if (show) {
+					// restore the names cleared when the field was hidden,
+					// unless an outer condition still hides it
+					if ($el.parents('.__ponzu-show-when').filter(':hidden').length === 0) {
+						inputs.filter('[data-ponzu-hidden-name]').each(function(j, input) {
+							$(input).attr('name', $(input).attr('data-ponzu-hidden-name'))
+								.removeAttr('data-ponzu-hidden-name');
+						});
+					}
+					$el.show();
+					return;
+				}
+
+				inputs.filter('[name]').each(function(j, input) {
+					var $input = $(input);
+					if ($input.attr('name') !== '') {
+						$input.attr('data-ponzu-hidden-name', $input.attr('name'));
+					}
+					$input.attr('name', '');
+				});
+				$el.hide();
+			});
+		}
+
+		$(document).on('change input', 'input, select, textarea', apply);
+
+		// wait for RepeatController to name the inputs of repeaters
+		setTimeout(apply, 0);
+	});
+</script>
+`
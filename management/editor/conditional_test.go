@@ -0,0 +1,21 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShowWhen(t *testing.T) {
+	p := &testContact{Links: []string{"https://example.com"}}
+
+	f := ShowWhen(Field{View: InputRepeater("Links", p, map[string]string{"label": "Links"})}, "type", "link", `"other"`)
+	view := string(f.View)
+
+	if !strings.HasPrefix(view, `<div class="__ponzu-show-when" data-show-name="type" data-show-values="[&#34;link&#34;,&#34;\&#34;other\&#34;&#34;]">`) {
+		t.Errorf("Expected the field to be wrapped with its condition, got: %s", view)
+	}
+
+	if !strings.Contains(view, `name="links.0"`) || !strings.Contains(view, "window.__ponzuShowWhen") {
+		t.Errorf("Expected the field and the script, got: %s", view)
+	}
+}
@@ -0,0 +1,111 @@
+package editor
+
+import (
+	"strconv"
+)
+
+// counterAttrs returns attrs with the data-ponzu-count attribute, which shows a
+// live counter below the element, when attrs["maxlength"] limits its length or
+// attrs["countwords"] is "true", and otherwise attrs itself. The counter of a
+// maxlength shows the characters used out of those allowed, while countwords
+// shows the number of words, without limiting them.
+func counterAttrs(fieldName string, attrs map[string]string) map[string]string {
+	mode := counterMode(fieldName, attrs)
+	if mode == "" {
+		return attrs
+	}
+
+	counted := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		counted[k] = v
+	}
+	counted["data-ponzu-count"] = mode
+
+	return counted
+}
+
+// counterMode returns "words" when attrs["countwords"] is "true", "chars" when
+// attrs["maxlength"] is a positive number, or an empty string for no counter
+func counterMode(fieldName string, attrs map[string]string) string {
+	if attrs["countwords"] == "true" {
+		return "words"
+	}
+
+	if attrs["maxlength"] == "" {
+		return ""
+	}
+
+	if n, err := strconv.Atoi(attrs["maxlength"]); err != nil || n < 1 {
+		logf("editor: invalid maxlength, no counter shown", "field", fieldName, "maxlength", attrs["maxlength"])
+		return ""
+	}
+
+	return "chars"
+}
+
+// counterScript returns the script which keeps the counter of every element of
+// the page with a data-ponzu-count attribute, including the items added by
+// RepeatController, each of which counts independently. An element over its
+// maxlength, such as one holding a longer stored value, is flagged with the
+// invalid class, and the form can't be submitted until it is shortened.
+func counterScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuCounter) {
+			return;
+		}
+		window.__ponzuCounter = true;
+
+		var words = ` + jsString(text("counter.words")) + `,
+			tooLong = ` + jsString(text("counter.tooLong")) + `;
+
+		// count updates the counter following el, or its affixes, adding it
+		// if needed
+		var count = function(el) {
+			var field = $(el),
+				anchor = field.parent('.__ponzu-affixed').length ? field.parent() : field,
+				counter = anchor.nextAll('.__ponzu-counter').first(),
+				max = parseInt(field.attr('maxlength'), 10) || 0,
+				n = el.value.length,
+				over = max > 0 && n > max,
+				shown = max > 0 ? n + ' / ' + max : '';
+
+			if (!counter.length) {
+				counter = $('<span class="__ponzu-counter character-counter"></span>').insertAfter(anchor);
+			}
+
+			if (field.attr('data-ponzu-count') === 'words') {
+				var value = $.trim(el.value),
+					w = value === '' ? 0 : value.split(/\s+/).length;
+				shown = words.replace('{n}', w) + (shown ? ', ' + shown : '');
+			}
+
+			counter.text(shown).toggleClass('red-text', over);
+
+			// only clear the invalid class this counter set
+			if (over) {
+				field.addClass('invalid').attr('data-ponzu-over', 'true');
+			} else if (field.attr('data-ponzu-over')) {
+				field.removeClass('invalid').removeAttr('data-ponzu-over');
+			}
+			el.setCustomValidity(over ? tooLong.replace('{n}', max) : '');
+		}
+
+		$(document).on('input', '[data-ponzu-count]', function() {
+			count(this);
+		});
+
+		$(document).on('ponzu-repeat-add', function(e) {
+			$(e.target).find('[data-ponzu-count]').each(function() {
+				count(this);
+			});
+		});
+
+		$('[data-ponzu-count]').each(function() {
+			count(this);
+		});
+	});
+</script>
+`
+}
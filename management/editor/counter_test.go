@@ -0,0 +1,34 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	p := &testContact{Bio: "Hi there", Links: []string{"a", "b"}}
+
+	view := string(Textarea("Bio", p, map[string]string{"maxlength": "160"}))
+	if !strings.Contains(view, `data-ponzu-count="chars"`) || !strings.Contains(view, `maxlength="160"`) ||
+		!strings.Contains(view, "window.__ponzuCounter") {
+		t.Errorf("Expected a counter of the characters, got: %s", view)
+	}
+
+	view = string(Textarea("Bio", p, map[string]string{"countwords": "true"}))
+	view = view[:strings.Index(view, "<script>")]
+	if !strings.Contains(view, `data-ponzu-count="words"`) || strings.Contains(view, "countwords") || strings.Contains(view, "maxlength") {
+		t.Errorf("Expected a counter of the words without a limit, got: %s", view)
+	}
+
+	view = string(InputRepeater("Links", p, map[string]string{"type": "text", "maxlength": "20"}))
+	if strings.Count(view, `data-ponzu-count="chars"`) != 2 || strings.Count(view, "if (window.__ponzuCounter)") != 1 {
+		t.Errorf("Expected each item to be counted, got: %s", view)
+	}
+
+	for _, attrs := range []map[string]string{{}, {"maxlength": "x"}} {
+		view = string(Textarea("Bio", p, attrs))
+		if strings.Contains(view, "data-ponzu-count") || strings.Contains(view, "__ponzuCounter") {
+			t.Errorf("%v: expected no counter, got: %s", attrs, view)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"html"
+	"net/http"
+)
+
+const (
+	// CSRFFieldName is the name of the hidden input Form renders to submit the
+	// CSRF token with every editor form
+	CSRFFieldName = "__ponzu-csrf"
+
+	// CSRFCookieName is the name of the cookie which holds the CSRF token the
+	// submitted form value must match
+	CSRFCookieName = "__ponzu-csrf"
+
+	// CSRFHeaderName is the request header the editor's own requests, such as
+	// autosaves and reference searches, send the CSRF token in
+	CSRFHeaderName = "X-Ponzu-CSRF"
+)
+
+// ErrInvalidCSRF is returned by ValidateCSRF when the submitted token is
+// missing or doesn't match the token in the request's cookie
+var ErrInvalidCSRF = errors.New("missing or invalid CSRF token")
+
+// SetCSRFCookie ensures the admin has a CSRF token cookie and returns the token.
+// An existing token is reused, otherwise a new random one is generated. Call it
+// from the handler which serves an editor, before writing the response.
+//
+// Tokens follow the double-submit cookie pattern: Form renders a hidden input
+// named CSRFFieldName in every editor, which is filled with the token from this
+// cookie in the browser (or with FormOptions.CSRFToken when set), and the
+// handler which receives the form checks the two match with ValidateCSRF.
+func SetCSRFCookie(res http.ResponseWriter, req *http.Request) (string, error) {
+	if c, err := req.Cookie(CSRFCookieName); err == nil && c.Value != "" {
+		return c.Value, nil
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	http.SetCookie(res, &http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    token,
+		Path:     "/admin",
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return token, nil
+}
+
+// ValidateCSRF returns ErrInvalidCSRF unless the CSRF token submitted with the
+// request matches the token in its cookie. The token is read from the
+// CSRFHeaderName header, or otherwise from the request's form.
+func ValidateCSRF(req *http.Request) error {
+	c, err := req.Cookie(CSRFCookieName)
+	if err != nil || c.Value == "" {
+		return ErrInvalidCSRF
+	}
+
+	token := req.Header.Get(CSRFHeaderName)
+	if token == "" {
+		token = req.FormValue(CSRFFieldName)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(c.Value)) != 1 {
+		return ErrInvalidCSRF
+	}
+
+	return nil
+}
+
+// csrfInput returns the hidden input which submits the CSRF token with an
+// editor form. Without a token, the value is read from the CSRF cookie in
+// the browser.
+func csrfInput(token string) string {
+	input := `<input type="hidden" class="__ponzu-csrf" name="` + CSRFFieldName + `" value="` +
+		html.EscapeString(token) + `" />`
+
+	if token != "" {
+		return input
+	}
+
+	return input + `
+<script>
+	$(function() {
+		var match = document.cookie.match(/(?:^|;\s*)` + CSRFCookieName + `=([^;]*)/);
+		if (match) {
+			$('input.__ponzu-csrf').val(decodeURIComponent(match[1]));
+		}
+	});
+</script>
+`
+}
+
+// csrfToken is a JS expression of the page's CSRF token, for the editor's
+// scripts to send with their requests. The token is read from the editor's
+// hidden input, or from the CSRF cookie before the input is filled.
+const csrfToken = `($('input.__ponzu-csrf').val() || decodeURIComponent((document.cookie.match(/(?:^|;\s*)` +
+	CSRFCookieName + `=([^;]*)/) || ['', ''])[1]))`
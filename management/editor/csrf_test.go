@@ -0,0 +1,116 @@
+package editor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// testCSRFRequest returns a POST of an editor form submitting token, with the
+// CSRF cookie set to cookie unless it is empty
+func testCSRFRequest(cookie, token string) *http.Request {
+	form := url.Values{"title": {"Hello"}}
+	if token != "" {
+		form.Set(CSRFFieldName, token)
+	}
+
+	req := httptest.NewRequest("POST", "/admin/edit", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: cookie})
+	}
+
+	return req
+}
+
+func TestSetCSRFCookie(t *testing.T) {
+	res := httptest.NewRecorder()
+	token, err := SetCSRFCookie(res, httptest.NewRequest("GET", "/admin/edit", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := res.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CSRFCookieName || cookies[0].Value != token || len(token) < 32 {
+		t.Fatalf("Expected a new token in the cookie, got %q and %v", token, cookies)
+	}
+
+	if cookies[0].Path != "/admin" || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Errorf("Expected a strict cookie for the admin, got %v", cookies[0])
+	}
+
+	other, err := SetCSRFCookie(httptest.NewRecorder(), httptest.NewRequest("GET", "/admin/edit", nil))
+	if err != nil || other == token {
+		t.Errorf("Expected a different token for a different admin, got %q, %v", other, err)
+	}
+
+	// an existing token is reused, without setting the cookie again
+	req := httptest.NewRequest("GET", "/admin/edit", nil)
+	req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: token})
+
+	res = httptest.NewRecorder()
+	again, err := SetCSRFCookie(res, req)
+	if err != nil || again != token || len(res.Result().Cookies()) != 0 {
+		t.Errorf("Expected the existing token to be reused, got %q, %v", again, err)
+	}
+}
+
+func TestValidateCSRF(t *testing.T) {
+	cases := []struct {
+		name   string
+		cookie string
+		token  string
+		valid  bool
+	}{
+		{"missing cookie", "", "abc123", false},
+		{"missing form token", "abc123", "", false},
+		{"missing both", "", "", false},
+		{"mismatched token", "abc123", "abc124", false},
+		{"token prefix", "abc123", "abc", false},
+		{"valid token", "abc123", "abc123", true},
+	}
+
+	for _, c := range cases {
+		err := ValidateCSRF(testCSRFRequest(c.cookie, c.token))
+		if c.valid && err != nil {
+			t.Errorf("%s: expected no error, got: %s", c.name, err)
+		}
+
+		if !c.valid && err != ErrInvalidCSRF {
+			t.Errorf("%s: expected ErrInvalidCSRF, got: %v", c.name, err)
+		}
+	}
+
+	// the editor's scripts send the token in a header, with a JSON body
+	req := httptest.NewRequest("POST", "/admin/edit/autosave", strings.NewReader(`{"title":["Hello"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: "abc123"})
+	if err := ValidateCSRF(req); err != ErrInvalidCSRF {
+		t.Errorf("Expected ErrInvalidCSRF without the header, got: %v", err)
+	}
+
+	req.Header.Set(CSRFHeaderName, "abc123")
+	if err := ValidateCSRF(req); err != nil {
+		t.Errorf("Expected the token in the header to be valid, got: %s", err)
+	}
+
+	req = testCSRFRequest("abc123", "abc123")
+	req.Header.Set(CSRFHeaderName, "abc124")
+	if err := ValidateCSRF(req); err != ErrInvalidCSRF {
+		t.Errorf("Expected the header to be validated before the form, got: %v", err)
+	}
+}
+
+func TestCSRFScripts(t *testing.T) {
+	view, err := Form(&testPost{}, Field{View: Reference("Title", &testPost{}, map[string]string{}, "Post")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// autosaves and reference searches both send the token
+	if strings.Count(string(view), `headers: {'X-Ponzu-CSRF': `+csrfToken+`}`) != 2 {
+		t.Errorf("Expected the token to be sent with the editor's requests, got: %s", view)
+	}
+}
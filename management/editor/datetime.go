@@ -0,0 +1,140 @@
+package editor
+
+import (
+	"html"
+	"time"
+)
+
+// DateTime returns the []byte of a date picker and a time picker, which store
+// the chosen moment in a hidden input as an RFC3339 string, e.g.
+// "2018-03-04T15:30:00-05:00", so that it can be held by a string or a
+// time.Time field. attrs["mode"] is "date" for only the date picker, "time" for
+// only the time picker, or "datetime", the default, for both. The stored value
+// is shown in its own timezone offset, which is kept when it is edited, while a
+// new value records the offset of the editor's browser, so the value always
+// round-trips to the same moment. An unset value shows empty pickers rather
+// than a default date, and clearing the pickers clears the value.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func DateTime(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("DateTime", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+
+	mode := attrs["mode"]
+	if mode != "date" && mode != "time" {
+		mode = "datetime"
+	}
+
+	date, clock, offset := dateTimeParts(value)
+	if date == "" && value != "" {
+		logf("editor: DateTime value is not RFC3339", "value", value, "field", fieldName)
+		value = ""
+	}
+
+	var label, zone string
+	if attrs["label"] != "" {
+		label = `<label class="active">` + attrs["label"] + `</label>`
+	}
+
+	if offset != "" {
+		zone = "UTC" + offset
+	}
+
+	view := `<div class="__ponzu-datetime ` + name + ` input-field col s12" data-mode="` + mode +
+		`" data-offset="` + offset + `">` + label + `<div class="__ponzu-datetime-pickers">`
+
+	if mode != "time" {
+		view += `<input type="date" class="__ponzu-datetime-date browser-default" value="` + date + `" />`
+	}
+
+	if mode != "date" {
+		view += `<input type="time" class="__ponzu-datetime-time browser-default" value="` + clock + `" />`
+	}
+
+	view += `<span class="__ponzu-datetime-zone">` + zone + `</span></div>` +
+		`<input type="hidden" class="__ponzu-datetime-value" name="` + name + `" value="` + html.EscapeString(value) + `" />` +
+		`</div>` + dateTimeScript
+
+	return []byte(view)
+}
+
+// dateTimeParts splits the RFC3339 value into the values of a date picker and a
+// time picker, as the wall clock time in the value's own offset, and the offset
+// itself, e.g. "-05:00" or "+00:00". All are empty if value can't be parsed.
+func dateTimeParts(value string) (date, clock, offset string) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", "", ""
+	}
+
+	clock = t.Format("15:04")
+	if t.Second() != 0 {
+		clock = t.Format("15:04:05")
+	}
+
+	return t.Format("2006-01-02"), clock, t.Format("-07:00")
+}
+
+// dateTimeScript composes the RFC3339 value of every DateTime of the page when
+// its pickers change. A date without a time is stored at midnight, and a time
+// without a date keeps the stored date, or is stored on 0001-01-01.
+const dateTimeScript = `
+<script>
+	$(function() {
+		if (window.__ponzuDateTime) {
+			return;
+		}
+		window.__ponzuDateTime = true;
+
+		var pad = function(n) {
+			return (n < 10 ? '0' : '') + String(n);
+		}
+
+		// offset returns the browser's offset at the date and clock, in the
+		// form "-05:00"
+		var offset = function(date, clock) {
+			var d = date.split('-'), c = clock.split(':'),
+				local = new Date(Number(d[0]), Number(d[1]) - 1, Number(d[2]), Number(c[0]), Number(c[1])),
+				mins = -local.getTimezoneOffset();
+
+			if (isNaN(mins) || Number(d[0]) < 1000) {
+				mins = -new Date().getTimezoneOffset();
+			}
+
+			return (mins < 0 ? '-' : '+') + pad(Math.floor(Math.abs(mins) / 60)) + ':' + pad(Math.abs(mins) % 60);
+		}
+
+		$(document).on('change input', '.__ponzu-datetime-date, .__ponzu-datetime-time', function() {
+			var field = $(this).closest('.__ponzu-datetime'),
+				mode = field.attr('data-mode'),
+				stored = field.find('.__ponzu-datetime-value'),
+				date = field.find('.__ponzu-datetime-date').val() || '',
+				clock = field.find('.__ponzu-datetime-time').val() || '';
+
+			if ((mode !== 'time' && date === '') || (mode === 'time' && clock === '')) {
+				stored.val('').trigger('change');
+				return;
+			}
+
+			if (mode === 'time') {
+				date = stored.val().slice(0, 10) || '0001-01-01';
+			}
+
+			if (clock === '') {
+				clock = '00:00';
+			}
+
+			if (clock.length === 5) {
+				clock += ':00';
+			}
+
+			var zone = field.attr('data-offset') || offset(date, clock);
+			stored.val(date + 'T' + clock + (zone === '+00:00' ? 'Z' : zone)).trigger('change');
+			field.find('.__ponzu-datetime-zone').text('UTC' + zone);
+		});
+	});
+</script>
+`
@@ -0,0 +1,58 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateTime(t *testing.T) {
+	nyc := time.FixedZone("EST", -5*60*60)
+	e := &testEvent{Starts: time.Date(2018, 3, 4, 15, 30, 0, 0, nyc)}
+
+	view := string(DateTime("Starts", e, map[string]string{"label": "Starts"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	for _, s := range []string{
+		`data-mode="datetime" data-offset="-05:00"`,
+		`<input type="date" class="__ponzu-datetime-date browser-default" value="2018-03-04" />`,
+		`<input type="time" class="__ponzu-datetime-time browser-default" value="15:30" />`,
+		`<span class="__ponzu-datetime-zone">UTC-05:00</span>`,
+		`name="starts" value="2018-03-04T15:30:00-05:00"`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	view = string(DateTime("Starts", &testEvent{}, map[string]string{"mode": "date"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	if strings.Contains(view, `type="time"`) {
+		t.Errorf("Expected no time picker in date mode, got: %s", view)
+	}
+
+	if !strings.Contains(view, `class="__ponzu-datetime-date browser-default" value=""`) ||
+		!strings.Contains(view, `name="starts" value=""`) || !strings.Contains(view, `data-offset=""`) {
+		t.Errorf("Expected an unset time to render empty, got: %s", view)
+	}
+}
+
+func TestDateTimeParts(t *testing.T) {
+	cases := []struct {
+		value, date, clock, offset string
+	}{
+		{"2018-03-04T15:30:00-05:00", "2018-03-04", "15:30", "-05:00"},
+		{"2018-03-04T15:30:45Z", "2018-03-04", "15:30:45", "+00:00"},
+		{"0001-01-01T09:00:00+05:30", "0001-01-01", "09:00", "+05:30"},
+		{"", "", "", ""},
+		{"March 4th", "", "", ""},
+	}
+
+	for _, c := range cases {
+		date, clock, offset := dateTimeParts(c.value)
+		if date != c.date || clock != c.clock || offset != c.offset {
+			t.Errorf("%q: expected %q %q %q, got: %q %q %q", c.value, c.date, c.clock, c.offset, date, clock, offset)
+		}
+	}
+}
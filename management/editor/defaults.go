@@ -0,0 +1,48 @@
+package editor
+
+// identifiable is implemented by content which knows its ID, such as any
+// content type embedding item.Item, so that new content can be told apart from
+// stored content
+type identifiable interface {
+	ItemID() int
+}
+
+// isNew reports whether p is content which hasn't been stored yet. New content
+// has no ID yet, or an ID of -1, which the admin sets on new content before it
+// is edited. Content without an ItemID method is always treated as new.
+func isNew(p interface{}) bool {
+	i, ok := p.(identifiable)
+	if !ok {
+		return true
+	}
+
+	return i.ItemID() <= 0
+}
+
+// defaultValue returns value, or attrs["default"] if value is empty and p is
+// new content. An empty value of stored content is kept, since it may have been
+// emptied on purpose, and the editor can't tell that apart from a value which
+// was never set.
+func defaultValue(p interface{}, value string, attrs map[string]string) string {
+	if value == "" && attrs["default"] != "" && isNew(p) {
+		return attrs["default"]
+	}
+
+	return value
+}
+
+// defaultValues is like defaultValue for the values of a repeater, returning
+// a single attrs["default"] value in place of vals if they are all empty
+func defaultValues(p interface{}, vals []string, attrs map[string]string) []string {
+	for _, v := range vals {
+		if v != "" {
+			return vals
+		}
+	}
+
+	if attrs["default"] != "" && isNew(p) {
+		return []string{attrs["default"]}
+	}
+
+	return vals
+}
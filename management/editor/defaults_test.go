@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+type testItem struct {
+	ID     int      `json:"id"`
+	Status string   `json:"status"`
+	Tags   []string `json:"tags"`
+}
+
+func (i *testItem) ItemID() int { return i.ID }
+
+func TestDefaults(t *testing.T) {
+	statuses := map[string]string{"draft": "Draft", "published": "Published"}
+
+	cases := []struct {
+		name     string
+		item     *testItem
+		expected string
+		ignored  string
+	}{
+		{"new", &testItem{ID: -1}, "draft", ""},
+		{"populated", &testItem{ID: -1, Status: "published", Tags: []string{"news"}}, "published", "draft"},
+		{"stored empty", &testItem{ID: 3}, "", "draft"},
+	}
+
+	for _, c := range cases {
+		view := string(Input("Status", c.item, map[string]string{"type": "text", "default": "draft"}))
+		if !strings.Contains(view, `value="`+c.expected+`"`) || strings.Contains(view, "default=") {
+			t.Errorf("%s: expected Input value %q, got: %s", c.name, c.expected, view)
+		}
+
+		view = string(Select("Status", c.item, map[string]string{"default": "draft"}, statuses))
+		if c.expected != "" && !isSelected(view, c.expected) {
+			t.Errorf("%s: expected %q to be selected, got: %s", c.name, c.expected, view)
+		}
+		if c.ignored != "" && isSelected(view, c.ignored) {
+			t.Errorf("%s: expected the default to be ignored, got: %s", c.name, view)
+		}
+
+		view = string(InputRepeater("Tags", c.item, map[string]string{"type": "text", "default": "draft"}))
+		if c.ignored == "" && !strings.Contains(view, `value="draft"`) {
+			t.Errorf("%s: expected the default tag, got: %s", c.name, view)
+		}
+		if c.ignored != "" && strings.Contains(view, `value="draft"`) {
+			t.Errorf("%s: expected the default tag to be ignored, got: %s", c.name, view)
+		}
+
+		view = string(SelectRepeater("Tags", c.item, map[string]string{"default": "news"}, map[string]string{"news": "News", "blog": "Blog"}))
+		if isSelected(view, "news") != (c.name != "stored empty") {
+			t.Errorf("%s: expected the default option to be selected only when empty and new, got: %s", c.name, view)
+		}
+	}
+}
+
+// isSelected reports whether the option with value is selected in view, in
+// either order of its attributes
+func isSelected(view, value string) bool {
+	return strings.Contains(view, `value="`+value+`" selected `) ||
+		strings.Contains(view, `selected value="`+value+`"`)
+}
+
+func TestIsNew(t *testing.T) {
+	if !isNew(&testItem{ID: -1}) || !isNew(&testItem{}) || isNew(&testItem{ID: 1}) {
+		t.Error("Expected content without an ID to be new")
+	}
+
+	if !isNew(&testContact{}) {
+		t.Error("Expected content without ItemID to be new")
+	}
+}
@@ -0,0 +1,195 @@
+package editor
+
+import (
+	"encoding/json"
+	"html"
+)
+
+// DependentSelect returns the []byte of a <select> HTML element whose options
+// depend on the value of another field, e.g. a state select filtered by the
+// chosen country. parentFieldName is the struct field name of the controlling
+// field, which must be rendered in the same editor.
+// The options for each value of the parent field are provided in options, as
+// parent value -> (option value -> label), and are displayed in order of their
+// labels. Alternatively attrs["endpoint"] may be set to load the options when
+// the parent changes, in which case "{value}" within the endpoint is replaced
+// by the parent's URL-encoded value, and it must respond with JSON in the form
+// {"data": [{"value": "CA", "label": "California"}, ...]}.
+// On load, the options for the stored parent value are rendered with the stored
+// value pre-selected. A stored value which isn't among them is kept, selected,
+// as an option labeled as unavailable, so that saving doesn't drop it. When the
+// parent changes and the selected value is not among the new options, the
+// selection is reset.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func DependentSelect(fieldName string, p interface{}, parentFieldName string, attrs map[string]string, options map[string]map[string]string) []byte {
+	checkAttrs("DependentSelect", fieldName, attrs)
+
+	return dependentSelect(fieldName, p, parentFieldName, attrs, options)
+}
+
+// CascadingSelect returns the []byte of a pair of <select> HTML elements, the
+// second of which, for fieldName, offers the options of the value chosen in the
+// first, for parentFieldName, e.g. the states of the chosen country in an
+// address. The relationships are provided in options as parent value ->
+// (option value -> label), like those of DependentSelect, and the parent offers
+// each of their parent values, labeled by parentOptions where it has a label
+// for them, e.g. "US" -> "United States". Both selects display their options in
+// order of their labels, and are rendered with their stored values selected.
+// Changing the parent resets the child when its value is not among the new
+// options, and the child's options may be loaded from attrs["endpoint"] as for
+// DependentSelect. attrs["label"] labels the child and attrs["parentLabel"] the
+// parent.
+// IMPORTANT:
+// The `fieldName` and `parentFieldName` arguments will cause a panic if they
+// are not exactly the string form of the struct fields they represent
+func CascadingSelect(fieldName string, p interface{}, parentFieldName string, attrs map[string]string, parentOptions map[string]string, options map[string]map[string]string) []byte {
+	checkAttrs("CascadingSelect", fieldName, attrs)
+
+	parent := TagNameFromStructField(parentFieldName, p)
+	parentValue := ValueFromStructField(parentFieldName, p)
+
+	// every parent value with options is offered, even without a label
+	labels := make(map[string]string, len(options))
+	for k := range options {
+		labels[k] = k
+	}
+	for k, v := range parentOptions {
+		labels[k] = v
+	}
+
+	// a stored value which is no longer offered is kept, so it isn't lost
+	if _, ok := labels[parentValue]; !ok && parentValue != "" {
+		labels[parentValue] = text("select.unavailable", "value", parentValue)
+	}
+
+	id := fieldID(parent)
+	view := `<div class="__ponzu-cascading-select ` + parent + ` input-field col s12">
+		<label class="active" for="` + id + `">` + attrs["parentLabel"] + `</label>
+		<select class="browser-default" id="` + id + `" name="` + parent + `">
+			<option class="__ponzu-cta" value=""` + selectedIf(parentValue == "") + `>` + htmlText("select.cta") + `</option>`
+
+	for _, opt := range sortedOptions(labels) {
+		view += `<option value="` + html.EscapeString(opt.Value) + `"` + selectedIf(opt.Value == parentValue) + `>` +
+			html.EscapeString(opt.Label) + `</option>`
+	}
+
+	view += `</select></div>`
+
+	return append([]byte(view), dependentSelect(fieldName, p, parentFieldName, attrs, options)...)
+}
+
+// dependentSelect renders the DependentSelect of the options of each value of
+// the field parentFieldName
+func dependentSelect(fieldName string, p interface{}, parentFieldName string, attrs map[string]string, options map[string]map[string]string) []byte {
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+	parent := TagNameFromStructField(parentFieldName, p)
+	parentValue := ValueFromStructField(parentFieldName, p)
+
+	// the options of each parent value are in order of their labels, and are
+	// encoded for the script with their parent values in order
+	byParent := make(map[string][]Option)
+	for k, opts := range options {
+		byParent[k] = sortedOptions(opts)
+	}
+
+	current := byParent[parentValue]
+	if attrs["endpoint"] != "" && value != "" {
+		// the stored value is kept until the endpoint's options have loaded
+		current = []Option{{Value: value, Label: value}}
+	} else if value != "" && !hasOption(current, value) {
+		// a stored value which isn't offered for the stored parent value is
+		// kept, so that saving doesn't drop it
+		current = append(append([]Option{}, current...), Option{Value: value, Label: text("select.unavailable", "value", value)})
+	}
+
+	id := fieldID(name)
+	view := `<div class="__ponzu-dependent-select ` + name + ` input-field col s12">
+		<label class="active" for="` + id + `">` + attrs["label"] + `</label>
+		<select class="browser-default" id="` + id + `" name="` + name + `">
+			<option class="__ponzu-cta" value=""` + selectedIf(value == "") + `>` + htmlText("select.cta") + `</option>`
+
+	for _, opt := range current {
+		view += `<option value="` + html.EscapeString(opt.Value) + `"` + selectedIf(opt.Value == value) + `>` +
+			html.EscapeString(opt.Label) + `</option>`
+	}
+
+	view += `</select></div>`
+
+	data, err := json.Marshal(byParent)
+	if err != nil {
+		data = []byte("{}")
+	}
+
+	script := `
+	<script>
+		$(function() {
+			var child = $('.__ponzu-dependent-select.` + selectorClass(name) + ` select'),
+				form = child.closest('form'),
+				parentSelector = '[name="' + ` + jsString(parent) + ` + '"]',
+				options = ` + string(data) + `,
+				endpoint = ` + jsString(attrs["endpoint"]) + `,
+				unavailable = ` + jsString(text("select.unavailable")) + `;
+
+			var parentValue = function() {
+				var $parent = form.find(parentSelector);
+				if ($parent.is(':radio')) {
+					return $parent.filter(':checked').val() || '';
+				}
+
+				return $parent.val() || '';
+			}
+
+			// populate replaces the options with list, keeping the selected
+			// value as unavailable when keep is true and list doesn't offer it
+			var populate = function(list, keep) {
+				var selected = child.val();
+				child.find('option:not(.__ponzu-cta)').remove();
+
+				var found = false;
+				$.each(list || [], function(i, opt) {
+					child.append($('<option>').val(opt.value).text(opt.label));
+					if (opt.value === selected) {
+						found = true;
+					}
+				});
+
+				if (!found && keep && selected) {
+					child.append($('<option>').val(selected).text(unavailable.replace('{value}', selected)));
+					found = true;
+				}
+
+				child.val(found ? selected : '');
+			}
+
+			var load = function(keep) {
+				var v = parentValue();
+				if (endpoint === '') {
+					populate(options[v], keep);
+					return;
+				}
+
+				if (v === '') {
+					populate([], keep);
+					return;
+				}
+
+				$.getJSON(endpoint.replace('{value}', encodeURIComponent(v)), function(resp) {
+					populate((resp && resp.data) || [], keep);
+				});
+			}
+
+			form.on('change', parentSelector, function() {
+				load(false);
+			});
+
+			if (endpoint !== '') {
+				load(true);
+			}
+		});
+	</script>`
+
+	return []byte(view + script)
+}
@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+type testAddress struct {
+	Country string `json:"country"`
+	State   string `json:"state"`
+}
+
+func TestCascadingSelect(t *testing.T) {
+	p := &testAddress{Country: "US", State: "CA"}
+	options := map[string]map[string]string{
+		"US": {"NY": "New York", "CA": "California", "TX": "Texas"},
+		"CA": {"ON": "Ontario", "BC": "British Columbia"},
+	}
+	countries := map[string]string{"US": "United States", "CA": "Canada"}
+	attrs := map[string]string{"label": "State", "parentLabel": "Country"}
+
+	view := string(CascadingSelect("State", p, "Country", attrs, countries, options))
+	for i := 0; i < 10; i++ {
+		if again := string(CascadingSelect("State", p, "Country", attrs, countries, options)); again != view {
+			t.Fatalf("Expected the same markup on every render, got: %s\nthen: %s", view, again)
+		}
+	}
+
+	parent := view[:strings.Index(view, `__ponzu-dependent-select`)]
+	if !strings.Contains(parent, `name="country"`) || !strings.Contains(parent, `<option value="US" selected>United States</option>`) {
+		t.Errorf("Expected the parent with its stored value selected, got: %s", parent)
+	}
+
+	if strings.Index(parent, "Canada") > strings.Index(parent, "United States") {
+		t.Errorf("Expected the parent options in order of their labels, got: %s", parent)
+	}
+
+	child := view[strings.Index(view, `__ponzu-dependent-select`):strings.Index(view, "<script>")]
+	if !strings.Contains(child, `<option value="CA" selected>California</option>`) || strings.Contains(child, "Ontario") {
+		t.Errorf("Expected only the options of the stored parent, with the stored value selected, got: %s", child)
+	}
+
+	if !(strings.Index(child, "California") < strings.Index(child, "New York") && strings.Index(child, "New York") < strings.Index(child, "Texas")) {
+		t.Errorf("Expected the child options in order of their labels, got: %s", child)
+	}
+
+	if !strings.Contains(view, `"CA":[{"value":"BC","label":"British Columbia"},{"value":"ON","label":"Ontario"}]`) {
+		t.Errorf("Expected the options of every parent value for the script, got: %s", view)
+	}
+
+	view = string(CascadingSelect("State", &testAddress{Country: "MX"}, "Country", attrs, nil, options))
+	if !strings.Contains(view, `<option value="MX" selected>MX (unavailable)</option>`) || !strings.Contains(view, `<option value="US">US</option>`) {
+		t.Errorf("Expected the parent values to be offered without labels, and the stored one kept, got: %s", view)
+	}
+}
+
+func TestDependentSelect(t *testing.T) {
+	options := map[string]map[string]string{
+		"US": {"NY": "New York", "CA": "California"},
+		"CA": {"ON": "Ontario"},
+	}
+
+	view := string(DependentSelect("State", &testAddress{Country: "US", State: "NY"}, "Country", map[string]string{"label": "State"}, options))
+	child := view[:strings.Index(view, "<script>")]
+
+	if !strings.Contains(child, `<label class="active" for="field-state">State</label>`) || !strings.Contains(child, `id="field-state" name="state"`) {
+		t.Errorf("Expected the label to be for the select, got: %s", child)
+	}
+
+	if !strings.Contains(child, `<option class="__ponzu-cta" value="">Select an option...</option>`) ||
+		!strings.Contains(child, `<option value="NY" selected>New York</option>`) || strings.Contains(child, "Ontario") {
+		t.Errorf("Expected the options of the stored parent, with the stored value selected, got: %s", child)
+	}
+
+	view = string(DependentSelect("State", &testAddress{Country: "CA", State: "NY"}, "Country", map[string]string{"label": "State"}, options))
+	child = view[:strings.Index(view, "<script>")]
+
+	if !strings.Contains(child, `<option value="ON">Ontario</option><option value="NY" selected>NY (unavailable)</option>`) ||
+		strings.Contains(child, `value="" selected`) {
+		t.Errorf("Expected the stored value to be kept as unavailable, got: %s", child)
+	}
+
+	if strings.Contains(view, "unavailable)\"}") || !strings.Contains(view, `"CA":[{"value":"ON","label":"Ontario"}]`) {
+		t.Errorf("Expected the unavailable value to be left out of the script's options, got: %s", view)
+	}
+
+	view = string(DependentSelect("State", &testAddress{Country: "US"}, "Country", map[string]string{}, options))
+	if !strings.Contains(view, `<option class="__ponzu-cta" value="" selected>`) || strings.Contains(view, "unavailable)<") {
+		t.Errorf("Expected the call to action to be selected without a stored value, got: %s", view)
+	}
+}
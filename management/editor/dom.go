@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"html"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -25,7 +27,7 @@ func NewElement(tagName, label, fieldName string, p interface{}, attrs map[strin
 		Attrs:   attrs,
 		Name:    TagNameFromStructField(fieldName, p),
 		Label:   label,
-		Data:    ValueFromStructField(fieldName, p),
+		Data:    defaultValue(p, ValueFromStructField(fieldName, p), attrs),
 		ViewBuf: &bytes.Buffer{},
 	}
 }
@@ -41,16 +43,15 @@ func DOMElementSelfClose(e *Element) []byte {
 
 	if e.Label != "" {
 		_, err = e.ViewBuf.WriteString(
-			`<label class="active" for="` +
-				strings.Join(strings.Split(e.Label, " "), "-") + `">` + e.Label +
-				`</label>`)
+			`<label class="active` + requiredClass(e.Attrs) + `" for="` + html.EscapeString(elementID(e)) + `">` + e.Label + `</label>`)
 		if err != nil {
 			log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
 			return nil
 		}
 	}
 
-	_, err = e.ViewBuf.WriteString(`<` + e.TagName + ` value="`)
+	prefix, suffix := affixes(e.Attrs)
+	_, err = e.ViewBuf.WriteString(prefix + `<` + e.TagName + ` value="`)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
 		return nil
@@ -62,20 +63,56 @@ func DOMElementSelfClose(e *Element) []byte {
 		return nil
 	}
 
-	for attr, value := range e.Attrs {
-		_, err := e.ViewBuf.WriteString(attr + `="` + value + `" `)
+	err = writeAttrs(e.ViewBuf, e.Attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
+		return nil
+	}
+
+	err = writeLabelAttrs(e.ViewBuf, e)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
+		return nil
+	}
+
+	if trimValue(e) {
+		_, err = e.ViewBuf.WriteString(`data-ponzu-trim="true"`)
 		if err != nil {
 			log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
 			return nil
 		}
 	}
-	_, err = e.ViewBuf.WriteString(` name="` + e.Name + `" />`)
+	_, err = e.ViewBuf.WriteString(` name="` + e.Name + `" />` + suffix)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
 		return nil
 	}
 
-	_, err = e.ViewBuf.WriteString(`</div>`)
+	if hasConstraints(e.Attrs) {
+		_, err = e.ViewBuf.WriteString(validIndicator())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
+			return nil
+		}
+	}
+
+	if isRequired(e.Attrs) {
+		_, err = e.ViewBuf.WriteString(requiredScript())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
+			return nil
+		}
+	}
+
+	if e.Attrs["emoji"] == "true" {
+		_, err = e.ViewBuf.WriteString(emojiPicker())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
+			return nil
+		}
+	}
+
+	_, err = e.ViewBuf.WriteString(helpText(e.Attrs) + `</div>`)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElementSelfClose")
 		return nil
@@ -99,12 +136,16 @@ func DOMElementCheckbox(e *Element) []byte {
 		return nil
 	}
 
-	for attr, value := range e.Attrs {
-		_, err := e.ViewBuf.WriteString(attr + `="` + value + `" `)
-		if err != nil {
-			log.Println("Error writing HTML string to buffer: DOMElementCheckbox")
-			return nil
-		}
+	err = writeAttrs(e.ViewBuf, e.Attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElementCheckbox")
+		return nil
+	}
+
+	err = writeLabelAttrs(e.ViewBuf, e)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElementCheckbox")
+		return nil
 	}
 	_, err = e.ViewBuf.WriteString(` name="` + e.Name + `" />`)
 	if err != nil {
@@ -114,16 +155,14 @@ func DOMElementCheckbox(e *Element) []byte {
 
 	if e.Label != "" {
 		_, err = e.ViewBuf.WriteString(
-			`<label for="` +
-				strings.Join(strings.Split(e.Label, " "), "-") + `">` +
-				e.Label + `</label>`)
+			`<label for="` + html.EscapeString(elementID(e)) + `">` + e.Label + `</label>`)
 		if err != nil {
 			log.Println("Error writing HTML string to buffer: DOMElementCheckbox")
 			return nil
 		}
 	}
 
-	_, err = e.ViewBuf.WriteString(`</p>`)
+	_, err = e.ViewBuf.WriteString(helpText(e.Attrs) + `</p>`)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElementCheckbox")
 		return nil
@@ -142,9 +181,7 @@ func DOMElement(e *Element) []byte {
 
 	if e.Label != "" {
 		_, err = e.ViewBuf.WriteString(
-			`<label class="active" for="` +
-				strings.Join(strings.Split(e.Label, " "), "-") + `">` + e.Label +
-				`</label>`)
+			`<label class="active` + requiredClass(e.Attrs) + `" for="` + html.EscapeString(elementID(e)) + `">` + e.Label + `</label>`)
 		if err != nil {
 			log.Println("Error writing HTML string to buffer: DOMElement")
 			return nil
@@ -157,8 +194,20 @@ func DOMElement(e *Element) []byte {
 		return nil
 	}
 
-	for attr, value := range e.Attrs {
-		_, err = e.ViewBuf.WriteString(attr + `="` + string(value) + `" `)
+	err = writeAttrs(e.ViewBuf, e.Attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElement")
+		return nil
+	}
+
+	err = writeLabelAttrs(e.ViewBuf, e)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElement")
+		return nil
+	}
+
+	if trimValue(e) {
+		_, err = e.ViewBuf.WriteString(`data-ponzu-trim="true"`)
 		if err != nil {
 			log.Println("Error writing HTML string to buffer: DOMElement")
 			return nil
@@ -182,7 +231,31 @@ func DOMElement(e *Element) []byte {
 		return nil
 	}
 
-	_, err = e.ViewBuf.WriteString(`</div>`)
+	if hasConstraints(e.Attrs) {
+		_, err = e.ViewBuf.WriteString(validIndicator())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElement")
+			return nil
+		}
+	}
+
+	if isRequired(e.Attrs) {
+		_, err = e.ViewBuf.WriteString(requiredScript())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElement")
+			return nil
+		}
+	}
+
+	if e.Attrs["emoji"] == "true" {
+		_, err = e.ViewBuf.WriteString(emojiPicker())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElement")
+			return nil
+		}
+	}
+
+	_, err = e.ViewBuf.WriteString(helpText(e.Attrs) + `</div>`)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElement")
 		return nil
@@ -192,6 +265,22 @@ func DOMElement(e *Element) []byte {
 }
 
 func DOMElementWithChildrenSelect(e *Element, children []*Element) []byte {
+	return domElementSelect(e, func(buf *bytes.Buffer) error {
+		// loop over children and create DOMElement for each child
+		for _, child := range children {
+			_, err := buf.Write(DOMElement(child))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// domElementSelect renders the select e like DOMElementWithChildrenSelect, with
+// its options written by writeOptions, e.g. within optgroups
+func domElementSelect(e *Element, writeOptions func(buf *bytes.Buffer) error) []byte {
 	_, err := e.ViewBuf.WriteString(`<div class="input-field col s6">`)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
@@ -204,12 +293,16 @@ func DOMElementWithChildrenSelect(e *Element, children []*Element) []byte {
 		return nil
 	}
 
-	for attr, value := range e.Attrs {
-		_, err = e.ViewBuf.WriteString(attr + `="` + value + `" `)
-		if err != nil {
-			log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
-			return nil
-		}
+	err = writeAttrs(e.ViewBuf, e.Attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
+		return nil
+	}
+
+	err = writeLabelAttrs(e.ViewBuf, e)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
+		return nil
 	}
 	_, err = e.ViewBuf.WriteString(` name="` + e.Name + `" >`)
 	if err != nil {
@@ -217,13 +310,10 @@ func DOMElementWithChildrenSelect(e *Element, children []*Element) []byte {
 		return nil
 	}
 
-	// loop over children and create DOMElement for each child
-	for _, child := range children {
-		_, err = e.ViewBuf.Write(DOMElement(child))
-		if err != nil {
-			log.Println("Error writing HTML DOMElement to buffer: DOMElementWithChildrenSelect")
-			return nil
-		}
+	err = writeOptions(e.ViewBuf)
+	if err != nil {
+		log.Println("Error writing HTML DOMElement to buffer: DOMElementWithChildrenSelect")
+		return nil
 	}
 
 	_, err = e.ViewBuf.WriteString(`</` + e.TagName + `>`)
@@ -232,15 +322,31 @@ func DOMElementWithChildrenSelect(e *Element, children []*Element) []byte {
 		return nil
 	}
 
+	if hasConstraints(e.Attrs) {
+		_, err = e.ViewBuf.WriteString(validIndicator())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
+			return nil
+		}
+	}
+
+	if isRequired(e.Attrs) {
+		_, err = e.ViewBuf.WriteString(requiredScript())
+		if err != nil {
+			log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
+			return nil
+		}
+	}
+
 	if e.Label != "" {
-		_, err = e.ViewBuf.WriteString(`<label class="active">` + e.Label + `</label>`)
+		_, err = e.ViewBuf.WriteString(`<label class="active` + requiredClass(e.Attrs) + `" for="` + html.EscapeString(elementID(e)) + `">` + e.Label + `</label>`)
 		if err != nil {
 			log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
 			return nil
 		}
 	}
 
-	_, err = e.ViewBuf.WriteString(`</div>`)
+	_, err = e.ViewBuf.WriteString(helpText(e.Attrs) + `</div>`)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElementWithChildrenSelect")
 		return nil
@@ -256,12 +362,10 @@ func DOMElementWithChildrenCheckbox(e *Element, children []*Element) []byte {
 		return nil
 	}
 
-	for attr, value := range e.Attrs {
-		_, err = e.ViewBuf.WriteString(attr + `="` + value + `" `)
-		if err != nil {
-			log.Println("Error writing HTML string to buffer: DOMElementWithChildrenCheckbox")
-			return nil
-		}
+	err = writeAttrs(e.ViewBuf, e.Attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to buffer: DOMElementWithChildrenCheckbox")
+		return nil
 	}
 
 	_, err = e.ViewBuf.WriteString(` >`)
@@ -271,7 +375,7 @@ func DOMElementWithChildrenCheckbox(e *Element, children []*Element) []byte {
 	}
 
 	if e.Label != "" {
-		_, err = e.ViewBuf.WriteString(`<label class="active">` + e.Label + `</label>`)
+		_, err = e.ViewBuf.WriteString(`<label class="active` + requiredClass(e.Attrs) + `">` + e.Label + `</label>`)
 		if err != nil {
 			log.Println("Error writing HTML string to buffer: DOMElementWithChildrenCheckbox")
 			return nil
@@ -287,7 +391,7 @@ func DOMElementWithChildrenCheckbox(e *Element, children []*Element) []byte {
 		}
 	}
 
-	_, err = e.ViewBuf.WriteString(`</` + e.TagName + `><div class="clear padding">&nbsp;</div>`)
+	_, err = e.ViewBuf.WriteString(helpText(e.Attrs) + `</` + e.TagName + `><div class="clear padding">&nbsp;</div>`)
 	if err != nil {
 		log.Println("Error writing HTML string to buffer: DOMElementWithChildrenCheckbox")
 		return nil
@@ -295,3 +399,269 @@ func DOMElementWithChildrenCheckbox(e *Element, children []*Element) []byte {
 
 	return e.ViewBuf.Bytes()
 }
+
+// elementID returns the id of the element e, which is its "id" attr when set, or
+// else one derived from its name, e.g. "field-links-0" for the input named
+// "links.0", so that its <label> can be associated with it. Repeaters renumber
+// the derived ids of their items along with their names.
+func elementID(e *Element) string {
+	if e.Attrs["id"] != "" {
+		return e.Attrs["id"]
+	}
+
+	return fieldID(e.Name)
+}
+
+// fieldID returns the id derived from the field name, see elementID
+func fieldID(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	id := []byte("field-" + name)
+	for i, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			id[i] = '-'
+		}
+	}
+
+	return string(id)
+}
+
+// selectorClass returns the class name escaped for a CSS selector within a
+// single quoted javascript string, e.g. "meta\\2e tags" for the class of the
+// field named "meta.tags", so that names which are nested, or hold any other
+// character which isn't valid in a selector, can still be selected. Names
+// must not contain whitespace, since they are also used as classes.
+func selectorClass(name string) string {
+	var b strings.Builder
+	for i, c := range []byte(name) {
+		letter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '-' || c >= 0x80
+		digit := c >= '0' && c <= '9'
+		if letter || digit && i > 0 {
+			b.WriteByte(c)
+			continue
+		}
+
+		b.WriteString(`\\` + strconv.FormatInt(int64(c), 16) + " ")
+	}
+
+	return b.String()
+}
+
+// labelable reports whether e is a form control which can be labeled
+func labelable(e *Element) bool {
+	switch e.TagName {
+	case "input":
+		return e.Attrs["type"] != "hidden"
+	case "select", "textarea":
+		return true
+	}
+
+	return false
+}
+
+// writeLabelAttrs writes the derived id of e to buf when it has no "id" attr,
+// and an aria-label naming it when it has no visible label, such as the items
+// of a repeater after the first. The aria-label is the field's label, or its
+// placeholder, or else its name.
+func writeLabelAttrs(buf *bytes.Buffer, e *Element) error {
+	if !labelable(e) || e.Name == "" {
+		return nil
+	}
+
+	if e.Attrs["id"] == "" {
+		_, err := buf.WriteString(`id="` + html.EscapeString(fieldID(e.Name)) + `" `)
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.Label != "" || e.Attrs["aria-label"] != "" || e.Attrs["aria-labelledby"] != "" {
+		return nil
+	}
+
+	label := e.Attrs["label"]
+	if label == "" {
+		label = e.Attrs["placeholder"]
+	}
+	if label == "" {
+		label = humanize(strings.SplitN(e.Name, ".", 2)[0])
+	}
+
+	_, err := buf.WriteString(`aria-label="` + html.EscapeString(label) + `" `)
+	return err
+}
+
+// helpText returns the markup of attrs["help"], a short description of a field
+// such as "Max 160 characters, shown in search results", which is shown below
+// the field in muted text. It is empty when there is no help.
+func helpText(attrs map[string]string) string {
+	if attrs["help"] == "" {
+		return ""
+	}
+
+	return `<span class="helper-text">` + html.EscapeString(attrs["help"]) + `</span>`
+}
+
+// editorAttrs are attrs keys which configure the editor field itself and are
+// therefore not rendered as HTML attributes
+var editorAttrs = map[string]bool{
+	"addIcon":        true,
+	"addLabel":       true,
+	"bump":           true,
+	"collapseAbove":  true,
+	"collapsible":    true,
+	"countwords":     true,
+	"ctaLabel":       true,
+	"currency":       true,
+	"default":        true,
+	"delIcon":        true,
+	"delLabel":       true,
+	"emoji":          true,
+	"help":           true,
+	"hideReset":      true,
+	"locale":         true,
+	"maxItems":       true,
+	"maxSuggestions": true,
+	"minItems":       true,
+	"mode":           true,
+	"numbered":       true,
+	"prefix":         true,
+	"preview":        true,
+	"sortable":       true,
+	"suffix":         true,
+	"toolbar":        true,
+	"trim":           true,
+	"unique":         true,
+	"wrapperClass":   true,
+	"wrapperId":      true,
+}
+
+// booleanAttrs are the HTML attributes which are true by being present, such as
+// "disabled" and "selected". They are rendered without a value when true, and
+// left out when false, since even disabled="false" would disable an element.
+var booleanAttrs = map[string]bool{
+	"allowfullscreen": true,
+	"async":           true,
+	"autofocus":       true,
+	"autoplay":        true,
+	"checked":         true,
+	"controls":        true,
+	"defer":           true,
+	"disabled":        true,
+	"formnovalidate":  true,
+	"hidden":          true,
+	"inert":           true,
+	"loop":            true,
+	"multiple":        true,
+	"muted":           true,
+	"novalidate":      true,
+	"open":            true,
+	"playsinline":     true,
+	"readonly":        true,
+	"required":        true,
+	"reversed":        true,
+	"selected":        true,
+}
+
+// boolAttr reports whether value makes a boolean attribute true, which it does
+// unless it is "false" or "0", so that "true", "checked" and "" are all true
+func boolAttr(value string) bool {
+	return !strings.EqualFold(value, "false") && value != "0"
+}
+
+// writeAttrs writes each of the attrs to buf as HTML attributes, in order of
+// their names so that the same attrs always render the same markup, skipping
+// any keys used only to configure the editor. Values are escaped, so that a
+// quote or a '>' can't end the attribute or the element, while keys which are
+// not valid attribute names are rejected, and reported through the Logger.
+func writeAttrs(buf *bytes.Buffer, attrs map[string]string) error {
+	names := make([]string, 0, len(attrs))
+	for attr := range attrs {
+		names = append(names, attr)
+	}
+	sort.Strings(names)
+
+	for _, attr := range names {
+		value := attrs[attr]
+		if editorAttrs[attr] {
+			continue
+		}
+
+		if !validAttrName(attr) {
+			logf("editor: invalid attr name rejected", "attr", attr, "caller", externalCaller())
+			continue
+		}
+
+		if booleanAttrs[attr] {
+			if !boolAttr(value) {
+				continue
+			}
+
+			_, err := buf.WriteString(attr + ` `)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		_, err := buf.WriteString(attr + `="` + html.EscapeString(value) + `" `)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// affixes returns the markup which surrounds an input showing the fixed text of
+// attrs["prefix"] before it and attrs["suffix"] after it, e.g. "https://" or
+// "%", which are only displayed and never submitted with its value. Both are
+// empty unless either is set.
+func affixes(attrs map[string]string) (before, after string) {
+	if attrs["prefix"] == "" && attrs["suffix"] == "" {
+		return "", ""
+	}
+
+	before = `<div class="__ponzu-affixed">`
+	if attrs["prefix"] != "" {
+		before += `<span class="__ponzu-prefix" aria-hidden="true">` + html.EscapeString(attrs["prefix"]) + `</span>`
+	}
+
+	if attrs["suffix"] != "" {
+		after = `<span class="__ponzu-suffix" aria-hidden="true">` + html.EscapeString(attrs["suffix"]) + `</span>`
+	}
+
+	return before, after + `</div>`
+}
+
+// trimValue reports whether the value of e should have leading and trailing
+// whitespace trimmed when submitted. Trimming is on by default for slug-like
+// text inputs, which are those of a Slug and those whose name ends in "slug",
+// e.g. "slug" or "meta.urlSlug", and can be turned on or off for any field with
+// attrs["trim"] set to "true" or "false".
+func trimValue(e *Element) bool {
+	switch e.Attrs["trim"] {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if e.TagName != "input" {
+		return false
+	}
+
+	switch e.Attrs["type"] {
+	case "", "text":
+		if _, ok := e.Attrs["data-ponzu-slug-source"]; ok {
+			return true
+		}
+
+		return strings.HasSuffix(strings.ToLower(e.Name), "slug")
+	}
+
+	return false
+}
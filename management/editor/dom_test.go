@@ -0,0 +1,253 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttrsCannotBreakOut(t *testing.T) {
+	p := &testContact{Name: "Ada", Links: []string{`"><img src=x onerror=alert(1)>`}}
+	attrs := func() map[string]string {
+		return map[string]string{
+			"label":       "Name",
+			"placeholder": `"><script>alert(1)</script>`,
+			"class":       `x" onmouseover="alert(1)`,
+			`title="x"`:   "y",
+			"data-x":      `'>'`,
+		}
+	}
+
+	views := map[string]string{
+		"Input":          string(Input("Name", p, attrs())),
+		"Textarea":       string(Textarea("Name", p, attrs())),
+		"Select":         string(Select("Name", p, attrs(), map[string]string{"a": "A"})),
+		"SelectRepeater": string(SelectRepeater("Links", p, attrs(), map[string]string{"a": "A"})),
+		"FileRepeater":   string(FileRepeater("Links", p, map[string]string{"label": `"><b>Images</b>`})),
+	}
+
+	for name, view := range views {
+		if i := strings.Index(view, "<script>\n"); i != -1 {
+			view = view[:i]
+		}
+
+		for _, s := range []string{`<script>alert`, `" onmouseover="`, `<img src=x`, `title="x"`, `'>'`} {
+			if strings.Contains(view, s) {
+				t.Errorf("%s: expected %s to be escaped or rejected, got: %s", name, s, view)
+			}
+		}
+	}
+
+	if !strings.Contains(views["Input"], `placeholder="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;"`) {
+		t.Errorf("Expected the escaped placeholder, got: %s", views["Input"])
+	}
+
+	if !strings.Contains(views["FileRepeater"], `value="&#34;&gt;&lt;img src=x onerror=alert(1)&gt;"`) ||
+		!strings.Contains(views["FileRepeater"], `placeholder="Add &#34;&gt;&lt;b&gt;Images&lt;/b&gt;"`) {
+		t.Errorf("Expected the escaped stored file, got: %s", views["FileRepeater"])
+	}
+}
+
+func TestValuesCannotBreakOut(t *testing.T) {
+	stored := `"><script>alert('x')</script>`
+	escaped := `value="&#34;&gt;&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt;"`
+	p := &testContact{Name: stored, Bio: stored, Links: []string{"a", stored}}
+
+	views := map[string]string{
+		"Input":         string(Input("Name", p, map[string]string{"type": "text"})),
+		"InputRepeater": string(InputRepeater("Links", p, map[string]string{"type": "text"})),
+		"File":          string(File("Bio", p, map[string]string{"label": "Image"})),
+		"FileRepeater":  string(FileRepeater("Links", p, map[string]string{"label": "Images"})),
+	}
+
+	for name, view := range views {
+		if i := strings.Index(view, "<script>\n"); i != -1 {
+			view = view[:i]
+		}
+
+		if strings.Contains(view, `"><script>`) || !strings.Contains(view, escaped) {
+			t.Errorf("%s: expected the stored value to be escaped, got: %s", name, view)
+		}
+	}
+
+	if !strings.Contains(views["File"], `viewLink.setAttribute('href', "\"\u003e\u003cscript\u003ealert('x')\u003c/script\u003e");`) {
+		t.Errorf("Expected the stored file to be a JavaScript string, got: %s", views["File"])
+	}
+}
+
+func TestLabelsAreAssociated(t *testing.T) {
+	p := &testContact{Name: "Ada", Links: []string{"a", "b"}}
+
+	view := string(Input("Name", p, map[string]string{"label": "Full Name", "type": "text"}))
+	if !strings.Contains(view, `<label class="active" for="field-name">`) || !strings.Contains(view, `id="field-name"`) {
+		t.Errorf("Expected the label to be for the input's id, got: %s", view)
+	}
+
+	view = string(Input("Name", p, map[string]string{"label": "Full Name", "id": "custom"}))
+	if !strings.Contains(view, `for="custom"`) || strings.Contains(view, `field-name`) {
+		t.Errorf("Expected the id attr to be used, got: %s", view)
+	}
+
+	view = string(InputRepeater("Links", p, map[string]string{"label": "Links", "type": "text"}))
+	if i := strings.Index(view, "<script>"); i != -1 {
+		view = view[:i]
+	}
+
+	if !strings.Contains(view, `for="field-links-0"`) || !strings.Contains(view, `id="field-links-0"`) ||
+		!strings.Contains(view, `id="field-links-1"`) {
+		t.Errorf("Expected an id per item, derived from its indexed name, got: %s", view)
+	}
+
+	// only the first item has a visible label
+	if strings.Count(view, `aria-label="Links"`) != 1 {
+		t.Errorf("Expected an aria-label on the unlabeled item, got: %s", view)
+	}
+
+	view = string(Select("Name", p, map[string]string{"label": "Name"}, map[string]string{"a": "A"}))
+	if !strings.Contains(view, `for="field-name"`) || !strings.Contains(view, `id="field-name"`) {
+		t.Errorf("Expected the select to be labeled, got: %s", view)
+	}
+
+	if strings.Contains(view, `<option id=`) || strings.Contains(view, `<option value="a" id=`) {
+		t.Errorf("Expected options not to be given ids, got: %s", view)
+	}
+}
+
+type testProfile struct {
+	Meta struct {
+		Tags []string `json:"tags"`
+	} `json:"meta"`
+}
+
+func TestDottedNamesAreSelectable(t *testing.T) {
+	p := &testProfile{}
+	p.Meta.Tags = []string{"a", "b"}
+
+	view := string(InputRepeater("Meta.Tags", p, map[string]string{"label": "Tags"}))
+	if !strings.Contains(view, `class="__ponzu-repeat meta.tags"`) {
+		t.Errorf("Expected the nested name as the repeater's class, got: %s", view)
+	}
+
+	if !strings.Contains(view, `document.querySelector('.__ponzu-repeat.meta\\2e tags')`) {
+		t.Errorf("Expected the dot to be escaped in the repeater's selector, got: %s", view)
+	}
+
+	if !strings.Contains(view, `var name = "meta.tags" + '.' + String(i);`) {
+		t.Errorf("Expected the items to be named after the nested name, got: %s", view)
+	}
+
+	if !strings.Contains(view, `name="meta.tags.1"`) || !strings.Contains(view, `id="field-meta-tags-1"`) {
+		t.Errorf("Expected the indexed name and derived id, got: %s", view)
+	}
+}
+
+func TestSelectorClass(t *testing.T) {
+	cases := map[string]string{
+		"links":      "links",
+		"meta.tags":  `meta\\2e tags`,
+		"a'b":        `a\\27 b`,
+		"2nd":        `\\32 nd`,
+		"item_2-old": "item_2-old",
+	}
+
+	for in, want := range cases {
+		if got := selectorClass(in); got != want {
+			t.Errorf("selectorClass(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBooleanAttrs(t *testing.T) {
+	p := &testContact{Name: "Ada", Links: []string{"a"}}
+
+	view := string(Input("Name", p, map[string]string{"type": "text", "disabled": "true", "required": "false", "readonly": ""}))
+	if !strings.Contains(view, ` disabled `) || !strings.Contains(view, ` readonly `) {
+		t.Errorf("Expected true boolean attrs without values, got: %s", view)
+	}
+
+	if strings.Contains(view, "required") || strings.Contains(view, `disabled="`) || strings.Contains(view, `readonly="`) {
+		t.Errorf("Expected false boolean attrs to be left out, got: %s", view)
+	}
+
+	view = string(SelectRepeater("Links", p, map[string]string{}, map[string]string{"a": "A", "b": "B"}))
+	if strings.Contains(view, `selected="`) || strings.Contains(view, `disabled="`) {
+		t.Errorf("Expected the options' boolean attrs without values, got: %s", view)
+	}
+
+	if strings.Count(view, " selected ") != 2 {
+		t.Errorf("Expected the call to action and the stored value to be selected, got: %s", view)
+	}
+}
+
+func TestHelpText(t *testing.T) {
+	p := &testContact{Bio: "Hi", Links: []string{"a", "b", "c"}}
+	help := `Max 160 characters, shown in <search> results`
+	escaped := `<span class="helper-text">Max 160 characters, shown in &lt;search&gt; results</span>`
+
+	for name, view := range map[string]string{
+		"Input":    string(Input("Bio", p, map[string]string{"type": "text", "help": help})),
+		"Textarea": string(Textarea("Bio", p, map[string]string{"help": help})),
+		"Checkbox": string(Checkbox("Bio", p, map[string]string{"help": help}, map[string]string{"x": "X"})),
+	} {
+		if strings.Count(view, escaped) != 1 {
+			t.Errorf("%s: expected the escaped help text, got: %s", name, view)
+		}
+
+		if strings.Contains(view, `help=`) {
+			t.Errorf("%s: expected help not to be rendered as an attribute, got: %s", name, view)
+		}
+	}
+
+	for name, view := range map[string]string{
+		"InputRepeater":    string(InputRepeater("Links", p, map[string]string{"type": "text", "help": help})),
+		"TextareaRepeater": string(TextareaRepeater("Links", p, map[string]string{"help": help})),
+		"SelectRepeater":   string(SelectRepeater("Links", p, map[string]string{"help": help}, map[string]string{"a": "A"})),
+		"FileRepeater":     string(FileRepeater("Links", p, map[string]string{"help": help})),
+		"LinkList":         string(LinkList("Links", p, map[string]string{"help": help})),
+	} {
+		if strings.Count(view, `class="helper-text"`) != 1 || !strings.Contains(view, `</span>`+escaped) {
+			t.Errorf("%s: expected the help text once, below the items, got: %s", name, view)
+		}
+	}
+
+	view := string(Input("Bio", p, map[string]string{"type": "text"}))
+	if strings.Contains(view, "helper-text") {
+		t.Errorf("Expected no help text unless set, got: %s", view)
+	}
+}
+
+func TestAttrsSorted(t *testing.T) {
+	p := &testContact{Name: "Ada"}
+	view := string(Input("Name", p, map[string]string{
+		"type": "text", "placeholder": "Name", "class": "big", "data-x": "1", "autocomplete": "off",
+	}))
+
+	want := `autocomplete="off" class="big" data-x="1" placeholder="Name" type="text"`
+	if !strings.Contains(view, want) {
+		t.Errorf("Expected the attrs in order of their names, got: %s", view)
+	}
+}
+
+func TestAffixes(t *testing.T) {
+	p := &testContact{Links: []string{"example.com", "ponzu-cms.org"}}
+
+	view := string(InputRepeater("Links", p, map[string]string{"label": "Links", "type": "text", "prefix": "https://", "suffix": `<"/>`}))
+	view = view[:strings.Index(view, "<script>")]
+
+	affixed := `<div class="__ponzu-affixed"><span class="__ponzu-prefix" aria-hidden="true">https://</span><input value="`
+	if strings.Count(view, affixed) != 2 || strings.Count(view, `<span class="__ponzu-suffix" aria-hidden="true">&lt;&#34;/&gt;</span></div>`) != 2 {
+		t.Errorf("Expected the escaped affixes around each input, got: %s", view)
+	}
+
+	if strings.Count(view, "<label") != 1 || !strings.Contains(view, `<label class="active" for="field-links-0">Links</label>`+affixed+`example.com"`) {
+		t.Errorf("Expected only the first item to be labeled, before its affixes, got: %s", view)
+	}
+
+	if strings.Contains(view, `prefix="`) || strings.Contains(view, `suffix="`) || !strings.Contains(view, `value="ponzu-cms.org"`) {
+		t.Errorf("Expected the affixes to be left out of the attributes and values, got: %s", view)
+	}
+
+	view = string(Input("Name", &testContact{Name: "50"}, map[string]string{"type": "number", "suffix": "%"}))
+	if strings.Contains(view, "__ponzu-prefix") || !strings.Contains(view, `name="name" /><span class="__ponzu-suffix" aria-hidden="true">%</span></div>`) {
+		t.Errorf("Expected only a suffix, got: %s", view)
+	}
+}
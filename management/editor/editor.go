@@ -4,8 +4,11 @@ package editor
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"time"
 )
 
 // Editable ensures data is editable
@@ -21,6 +24,22 @@ type Mergeable interface {
 	Approve(http.ResponseWriter, *http.Request) error
 }
 
+// Autosaver opts a content type in to autosaving drafts from its editor. While
+// the editor is open, its current values are periodically POSTed as JSON to the
+// admin's autosave endpoint, which calls Autosave to store them as the content
+// type sees fit, see FormOptions.AutosaveURL for the request contract.
+type Autosaver interface {
+	Autosave(http.ResponseWriter, *http.Request) error
+}
+
+// DefaultAutosaveURL is the admin endpoint which autosaves content types that
+// implement Autosaver
+const DefaultAutosaveURL = "/admin/edit/autosave"
+
+// DefaultAutosaveInterval is how often the editor autosaves when no interval
+// is set in FormOptions
+const DefaultAutosaveInterval = 30 * time.Second
+
 // Editor is a view containing fields to manage content
 type Editor struct {
 	ViewBuf *bytes.Buffer
@@ -30,35 +49,148 @@ type Editor struct {
 // within a particular content struct
 type Field struct {
 	View []byte
+
+	// Render, when set, writes the field to the form in place of View, so that
+	// the field is streamed by FormTo rather than rendered in memory first, e.g.
+	//	Render: func(w io.Writer) error {
+	//		return editor.InputRepeaterTo(w, "Names", p, attrs)
+	//	}
+	// An error it returns is returned by Form and FormTo, even when the field
+	// is wrapped.
+	Render func(w io.Writer) error
+
+	// Meta describes the field to a FieldWrapper, and is optional
+	Meta FieldMeta
+
+	// Wrap, when set, wraps this field's View in place of FormOptions.Wrap
+	Wrap FieldWrapper
+}
+
+// FieldMeta describes a rendered field to a FieldWrapper
+type FieldMeta struct {
+	// Name is the form name of the field, e.g. "title"
+	Name string
+
+	// Label is the human readable name of the field
+	Label string
+
+	// Error is a validation message to display with the field, if any
+	Error string
+}
+
+// FieldWrapper returns the final markup of a field, given its rendered View and
+// metadata, so that integrators can control the markup around every field, for
+// example to match a custom admin theme
+type FieldWrapper func(fieldHTML []byte, meta FieldMeta) []byte
+
+// FormOptions configures optional parts of the edit page rendered by
+// FormWithOptions. The zero value renders the same page as Form.
+type FormOptions struct {
+	// StickySave renders the Save and Delete controls, plus a Cancel control,
+	// in a bar fixed to the bottom of the window so they stay visible on long
+	// editors
+	StickySave bool
+
+	// CSRFToken is submitted with the form in the hidden CSRFFieldName input.
+	// When empty, the token is read from the CSRF cookie set by SetCSRFCookie
+	// in the browser instead.
+	CSRFToken string
+
+	// AutosaveURL is the endpoint the editor's values are POSTed to while it
+	// is open, and defaults to DefaultAutosaveURL when the content type
+	// implements Autosaver. Autosave is disabled when it is empty.
+	// The request's "type" and "id" query parameters are those of the content
+	// being edited, and its body is a JSON object mapping each submitted field
+	// name to an array of its values, e.g. {"title": ["Hello"], "tags.0":
+	// ["news"]}, as produced by the window.ponzuSerialize(form) function. Any
+	// 2xx response is shown as saved, and anything else as a failure.
+	AutosaveURL string
+
+	// AJAX submits the editor in the background when it is saved, so that
+	// validation errors written by a hook with WriteValidationErrors are shown
+	// alongside their fields without reloading the page and losing the other
+	// values. Once saved, the browser follows the admin's redirect as usual.
+	AJAX bool
+
+	// Wrap, when set, wraps the View of every Field in the form which does not
+	// set its own Wrap
+	Wrap FieldWrapper
+
+	// AutosaveInterval is the time between autosaves, which only happen when
+	// the values have changed. It defaults to DefaultAutosaveInterval.
+	AutosaveInterval time.Duration
+
+	// ReadOnly renders the form for review rather than editing, e.g. as a
+	// preview or for users without permission to edit. Every field is
+	// disabled, by rendering the form within a disabled <fieldset>, so their
+	// values are still shown but can't be changed or submitted. The Save and
+	// Delete controls, autosave, and the controls of repeaters are left out.
+	ReadOnly bool
 }
 
 // Form takes editable content and any number of Field funcs to describe the edit
 // page for any content struct added by a user
 func Form(post Editable, fields ...Field) ([]byte, error) {
+	return FormWithOptions(post, FormOptions{}, fields...)
+}
+
+// FormWithOptions is like Form, but renders the edit page configured by opts
+func FormWithOptions(post Editable, opts FormOptions, fields ...Field) ([]byte, error) {
 	editor := &Editor{}
 
 	editor.ViewBuf = &bytes.Buffer{}
-	_, err := editor.ViewBuf.WriteString(`<table><tbody class="row"><tr class="col s8 editor-fields"><td class="col s12">`)
+
+	err := FormWithOptionsTo(editor.ViewBuf, post, opts, fields...)
 	if err != nil {
-		log.Println("Error writing HTML string to editor Form buffer")
 		return nil, err
 	}
 
+	return editor.ViewBuf.Bytes(), nil
+}
+
+// FormTo is like Form, but writes the edit page to w as it is rendered, such as
+// directly to an http.ResponseWriter, rather than returning it. Fields which
+// set Render are written to w without being rendered in memory first.
+func FormTo(w io.Writer, post Editable, fields ...Field) error {
+	return FormWithOptionsTo(w, post, FormOptions{}, fields...)
+}
+
+// FormWithOptionsTo is like FormTo, but writes the edit page configured by opts
+func FormWithOptionsTo(w io.Writer, post Editable, opts FormOptions, fields ...Field) error {
+	var before, after string
+	if opts.ReadOnly {
+		before, after = `<fieldset class="__ponzu-readonly" disabled>`, `</fieldset>`+readOnlyScript
+	}
+
+	_, err := io.WriteString(w, before+`<table><tbody class="row"><tr class="col s8 editor-fields"><td class="col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to editor Form buffer")
+		return err
+	}
+
 	for _, f := range fields {
-		addFieldToEditorView(editor, f)
+		f, err = wrapField(f, opts.Wrap)
+		if err != nil {
+			return err
+		}
+
+		err = addFieldToEditorView(w, f)
+		if err != nil {
+			return err
+		}
 	}
 
-	_, err = editor.ViewBuf.WriteString(`</td></tr>`)
+	_, err = io.WriteString(w, `</td></tr>`)
 	if err != nil {
 		log.Println("Error writing HTML string to editor Form buffer")
-		return nil, err
+		return err
 	}
 
 	// content items with Item embedded have some default fields we need to render
-	_, err = editor.ViewBuf.WriteString(`<tr class="col s4 default-fields"><td class="col s12">`)
+	_, err = io.WriteString(w, `<tr class="col s4 default-fields"><td class="col s12">`)
 	if err != nil {
 		log.Println("Error writing HTML string to editor Form buffer")
-		return nil, err
+		return err
 	}
 
 	publishTime := `
@@ -110,37 +242,83 @@ func Form(post Editable, fields ...Field) ([]byte, error) {
 </div>
 	`
 
-	_, err = editor.ViewBuf.WriteString(publishTime)
+	_, err = io.WriteString(w, publishTime)
 	if err != nil {
 		log.Println("Error writing HTML string to editor Form buffer")
-		return nil, err
+		return err
 	}
 
-	err = addPostDefaultFieldsToEditorView(post, editor)
+	err = addPostDefaultFieldsToEditorView(post, w)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	// every editor form submits a CSRF token, see SetCSRFCookie
+	_, err = io.WriteString(w, csrfInput(opts.CSRFToken))
+	if err != nil {
+		log.Println("Error writing HTML string to editor Form buffer")
+		return err
 	}
 
 	submit := `
 <div class="input-field post-controls">
-	<button class="right waves-effect waves-light btn green save-post" type="submit">Save</button>
-	<button class="right waves-effect waves-light btn red delete-post" type="submit">Delete</button>
+	<button class="right waves-effect waves-light btn green save-post" type="submit">` + htmlText("editor.save") + `</button>
+	<button class="right waves-effect waves-light btn red delete-post" type="submit">` + htmlText("editor.delete") + `</button>
+</div>
+`
+	if opts.StickySave {
+		submit = `
+<div class="__ponzu-sticky-save">
+	<div class="input-field post-controls">
+		<button class="right waves-effect waves-light btn green save-post" type="submit">` + htmlText("editor.save") + `</button>
+		<button class="right waves-effect waves-light btn red delete-post" type="submit">` + htmlText("editor.delete") + `</button>
+		<button class="right waves-effect waves-light btn-flat cancel-post" type="button">` + htmlText("editor.cancel") + `</button>
+	</div>
 </div>
 `
+	}
+
+	autosaveURL := opts.AutosaveURL
+	if _, ok := post.(Autosaver); ok && autosaveURL == "" {
+		autosaveURL = DefaultAutosaveURL
+	}
+
+	if opts.ReadOnly {
+		submit, autosaveURL = "", ""
+	}
+
+	interval := opts.AutosaveInterval
+	if interval <= 0 {
+		interval = DefaultAutosaveInterval
+	}
+
 	_, ok := post.(Mergeable)
-	if ok {
+	if ok && !opts.ReadOnly {
 		submit +=
 			`
 <div class="row external post-controls">
 	<div class="col s12 input-field">
-		<button class="right waves-effect waves-light btn blue approve-post" type="submit">Approve</button>
-		<button class="right waves-effect waves-light btn grey darken-2 reject-post" type="submit">Reject</button>
+		<button class="right waves-effect waves-light btn blue approve-post" type="submit">` + htmlText("editor.approve") + `</button>
+		<button class="right waves-effect waves-light btn grey darken-2 reject-post" type="submit">` + htmlText("editor.reject") + `</button>
 	</div>	
 	<label class="approve-details right-align col s12">This content is pending approval. By clicking 'Approve', it will be immediately published. By clicking 'Reject', it will be deleted.</label> 
 </div>
 `
 	}
 
+	if autosaveURL != "" {
+		submit += `
+<div class="right-align">
+	<span class="__ponzu-autosave-status grey-text"></span>
+</div>
+`
+	}
+
+	var ajax string
+	if opts.AJAX && !opts.ReadOnly {
+		ajax = ajaxScript()
+	}
+
 	script := `
 <script>
 	$(function() {
@@ -169,6 +347,32 @@ func Form(post Editable, fields ...Field) ([]byte, error) {
 			slug.parent().hide();
 		}
 
+		// trim leading and trailing whitespace from fields which opt in to it,
+		// as soon as they lose focus and again just before saving
+		var trim = function(i, el) {
+			el.value = $.trim(el.value);
+		}
+
+		form.on('blur', '[data-ponzu-trim]', function(e) {
+			trim(0, e.target);
+		});
+
+		form.on('submit', function(e) {
+			form.find('[data-ponzu-trim]').each(trim);
+		});
+
+		// submitForm submits the form through a native submit event, unlike
+		// jQuery's form.submit(), so that handlers added with addEventListener
+		// run too. A handler cancelling the event stops the form submitting.
+		var submitForm = function() {
+			var ev = document.createEvent('HTMLEvents');
+			ev.initEvent('submit', true, true);
+
+			if (form.get(0).dispatchEvent(ev)) {
+				form.get(0).submit();
+			}
+		}
+
 		save.on('click', function(e) {
 			e.preventDefault();
 
@@ -177,7 +381,66 @@ func Form(post Editable, fields ...Field) ([]byte, error) {
 				form.attr('action', action + '?status=pending')
 			}
 
-			form.submit();
+			submitForm();
+		});
+
+		// ponzuSerialize returns the values the form would currently submit, as
+		// an object of field name -> array of values. Inputs without a name,
+		// such as removed repeater items, are left out.
+		window.ponzuSerialize = function(f) {
+			var values = {};
+			$(f).find('input, select, textarea').each(function(i, el) {
+				var $el = $(el), name = $el.attr('name');
+				if (!name || el.disabled || $el.is(':file, :button, :submit, :reset')) {
+					return;
+				}
+
+				if ($el.is(':checkbox, :radio') && !el.checked) {
+					return;
+				}
+
+				var val = $el.val();
+				values[name] = (values[name] || []).concat($.isArray(val) ? val : [val === null ? '' : val]);
+			});
+
+			return values;
+		}
+
+		var autosaveURL = ` + jsString(autosaveURL) + `;
+		if (autosaveURL !== '' && form.attr('action') === '/admin/edit') {
+			var status = form.find('.__ponzu-autosave-status'),
+				last = JSON.stringify(window.ponzuSerialize(form));
+
+			setInterval(function() {
+				var current = JSON.stringify(window.ponzuSerialize(form));
+				if (current === last) {
+					return;
+				}
+
+				var url = autosaveURL + (autosaveURL.indexOf('?') === -1 ? '?' : '&') +
+					'type=' + encodeURIComponent(form.find('input[name=type]').val() || '') +
+					'&id=' + encodeURIComponent(id.val() || '');
+
+				status.text(` + jsString(text("autosave.saving")) + `);
+				$.ajax({
+					url: url,
+					method: 'POST',
+					contentType: 'application/json',
+					headers: {'` + CSRFHeaderName + `': ` + csrfToken + `},
+					data: current
+				}).done(function() {
+					last = current;
+					status.text(` + jsString(text("autosave.saved")) + `.replace('{time}', new Date().toLocaleTimeString()));
+				}).fail(function() {
+					status.text(` + jsString(text("autosave.failed")) + `);
+				});
+			}, ` + fmt.Sprintf("%d", interval.Nanoseconds()/int64(time.Millisecond)) + `);
+		}
+
+` + ajax + `
+		form.find('button.cancel-post').on('click', function(e) {
+			e.preventDefault();
+			window.history.back();
 		});
 
 		del.on('click', function(e) {
@@ -197,7 +460,7 @@ func Form(post Editable, fields ...Field) ([]byte, error) {
 			action = action + '/approve';
 			form.attr('action', action);
 
-			form.submit();
+			submitForm();
 		});
 
 		external.find('button.reject-post').on('click', function(e) {
@@ -213,17 +476,43 @@ func Form(post Editable, fields ...Field) ([]byte, error) {
 	});
 </script>
 `
-	_, err = editor.ViewBuf.WriteString(submit + script + `</td></tr></tbody></table>`)
+	_, err = io.WriteString(w, submit+script+`</td></tr></tbody></table>`+after)
 	if err != nil {
 		log.Println("Error writing HTML string to editor Form buffer")
-		return nil, err
+		return err
 	}
 
-	return editor.ViewBuf.Bytes(), nil
+	return nil
 }
 
-func addFieldToEditorView(e *Editor, f Field) error {
-	_, err := e.ViewBuf.Write(f.View)
+// readOnlyScript stops a form rendered with FormOptions.ReadOnly from being
+// edited or submitted by anything a disabled <fieldset> doesn't disable
+const readOnlyScript = `
+<script>
+	$(function() {
+		var scope = $('.__ponzu-readonly');
+
+		scope.find('[contenteditable]').attr('contenteditable', 'false');
+		scope.find('input, textarea').attr('readonly', 'readonly');
+		scope.closest('form').on('submit', function(e) {
+			e.preventDefault();
+		});
+	});
+</script>
+`
+
+func addFieldToEditorView(w io.Writer, f Field) error {
+	if f.Render != nil {
+		err := f.Render(w)
+		if err != nil {
+			log.Println("Error rendering field to editor view")
+			return err
+		}
+
+		return nil
+	}
+
+	_, err := w.Write(f.View)
 	if err != nil {
 		log.Println("Error writing field view to editor view buffer")
 		return err
@@ -232,7 +521,35 @@ func addFieldToEditorView(e *Editor, f Field) error {
 	return nil
 }
 
-func addPostDefaultFieldsToEditorView(p Editable, e *Editor) error {
+// wrapField returns f with its View wrapped by its own Wrap func, or else by
+// wrap. f is returned unchanged when neither is set. A field which sets Render
+// is rendered into its View first, since it must be wrapped as a whole, and
+// the error of its Render is returned.
+func wrapField(f Field, wrap FieldWrapper) (Field, error) {
+	if f.Wrap != nil {
+		wrap = f.Wrap
+	}
+
+	if wrap == nil {
+		return f, nil
+	}
+
+	if f.Render != nil {
+		view := &bytes.Buffer{}
+		err := f.Render(view)
+		if err != nil {
+			log.Println("Error rendering field to wrap in editor view")
+			return f, err
+		}
+
+		f.View, f.Render = view.Bytes(), nil
+	}
+
+	f.View = wrap(f.View, f.Meta)
+	return f, nil
+}
+
+func addPostDefaultFieldsToEditorView(p Editable, w io.Writer) error {
 	defaults := []Field{
 		{
 			View: Input("Slug", p, map[string]string{
@@ -257,7 +574,7 @@ func addPostDefaultFieldsToEditorView(p Editable, e *Editor) error {
 	}
 
 	for _, f := range defaults {
-		err := addFieldToEditorView(e, f)
+		err := addFieldToEditorView(w, f)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,293 @@
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type testPost struct {
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Timestamp int64  `json:"timestamp"`
+	Updated   int64  `json:"updated"`
+}
+
+func (p *testPost) MarshalEditor() ([]byte, error) {
+	return Form(p, Field{View: Input("Title", p, map[string]string{"type": "text"})})
+}
+
+func TestFormReadOnly(t *testing.T) {
+	p := &testPost{Title: "Hello"}
+	fields := []Field{{View: Input("Title", p, map[string]string{"type": "text"})}}
+
+	view, err := FormWithOptions(p, FormOptions{ReadOnly: true}, fields...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := string(view)
+	if !strings.HasPrefix(s, `<fieldset class="__ponzu-readonly" disabled>`) || !strings.Contains(s, `value="Hello"`) {
+		t.Errorf("Expected the fields to be rendered within a disabled fieldset, got: %s", s)
+	}
+
+	if strings.Contains(s, `btn green save-post`) {
+		t.Errorf("Expected no Save control, got: %s", s)
+	}
+
+	view, err = Form(p, fields...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(view), "__ponzu-readonly") || !strings.Contains(string(view), `btn green save-post`) {
+		t.Errorf("Expected an editable form by default, got: %s", view)
+	}
+}
+
+// testForm returns the fields of a form of several repeaters of p, rendered
+// either as Views or by Render funcs
+func testForm(p *testContact, stream bool) []Field {
+	attrs := func() map[string]string { return map[string]string{"type": "text"} }
+	options := map[string]string{"x": "X", "y": "Y"}
+
+	if !stream {
+		return []Field{
+			{View: InputRepeater("Links", p, attrs())},
+			{View: TextareaRepeater("Links", p, attrs())},
+			{View: SelectRepeater("Links", p, attrs(), options)},
+			{View: FileRepeater("Links", p, map[string]string{})},
+		}
+	}
+
+	return []Field{
+		{Render: func(w io.Writer) error { return InputRepeaterTo(w, "Links", p, attrs()) }},
+		{Render: func(w io.Writer) error { return TextareaRepeaterTo(w, "Links", p, attrs()) }},
+		{Render: func(w io.Writer) error { return SelectRepeaterTo(w, "Links", p, attrs(), options) }},
+		{Render: func(w io.Writer) error { return FileRepeaterTo(w, "Links", p, map[string]string{}) }},
+	}
+}
+
+// sortedBytes returns the bytes of b in order, to compare markup regardless of
+// the order of its attributes
+func sortedBytes(b []byte) string {
+	sorted := append([]byte{}, b...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return string(sorted)
+}
+
+// errWriter fails every write once n bytes have been written
+type errWriter struct {
+	n int
+}
+
+func (w *errWriter) Write(b []byte) (int, error) {
+	if len(b) > w.n {
+		return 0, errors.New("write failed")
+	}
+
+	w.n -= len(b)
+	return len(b), nil
+}
+
+func TestFormTo(t *testing.T) {
+	p := &testContact{Links: []string{"a", "b", "c"}}
+	post := &testPost{Title: "Hello"}
+
+	want, err := Form(post, testForm(p, false)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = FormTo(buf, post, testForm(p, true)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the order of attributes may differ between renders
+	if sortedBytes(buf.Bytes()) != sortedBytes(want) {
+		t.Errorf("Expected FormTo to write the same page as Form, got: %s\nwant: %s", buf, want)
+	}
+
+	wrap := func(view []byte, meta FieldMeta) []byte {
+		return append(append([]byte(`<div class="wrapped">`), view...), `</div>`...)
+	}
+
+	view, err := FormWithOptions(post, FormOptions{Wrap: wrap}, testForm(p, true)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(string(view), `<div class="wrapped"><span class="__ponzu-repeat`) != 4 {
+		t.Errorf("Expected the rendered fields to be wrapped, got: %s", view)
+	}
+
+	err = FormTo(&errWriter{n: len(want) / 2}, post, testForm(p, true)...)
+	if err == nil {
+		t.Errorf("Expected the error of the writer to be returned")
+	}
+
+	err = InputRepeaterTo(&errWriter{n: 100}, "Links", p, map[string]string{"type": "text"})
+	if err == nil {
+		t.Errorf("Expected InputRepeaterTo to return the error of the writer")
+	}
+}
+
+func TestFormWrapRenderError(t *testing.T) {
+	post := &testPost{Title: "Hello"}
+	renderErr := errors.New("render failed")
+	failing := Field{Render: func(w io.Writer) error { return renderErr }}
+
+	wrap := func(view []byte, meta FieldMeta) []byte {
+		return append(append([]byte(`<div class="wrapped">`), view...), `</div>`...)
+	}
+
+	// a field which fails to render is not wrapped, whichever Wrap applies
+	own := failing
+	own.Wrap = wrap
+	for name, render := range map[string]func() error{
+		"FormOptions.Wrap": func() error {
+			_, err := FormWithOptions(post, FormOptions{Wrap: wrap}, failing)
+			return err
+		},
+		"Field.Wrap": func() error {
+			return FormTo(ioutil.Discard, post, own)
+		},
+		"Fieldset": func() error {
+			_, err := Form(post, Fieldset("Details", own))
+			return err
+		},
+	} {
+		if err := render(); err != renderErr {
+			t.Errorf("%s: expected the error of the field's Render, got: %v", name, err)
+		}
+	}
+}
+
+func TestFormButtonLabels(t *testing.T) {
+	post := &testPost{Title: "Hello"}
+
+	for _, sticky := range []bool{false, true} {
+		view, err := FormWithOptions(post, FormOptions{StickySave: sticky})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(view), `btn green save-post" type="submit">Save</button>`) ||
+			!strings.Contains(string(view), `btn red delete-post" type="submit">Delete</button>`) {
+			t.Errorf("Sticky %t: expected the default labels, got: %s", sticky, view)
+		}
+	}
+
+	defer SetStrings(nil)
+	SetStrings(map[string]string{
+		"editor.save":   "Enregistrer",
+		"editor.delete": "Supprimer",
+		"editor.cancel": `Annuler & "revenir"`,
+	})
+
+	for _, sticky := range []bool{false, true} {
+		view, err := FormWithOptions(post, FormOptions{StickySave: sticky})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(view), `save-post" type="submit">Enregistrer</button>`) ||
+			!strings.Contains(string(view), `delete-post" type="submit">Supprimer</button>`) ||
+			strings.Contains(string(view), ">Save<") {
+			t.Errorf("Sticky %t: expected the translated labels, got: %s", sticky, view)
+		}
+
+		cancel := strings.Contains(string(view), `cancel-post" type="button">Annuler &amp; &#34;revenir&#34;</button>`)
+		if cancel != sticky {
+			t.Errorf("Sticky %t: expected the escaped Cancel only when sticky, got: %s", sticky, view)
+		}
+	}
+}
+
+func TestFormSubmitEvent(t *testing.T) {
+	p := &testContact{Links: []string{"1", "", "2"}}
+	post := &testPost{Title: "Hello"}
+
+	view, err := Form(post, Field{View: NumberRepeater("Links", p, nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the number repeater drops its empty items in a native submit listener,
+	// which jQuery's form.submit() would never run
+	s := string(view)
+	if !strings.Contains(s, "document.addEventListener('submit'") {
+		t.Fatalf("Expected the repeater to listen for native submit events, got: %s", s)
+	}
+
+	save := s[strings.Index(s, "save.on('click'"):]
+	save = save[:strings.Index(save, "});")]
+	if !strings.Contains(save, "submitForm();") || strings.Contains(save, "form.submit()") {
+		t.Errorf("Expected Save to submit through a native submit event, got: %s", save)
+	}
+
+	approve := s[strings.Index(s, "button.approve-post').on('click'"):]
+	approve = approve[:strings.Index(approve, "});")]
+	if !strings.Contains(approve, "submitForm();") || strings.Contains(approve, "form.submit()") {
+		t.Errorf("Expected Approve to submit through a native submit event, got: %s", approve)
+	}
+
+	for _, want := range []string{
+		"ev.initEvent('submit', true, true);",
+		"if (form.get(0).dispatchEvent(ev)) {\n\t\t\t\tform.get(0).submit();",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Expected the dispatched event to be cancelable, got: %s", s)
+		}
+	}
+}
+
+func BenchmarkForm(b *testing.B) {
+	p := &testContact{Links: []string{"a", "b", "c", "d", "e"}}
+	post := &testPost{Title: "Hello"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Form(post, testForm(p, false)...)
+	}
+}
+
+func BenchmarkFormTo(b *testing.B) {
+	p := &testContact{Links: []string{"a", "b", "c", "d", "e"}}
+	post := &testPost{Title: "Hello"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FormTo(ioutil.Discard, post, testForm(p, true)...)
+	}
+}
+
+func TestHidden(t *testing.T) {
+	p := &testPost{Title: `a&b "c" <d>`}
+
+	view, err := Form(p, Field{View: Hidden("Title", p)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<input type="hidden" name="title" value="a&amp;b &#34;c&#34; &lt;d&gt;" />`
+	if !strings.Contains(string(view), want) {
+		t.Fatalf("Expected %s within the form, got: %s", want, view)
+	}
+
+	value := strings.TrimSuffix(strings.TrimPrefix(want, `<input type="hidden" name="title" value="`), `" />`)
+	if html.UnescapeString(value) != p.Title {
+		t.Errorf("Expected the submitted value to be the stored value, got: %s", html.UnescapeString(value))
+	}
+
+	if bytes.Contains(Hidden("Title", p), []byte("label")) {
+		t.Errorf("Expected no label, got: %s", Hidden("Title", p))
+	}
+}
@@ -3,10 +3,12 @@ package editor
 import (
 	"bytes"
 	"html"
-	"strings"
+	"net/url"
 )
 
 // Input returns the []byte of an <input> HTML element with a label.
+// attrs["prefix"] and attrs["suffix"] are fixed text shown before and after
+// the input, e.g. "https://" or "%", which is not part of its value.
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
@@ -30,26 +32,55 @@ import (
 // 		)
 // 	}
 func Input(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Input", fieldName, attrs)
+
 	e := NewElement("input", attrs["label"], fieldName, p, attrs)
 
 	return DOMElementSelfClose(e)
 }
 
+// Hidden returns the []byte of an <input type="hidden"> HTML element holding
+// the stored value of the field, without a label or wrapper, for values the
+// editor should keep but not show, such as a computed key or a reference to a
+// parent. The value is submitted unchanged, so it is stored again on save.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Hidden(fieldName string, p interface{}) []byte {
+	return []byte(`<input type="hidden" name="` + TagNameFromStructField(fieldName, p) +
+		`" value="` + html.EscapeString(ValueFromStructField(fieldName, p)) + `" />`)
+}
+
 // Textarea returns the []byte of a <textarea> HTML element with a label.
+// attrs["maxlength"] limits its length, shown by a live counter of the
+// characters used, and attrs["countwords"] set to "true" counts its words
+// instead, without limiting them.
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
 func Textarea(fieldName string, p interface{}, attrs map[string]string) []byte {
-	// add materialize css class to make UI correct
+	checkAttrs("Textarea", fieldName, attrs)
+
+	// add materialize css class to make UI correct, without changing attrs
 	className := "materialize-textarea"
-	if _, ok := attrs["class"]; ok {
-		class := attrs["class"]
-		attrs["class"] = class + " " + className
+	taAttrs := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		taAttrs[k] = v
+	}
+
+	if _, ok := taAttrs["class"]; ok {
+		class := taAttrs["class"]
+		taAttrs["class"] = class + " " + className
 	} else {
-		attrs["class"] = className
+		taAttrs["class"] = className
 	}
 
-	e := NewElement("textarea", attrs["label"], fieldName, p, attrs)
+	counted := counterAttrs(fieldName, taAttrs)
+	e := NewElement("textarea", attrs["label"], fieldName, p, counted)
+
+	if counted["data-ponzu-count"] != "" {
+		return append(DOMElement(e), counterScript()...)
+	}
 
 	return DOMElement(e)
 }
@@ -59,6 +90,8 @@ func Textarea(fieldName string, p interface{}, attrs map[string]string) []byte {
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
 func Timestamp(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Timestamp", fieldName, attrs)
+
 	var data string
 	val := ValueFromStructField(fieldName, p)
 	if val == "0" {
@@ -80,10 +113,15 @@ func Timestamp(fieldName string, p interface{}, attrs map[string]string) []byte
 }
 
 // File returns the []byte of a <input type="file"> HTML element with a label.
+// Stored images, videos and audio files are previewed, with an HTML5 player
+// for video and audio, and newly selected audio files can be played before
+// they are uploaded.
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
 func File(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("File", fieldName, attrs)
+
 	name := TagNameFromStructField(fieldName, p)
 	value := ValueFromStructField(fieldName, p)
 	tmpl :=
@@ -91,21 +129,39 @@ func File(fieldName string, p interface{}, attrs map[string]string) []byte {
 			<label class="active">` + attrs["label"] + `</label>
 			<div class="file-field input-field">
 				<div class="btn">
-					<span>Upload</span>
+					<span>` + htmlText("file.upload") + `</span>
 					<input class="upload" type="file">
 				</div>
 				<div class="file-path-wrapper">
 					<input class="file-path validate" placeholder="` + attrs["label"] + `" type="text">
 				</div>
 			</div>
+			<span class="file-error red-text"></span>
 			<div class="preview"><div class="img-clip"></div></div>			
-			<input class="store ` + name + `" type="hidden" name="` + name + `" value="` + value + `" />
+			<input class="store ` + name + `" type="hidden" name="` + name + `" value="` + html.EscapeString(value) + `" />
 		</div>`
 
+	// reject selected images which don't meet any dimension constraints,
+	// restoring the previously stored file
+	var checkDimensions, dimensionsCall string
+	if dims := ImageConstraintsFromAttrs(attrs); !dims.IsZero() {
+		checkDimensions = imageDimensionsScript(dims)
+		dimensionsCall = `
+					$file.find('.file-error').text('');
+					checkDimensions(e.target, function(msg) {
+						$file.find('.file-error').text(msg);
+						upload.val('');
+						upload.attr('name', '');
+						$file.find('.file-path').val('');
+						store.val(uploadSrc);
+						store.attr('name', '` + name + `');
+					});`
+	}
+
 	script :=
 		`<script>
 			$(function() {
-				var $file = $('.file-input.` + name + `'),
+				var $file = $('.file-input.` + selectorClass(name) + `'),
 					upload = $file.find('input.upload'),
 					store = $file.find('input.store'),
 					preview = $file.find('.preview'),
@@ -113,15 +169,16 @@ func File(fieldName string, p interface{}, attrs map[string]string) []byte {
 					reset = document.createElement('div'),
 					img = document.createElement('img'),
 					video = document.createElement('video'),
+					audio = document.createElement('audio'),
 					unknown = document.createElement('div'),
 					viewLink = document.createElement('a'),
-					viewLinkText = document.createTextNode('Download / View '),
+					viewLinkText = document.createTextNode(` + jsString(text("file.view")) + `),
 					iconLaunch = document.createElement('i'),
 					iconLaunchText = document.createTextNode('launch'),
 					uploadSrc = store.val();
 					video.setAttribute
 					preview.hide();
-					viewLink.setAttribute('href', '` + value + `');
+					viewLink.setAttribute('href', ` + jsString(value) + `);
 					viewLink.setAttribute('target', '_blank');
 					viewLink.appendChild(viewLinkText);
 					viewLink.style.display = 'block';
@@ -139,8 +196,27 @@ func File(fieldName string, p interface{}, attrs map[string]string) []byte {
 				// add the 'name' attr to ` + name + ` input
 				upload.on('change', function(e) {
 					resetImage();
+					previewAudio(e.target);` + dimensionsCall + `
 				});
 
+				// preview a newly selected audio file with a player, since it
+				// can't be checked or shown like an image
+				function previewAudio(input) {
+					var file = input.files && input.files[0];
+					if (!file || !/^audio\//.test(file.type) || !window.URL) {
+						return;
+					}
+
+					$(audio)
+						.attr('src', URL.createObjectURL(file))
+						.attr('controls', true)
+						.css('width', '100%');
+					clip.append(audio);
+					clip.addClass('audio');
+					$(viewLink).hide();
+					preview.css('opacity', 1).show();
+				}
+` + checkDimensions + `
 				if (uploadSrc.length > 0) {
 					var ext = uploadSrc.substring(uploadSrc.lastIndexOf('.'));
 					ext = ext.toLowerCase();
@@ -162,6 +238,21 @@ func File(fieldName string, p interface{}, attrs map[string]string) []byte {
 								.css('width', '100%');
 							clip.append(video);
 							break;
+						case '.mp3':
+						case '.m4a':
+						case '.aac':
+						case '.oga':
+						case '.ogg':
+						case '.opus':
+						case '.wav':
+						case '.flac':
+							$(audio)
+								.attr('src', store.val())
+								.attr('controls', true)
+								.css('width', '100%');
+							clip.append(audio);
+							clip.addClass('audio');
+							break;
 						default:
 							$(img).attr('src', '/admin/static/dashboard/img/ponzu-file.png');
 							$(unknown)
@@ -202,7 +293,16 @@ func File(fieldName string, p interface{}, attrs map[string]string) []byte {
 					store.val('');
 					store.attr('name', '');
 					upload.attr('name', '` + name + `');
+
+					// stop any audio, which would keep playing once removed
+					audio.pause();
+					if (audio.src.indexOf('blob:') === 0) {
+						URL.revokeObjectURL(audio.src);
+					}
+					audio.removeAttribute('src');
+
 					clip.empty();
+					clip.removeClass('audio');
 				}
 			});	
 		</script>`
@@ -215,26 +315,34 @@ func File(fieldName string, p interface{}, attrs map[string]string) []byte {
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
 func Richtext(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Richtext", fieldName, attrs)
+
 	// create wrapper for richtext editor, which isolates the editor's css
 	iso := []byte(`<div class="iso-texteditor input-field col s12"><label>` + attrs["label"] + `</label>`)
 	isoClose := []byte(`</div>`)
 
-	if _, ok := attrs["class"]; ok {
-		attrs["class"] += "richtext " + fieldName
+	// target the editor, without changing attrs
+	divAttrs := make(map[string]string, len(attrs)+2)
+	for k, v := range attrs {
+		divAttrs[k] = v
+	}
+
+	if _, ok := divAttrs["class"]; ok {
+		divAttrs["class"] += "richtext " + fieldName
 	} else {
-		attrs["class"] = "richtext " + fieldName
+		divAttrs["class"] = "richtext " + fieldName
 	}
 
-	if _, ok := attrs["id"]; ok {
-		attrs["id"] += "richtext-" + fieldName
+	if _, ok := divAttrs["id"]; ok {
+		divAttrs["id"] += "richtext-" + fieldName
 	} else {
-		attrs["id"] = "richtext-" + fieldName
+		divAttrs["id"] = "richtext-" + fieldName
 	}
 
 	// create the target element for the editor to attach itself
 	div := &Element{
 		TagName: "div",
-		Attrs:   attrs,
+		Attrs:   divAttrs,
 		Name:    "",
 		Label:   "",
 		Data:    "",
@@ -254,8 +362,8 @@ func Richtext(fieldName string, p interface{}, attrs map[string]string) []byte {
 	script := `
 	<script>
 		$(function() { 
-			var _editor = $('.richtext.` + fieldName + `');
-			var hidden = $('.richtext-value.` + fieldName + `');
+			var _editor = $('.richtext.` + selectorClass(fieldName) + `');
+			var hidden = $('.richtext-value.` + selectorClass(fieldName) + `');
 
 			_editor.materialnote({
 				height: 250,
@@ -318,30 +426,40 @@ func Richtext(fieldName string, p interface{}, attrs map[string]string) []byte {
 }
 
 // Select returns the []byte of a <select> HTML element plus internal <options> with a label.
+// Options are displayed in order of their labels.
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
 func Select(fieldName string, p interface{}, attrs, options map[string]string) []byte {
+	checkAttrs("Select", fieldName, attrs)
+
 	// options are the value attr and the display value, i.e.
-	// <option value="{map key}">{map value}</option>
+	// <option value="{map key}">{map value}</option>, displayed in order of
+	// their display values so the order is the same on every render
 
 	// find the field value in p to determine if an option is pre-selected
-	fieldVal := ValueFromStructField(fieldName, p)
+	fieldVal := defaultValue(p, ValueFromStructField(fieldName, p), attrs)
+
+	// style the select, without changing attrs
+	selAttrs := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		selAttrs[k] = v
+	}
 
-	if _, ok := attrs["class"]; ok {
-		attrs["class"] += " browser-default"
+	if _, ok := selAttrs["class"]; ok {
+		selAttrs["class"] += " browser-default"
 	} else {
-		attrs["class"] = "browser-default"
+		selAttrs["class"] = "browser-default"
 	}
 
-	sel := NewElement("select", attrs["label"], fieldName, p, attrs)
+	sel := NewElement("select", attrs["label"], fieldName, p, selAttrs)
 	var opts []*Element
 
 	// provide a call to action for the select element
 	cta := &Element{
 		TagName: "option",
 		Attrs:   map[string]string{"disabled": "true", "selected": "true"},
-		Data:    "Select an option...",
+		Data:    text("select.cta"),
 		ViewBuf: &bytes.Buffer{},
 	}
 
@@ -349,21 +467,21 @@ func Select(fieldName string, p interface{}, attrs, options map[string]string) [
 	reset := &Element{
 		TagName: "option",
 		Attrs:   map[string]string{"value": ""},
-		Data:    "None",
+		Data:    text("select.none"),
 		ViewBuf: &bytes.Buffer{},
 	}
 
 	opts = append(opts, cta, reset)
 
-	for k, v := range options {
-		optAttrs := map[string]string{"value": k}
-		if k == fieldVal {
+	for _, o := range sortedOptions(options) {
+		optAttrs := map[string]string{"value": o.Value}
+		if o.Value == fieldVal {
 			optAttrs["selected"] = "true"
 		}
 		opt := &Element{
 			TagName: "option",
 			Attrs:   optAttrs,
-			Data:    v,
+			Data:    o.Label,
 			ViewBuf: &bytes.Buffer{},
 		}
 
@@ -374,36 +492,44 @@ func Select(fieldName string, p interface{}, attrs, options map[string]string) [
 }
 
 // Checkbox returns the []byte of a set of <input type="checkbox"> HTML elements
-// wrapped in a <div> with a label.
+// wrapped in a <div> with a label. Options are displayed in order of their
+// labels.
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
 func Checkbox(fieldName string, p interface{}, attrs, options map[string]string) []byte {
-	if _, ok := attrs["class"]; ok {
-		attrs["class"] += "input-field col s12"
+	checkAttrs("Checkbox", fieldName, attrs)
+
+	// style the wrapper, without changing attrs
+	divAttrs := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		divAttrs[k] = v
+	}
+
+	if _, ok := divAttrs["class"]; ok {
+		divAttrs["class"] += "input-field col s12"
 	} else {
-		attrs["class"] = "input-field col s12"
+		divAttrs["class"] = "input-field col s12"
 	}
 
-	div := NewElement("div", attrs["label"], fieldName, p, attrs)
+	div := NewElement("div", attrs["label"], fieldName, p, divAttrs)
 
 	var opts []*Element
 
 	// get the pre-checked options if this is already an existing post
-	checkedVals := ValueFromStructField(fieldName, p)
-	checked := strings.Split(checkedVals, "__ponzu")
+	checked := defaultValues(p, ValuesFromStructField(fieldName, p), attrs)
 
-	i := 0
-	for k, v := range options {
+	// the options are in order of their labels, so that each is rendered at
+	// the same index, and with the same name and id, on every render
+	for i, o := range sortedOptions(options) {
 		inputAttrs := map[string]string{
 			"type":  "checkbox",
-			"value": k,
-			"id":    strings.Join(strings.Split(v, " "), "-"),
+			"value": o.Value,
 		}
 
-		// check if k is in the pre-checked values and set to checked
+		// check if the option is in the pre-checked values and set to checked
 		for _, x := range checked {
-			if k == x {
+			if o.Value == x {
 				inputAttrs["checked"] = "checked"
 			}
 		}
@@ -414,108 +540,126 @@ func Checkbox(fieldName string, p interface{}, attrs, options map[string]string)
 			TagName: "input",
 			Attrs:   inputAttrs,
 			Name:    TagNameFromStructFieldMulti(fieldName, i, p),
-			Label:   v,
+			Label:   o.Label,
 			Data:    "",
 			ViewBuf: &bytes.Buffer{},
 		}
 
 		opts = append(opts, input)
-		i++
 	}
 
 	return DOMElementWithChildrenCheckbox(div, opts)
 }
 
-// Tags returns the []byte of a tag input (in the style of Materialze 'Chips') with a label.
+// RenderedHTML returns the []byte of a read-only view of the HTML stored in a
+// field, so editors can see the formatted content. The HTML is sanitized and
+// displayed inside a sandboxed <iframe>, and nothing is submitted with the form.
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
-func Tags(fieldName string, p interface{}, attrs map[string]string) []byte {
+func RenderedHTML(fieldName string, p interface{}) []byte {
 	name := TagNameFromStructField(fieldName, p)
+	value := sanitizeHTML(ValueFromStructField(fieldName, p), defaultAllowedHTML)
 
-	// get the saved tags if this is already an existing post
-	values := ValueFromStructField(fieldName, p)
-	var tags []string
-	if strings.Contains(values, "__ponzu") {
-		tags = strings.Split(values, "__ponzu")
-	}
+	view := `<div class="__ponzu-rendered-html ` + name + ` input-field col s12">` +
+		`<iframe sandbox="" class="__ponzu-rendered-html-frame" srcdoc="` + html.EscapeString(value) + `"></iframe>` +
+		`</div>`
+
+	return []byte(view)
+}
+
+// DistinctValuesSelect returns the []byte of a <select> HTML element whose
+// options are the distinct values of sourceField across all items of
+// contentType, e.g. to pick an existing category name, plus an "Other..."
+// option which reveals a text input for a new value.
+// The options are loaded when the editor opens from attrs["endpoint"], which
+// defaults to the public content API, "/api/contents?type=<contentType>&count=-1".
+// Any endpoint must respond with JSON in the same shape as the content API,
+// i.e. {"data": [{...}, ...]}, where sourceField is the `json` key of the value
+// in each item. String values are used as-is, and each element of an array
+// value is used as its own option. Content types which implement
+// item.Hideable are not served by the content API, and need their own endpoint.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func DistinctValuesSelect(fieldName string, p interface{}, contentType, sourceField string, attrs map[string]string) []byte {
+	checkAttrs("DistinctValuesSelect", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
 
-	// case where there is only one tag stored, thus has no separator
-	if len(values) > 0 && !strings.Contains(values, "__ponzu") {
-		tags = append(tags, values)
+	endpoint := attrs["endpoint"]
+	if endpoint == "" {
+		endpoint = "/api/contents?type=" + url.QueryEscape(contentType) + "&count=-1"
 	}
 
-	html := `
-	<div class="col s12 __ponzu-tags ` + name + `">
-		<label class="active">` + attrs["label"] + ` (Type and press "Enter")</label>
-		<div class="chips ` + name + `"></div>
-	`
-
-	var initial []string
-	i := 0
-	for _, tag := range tags {
-		tagName := TagNameFromStructFieldMulti(fieldName, i, p)
-		html += `<input type="hidden" class="__ponzu-tag ` + tag + `" name=` + tagName + ` value="` + tag + `"/>`
-		initial = append(initial, `{tag: '`+tag+`'}`)
-		i++
+	var stored string
+	if value != "" {
+		stored = `<option value="` + html.EscapeString(value) + `" selected>` + html.EscapeString(value) + `</option>`
 	}
 
-	script := `
+	view := `<div class="__ponzu-distinct-select ` + name + ` input-field col s12">
+		<label class="active">` + attrs["label"] + `</label>
+		<select class="browser-default">
+			<option value="" disabled` + selectedIf(value == "") + `>` + htmlText("select.cta") + `</option>
+			` + stored + `
+			<option value="__ponzu-other">` + htmlText("select.other") + `</option>
+		</select>
+		<input type="text" class="__ponzu-distinct-other" name="` + name + `" value="` + html.EscapeString(value) + `" placeholder="` + htmlText("select.newValue") + `" style="display: none;" />
+	</div>
 	<script>
 		$(function() {
-			var tags = $('.__ponzu-tags.` + name + `');
-			$('.chips.` + name + `').material_chip({
-				data: [` + strings.Join(initial, ",") + `],
-				secondaryPlaceholder: '+` + name + `'
-			});		
-
-			// handle events specific to tags
-			var chips = tags.find('.chips');
-			
-			chips.on('chip.add', function(e, chip) {
-				chips.parent().find('.empty-tag').remove();
-				
-				var input = $('<input>');
-				input.attr({
-					class: '__ponzu-tag '+chip.tag.split(' ').join('__'),
-					name: '` + name + `.'+String(tags.find('input[type=hidden]').length),
-					value: chip.tag,
-					type: 'hidden'
-				});
-				
-				tags.append(input);
+			var $field = $('.__ponzu-distinct-select.` + selectorClass(name) + `'),
+				sel = $field.find('select'),
+				other = sel.find('option[value="__ponzu-other"]'),
+				input = $field.find('.__ponzu-distinct-other'),
+				source = ` + jsString(sourceField) + `;
+
+			sel.on('change', function(e) {
+				if (sel.val() === '__ponzu-other') {
+					input.val('').show().focus();
+					return;
+				}
+
+				input.hide().val(sel.val());
 			});
 
-			chips.on('chip.delete', function(e, chip) {
-				// convert tag string to class-like selector "some tag" -> ".some.tag"
-				var sel = '.__ponzu-tag.' + chip.tag.split(' ').join('__');
-				chips.parent().find(sel).remove();
-
-				// iterate through all hidden tag inputs to re-name them with the correct ` + name + `.index
-				var hidden = chips.parent().find('input[type=hidden]');
-				
-				// if there are no tags, set a blank
-				if (hidden.length === 0) {
-					var input = $('<input>');
-					input.attr({
-						class: 'empty-tag',
-						name: '` + name + `',
-						type: 'hidden'
-					});
-					
-					tags.append(input);
-				}
-				
-				// re-name hidden storage elements in necessary format 
-				for (var i = 0; i < hidden.length; i++) {
-					$(hidden[i]).attr('name', '` + name + `.'+String(i));
+			$.getJSON(` + jsString(endpoint) + `, function(resp) {
+				var seen = {}, values = [];
+				sel.find('option').each(function(i, opt) {
+					seen[$(opt).val()] = true;
+				});
+
+				var add = function(v) {
+					if (typeof v !== 'string' && typeof v !== 'number') {
+						return;
+					}
+
+					v = String(v);
+					if (v === '' || seen[v]) {
+						return;
+					}
+
+					seen[v] = true;
+					values.push(v);
 				}
+
+				$.each((resp && resp.data) || [], function(i, item) {
+					var v = item[source];
+					if ($.isArray(v)) {
+						$.each(v, function(j, x) { add(x); });
+					} else {
+						add(v);
+					}
+				});
+
+				values.sort();
+				$.each(values, function(i, v) {
+					other.before($('<option>').val(v).text(v));
+				});
 			});
 		});
-	</script>
-	`
-
-	html += `</div>`
+	</script>`
 
-	return []byte(html + script)
+	return []byte(view)
 }
@@ -0,0 +1,72 @@
+package editor
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type testHTMLPage struct {
+	Body string `json:"body"`
+}
+
+func TestRenderedHTML(t *testing.T) {
+	p := &testHTMLPage{Body: `<p>Tom &amp; "Jerry"</p><a href="https://example.com/?a=1&amp;b=2" onclick="steal()">x</a><script>alert(1)</script>`}
+	view := string(RenderedHTML("Body", p))
+
+	// the frame is sandboxed without any permissions, so that the stored HTML
+	// can't run scripts or reach the admin
+	if !strings.Contains(view, `<iframe sandbox="" class="__ponzu-rendered-html-frame" srcdoc="`) {
+		t.Errorf("Expected a sandboxed frame, got: %s", view)
+	}
+
+	// the quotes and ampersands of the HTML are escaped within srcdoc, so the
+	// attribute holds all of it
+	m := regexp.MustCompile(`srcdoc="([^"]*)"></iframe></div>$`).FindStringSubmatch(view)
+	if m == nil {
+		t.Fatalf("Expected the whole HTML within srcdoc, got: %s", view)
+	}
+
+	for _, want := range []string{`&amp;amp; &#34;Jerry&#34;`, `href=&#34;https://example.com/?a=1&amp;amp;b=2&#34;`} {
+		if !strings.Contains(m[1], want) {
+			t.Errorf("Expected %s within srcdoc, got: %s", want, m[1])
+		}
+	}
+
+	doc := html.UnescapeString(m[1])
+	if doc != `<p>Tom &amp; "Jerry"</p><a href="https://example.com/?a=1&amp;b=2">x</a>` {
+		t.Errorf("Expected the sanitized HTML as the frame's document, got: %s", doc)
+	}
+}
+
+func TestDistinctValuesSelect(t *testing.T) {
+	p := &testContact{Name: "News"}
+
+	// the endpoint is a JSON string, in which & is written as \u0026
+	view := string(DistinctValuesSelect("Name", p, "Post", "category", map[string]string{"label": "Category"}))
+	if !strings.Contains(view, `$.getJSON("/api/contents?type=Post\u0026count=-1", function(resp) {`) {
+		t.Errorf("Expected the content API as the default endpoint, got: %s", view)
+	}
+
+	for _, want := range []string{
+		`<option value="News" selected>News</option>`,
+		`name="name" value="News"`,
+		`source = "category";`,
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %s, got: %s", want, view)
+		}
+	}
+
+	// the content type is escaped within the query of the endpoint
+	view = string(DistinctValuesSelect("Name", p, "Post&count=1#x", "category", map[string]string{}))
+	if !strings.Contains(view, `$.getJSON("/api/contents?type=Post%26count%3D1%23x\u0026count=-1", function(resp) {`) {
+		t.Errorf("Expected the content type to be escaped, got: %s", view)
+	}
+
+	view = string(DistinctValuesSelect("Name", p, "Post", "category", map[string]string{"endpoint": "/admin/categories?all=true"}))
+	if !strings.Contains(view, `$.getJSON("/admin/categories?all=true", function(resp) {`) || strings.Contains(view, "/api/contents") {
+		t.Errorf("Expected the custom endpoint to override the default, got: %s", view)
+	}
+}
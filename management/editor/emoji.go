@@ -0,0 +1,76 @@
+package editor
+
+import "bytes"
+
+// emojiChars are the characters offered by the picker which is added to text
+// inputs and textareas when attrs["emoji"] is "true"
+var emojiChars = []string{
+	"😀", "😂", "😉", "😍", "🤔", "😢", "👍", "👏", "🙏", "🎉",
+	"🔥", "✨", "❤️", "⭐", "✅", "❌", "⚠️", "📌", "→", "←",
+	"↑", "↓", "•", "—", "©", "®", "™", "°", "±", "×",
+	"€", "£", "¥", "§",
+}
+
+// emojiPicker returns the toggle and menu of the emoji/special-character picker
+// along with the script that powers it. The script binds delegated handlers
+// once per page, so pickers within repeater clones work without re-binding.
+func emojiPicker() string {
+	picker := &bytes.Buffer{}
+	picker.WriteString(`<a href="#" class="__ponzu-emoji-toggle grey-text" title="` + htmlText("emoji.title") + `">` +
+		`<i class="material-icons">insert_emoticon</i></a>`)
+	picker.WriteString(`<div class="__ponzu-emoji-picker card-panel" style="display: none;">`)
+	for _, c := range emojiChars {
+		picker.WriteString(`<button type="button" class="btn-flat">` + c + `</button>`)
+	}
+	picker.WriteString(`</div>`)
+
+	return picker.String() + emojiScript
+}
+
+const emojiScript = `
+<script>
+	$(function() {
+		if (window.__ponzuEmojiPicker) {
+			return;
+		}
+		window.__ponzuEmojiPicker = true;
+
+		$(document).on('click', '.__ponzu-emoji-toggle', function(e) {
+			e.preventDefault();
+			$(this).siblings('.__ponzu-emoji-picker').toggle();
+		});
+
+		$(document).on('click', '.__ponzu-emoji-picker button', function(e) {
+			e.preventDefault();
+
+			var ch = $(this).text(),
+				picker = $(this).closest('.__ponzu-emoji-picker'),
+				field = picker.closest('.input-field')
+					.find('input:not([type=hidden]):not(.file-path), textarea')
+					.first().get(0);
+
+			if (!field) {
+				return;
+			}
+
+			// insert the character at the cursor, replacing any selection.
+			// some input types do not support selection, so append instead
+			var start = null, end = null;
+			try {
+				start = field.selectionStart;
+				end = field.selectionEnd;
+			} catch (err) {}
+
+			if (typeof start === 'number' && typeof end === 'number') {
+				field.value = field.value.slice(0, start) + ch + field.value.slice(end);
+				field.selectionStart = field.selectionEnd = start + ch.length;
+			} else {
+				field.value += ch;
+			}
+
+			picker.hide();
+			$(field).trigger('input').focus();
+		});
+	});
+</script>
+`
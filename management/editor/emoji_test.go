@@ -0,0 +1,42 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmojiPicker(t *testing.T) {
+	p := &testContact{Name: "Ada", Bio: "Hi"}
+
+	for name, view := range map[string]string{
+		"Input":    string(Input("Name", p, map[string]string{"type": "text", "emoji": "true"})),
+		"Textarea": string(Textarea("Bio", p, map[string]string{"emoji": "true"})),
+	} {
+		if strings.Count(view, `<a href="#" class="__ponzu-emoji-toggle grey-text" title="Insert an emoji or symbol">`) != 1 ||
+			strings.Count(view, `<button type="button" class="btn-flat">`) != len(emojiChars) {
+			t.Errorf("%s: expected the picker with a button per character, got: %s", name, view)
+		}
+
+		if strings.Contains(view, `emoji="`) {
+			t.Errorf("%s: expected emoji not to be rendered as an attribute, got: %s", name, view)
+		}
+
+		if strings.Count(view, "window.__ponzuEmojiPicker = true") != 1 {
+			t.Errorf("%s: expected the picker's script, got: %s", name, view)
+		}
+	}
+
+	for _, attrs := range []map[string]string{{"type": "text"}, {"type": "text", "emoji": "false"}} {
+		if view := string(Input("Name", p, attrs)); strings.Contains(view, "__ponzu-emoji") {
+			t.Errorf("Expected no picker unless emoji is true, got: %s", view)
+		}
+	}
+
+	defer SetStrings(nil)
+	SetStrings(map[string]string{"emoji.title": `Insérer un "emoji"`})
+
+	view := string(Input("Name", p, map[string]string{"type": "text", "emoji": "true"}))
+	if !strings.Contains(view, `title="Insérer un &#34;emoji&#34;"`) {
+		t.Errorf("Expected the translated and escaped title, got: %s", view)
+	}
+}
@@ -0,0 +1,271 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+)
+
+// EnumOption is one of the allowed values of an enum field, with the Label
+// displayed for it
+type EnumOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// EnumPills returns the []byte of an ordered list of removable pills for a
+// slice of enum values, such as the steps of a workflow. New pills are chosen
+// from a dropdown of the enum values not already selected, so each value can
+// only be selected once, and pills are reordered by dragging them, or with the
+// arrow keys once focused. Stored values which are not in enum are kept, and
+// are displayed as they are. The values are submitted in order as "name.0",
+// "name.1", and so on.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func EnumPills(fieldName string, p interface{}, enum []EnumOption, attrs map[string]string) []byte {
+	checkAttrs("EnumPills", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	name := TagNameFromStructField(fieldName, p)
+
+	labels := make(map[string]string, len(enum))
+	for _, opt := range enum {
+		labels[opt.Value] = opt.Label
+	}
+
+	var values []string
+	for _, v := range ValuesFromStructField(fieldName, p) {
+		if v != "" && !hasString(values, v) {
+			values = append(values, v)
+		}
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-enum-pills ` + html.EscapeString(name) + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to EnumPills buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to EnumPills buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<div class="__ponzu-pill-list">`)
+	if err != nil {
+		log.Println("Error writing HTML string to EnumPills buffer")
+		return nil
+	}
+
+	for i, v := range values {
+		label, ok := labels[v]
+		if !ok {
+			label = v
+		}
+
+		_, err = view.WriteString(`<div class="chip" draggable="true" tabindex="0" title="` + htmlText("repeat.drag") +
+			`" data-value="` + html.EscapeString(v) + `">` + html.EscapeString(label) +
+			`<i class="material-icons close" title="` + htmlText("tokens.remove") + `">close</i>` +
+			`<input type="hidden" name="` + html.EscapeString(fmt.Sprintf("%s.%d", name, i)) + `" value="` + html.EscapeString(v) + `" /></div>`)
+		if err != nil {
+			log.Println("Error writing HTML string to EnumPills buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`</div><div class="__ponzu-pill-add row">` +
+		`<div class="col s8"><select class="browser-default"></select></div>` +
+		`<div class="col s4"><button type="button" class="btn-flat waves-effect waves-green">` + htmlText("group.add") + `</button></div>` +
+		`</div></div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to EnumPills buffer")
+		return nil
+	}
+
+	data, err := json.Marshal(enum)
+	if err != nil || enum == nil {
+		data = []byte("[]")
+	}
+
+	script := `
+	<script>
+		(function() {
+			var init = function() {
+				var scope = document.querySelector('.__ponzu-enum-pills.` + selectorClass(name) + `');
+				if (!scope) {
+					return;
+				}
+
+				var pills = scope.querySelector('.__ponzu-pill-list'),
+					select = scope.querySelector('.__ponzu-pill-add select'),
+					add = scope.querySelector('.__ponzu-pill-add button'),
+					name = ` + jsString(name) + `,
+					enumOptions = ` + string(data) + `,
+					dragging = null;
+
+				var chips = function() {
+					return Array.prototype.slice.call(pills.querySelectorAll('.chip'));
+				}
+
+				var chosen = function() {
+					return chips().map(function(chip) {
+						return chip.getAttribute('data-value');
+					});
+				}
+
+				// reset the names of the pills to their order, and only offer
+				// the enum values which are not already chosen
+				var update = function() {
+					chips().forEach(function(chip, i) {
+						chip.querySelector('input[type=hidden]').setAttribute('name', name + '.' + String(i));
+					});
+
+					var taken = chosen();
+					while (select.firstChild) {
+						select.removeChild(select.firstChild);
+					}
+
+					enumOptions.forEach(function(opt) {
+						if (taken.indexOf(opt.value) === -1) {
+							var option = document.createElement('option');
+							option.value = opt.value;
+							option.textContent = opt.label;
+							select.appendChild(option);
+						}
+					});
+
+					var none = select.options.length === 0;
+					select.disabled = none;
+					add.disabled = none;
+				}
+
+				add.addEventListener('click', function(e) {
+					e.preventDefault();
+
+					var value = select.value;
+					if (!value || chosen().indexOf(value) !== -1) {
+						return;
+					}
+
+					var pill = document.createElement('div');
+					pill.className = 'chip';
+					pill.setAttribute('draggable', 'true');
+					pill.setAttribute('tabindex', '0');
+					pill.title = ` + jsString(text("repeat.drag")) + `;
+					pill.setAttribute('data-value', value);
+					pill.textContent = select.options[select.selectedIndex].textContent;
+
+					var close = document.createElement('i');
+					close.className = 'material-icons close';
+					close.title = ` + jsString(text("tokens.remove")) + `;
+					close.textContent = 'close';
+					pill.appendChild(close);
+
+					var input = document.createElement('input');
+					input.type = 'hidden';
+					input.value = value;
+					pill.appendChild(input);
+
+					pills.appendChild(pill);
+					update();
+				});
+
+				pills.addEventListener('click', function(e) {
+					if (!e.target.classList.contains('close')) {
+						return;
+					}
+
+					e.preventDefault();
+					pills.removeChild(e.target.closest('.chip'));
+					update();
+				});
+
+				// move a focused pill before or after its neighbour
+				pills.addEventListener('keydown', function(e) {
+					var chip = e.target.classList.contains('chip') ? e.target : null;
+					if (!chip) {
+						return;
+					}
+
+					if (e.key === 'ArrowLeft' || e.key === 'ArrowUp') {
+						if (chip.previousElementSibling) {
+							pills.insertBefore(chip, chip.previousElementSibling);
+						}
+					} else if (e.key === 'ArrowRight' || e.key === 'ArrowDown') {
+						if (chip.nextElementSibling) {
+							pills.insertBefore(chip.nextElementSibling, chip);
+						}
+					} else {
+						return;
+					}
+
+					e.preventDefault();
+					chip.focus();
+					update();
+				});
+
+				pills.addEventListener('dragstart', function(e) {
+					dragging = e.target.closest ? e.target.closest('.chip') : null;
+					if (!dragging) {
+						return;
+					}
+
+					e.dataTransfer.effectAllowed = 'move';
+					e.dataTransfer.setData('text/plain', '');
+				});
+
+				pills.addEventListener('dragover', function(e) {
+					var over = e.target.closest ? e.target.closest('.chip') : null;
+					if (!dragging || !over) {
+						return;
+					}
+
+					e.preventDefault();
+					if (over === dragging) {
+						return;
+					}
+
+					var rect = over.getBoundingClientRect();
+					if (e.clientX - rect.left > rect.width / 2) {
+						pills.insertBefore(dragging, over.nextSibling);
+					} else {
+						pills.insertBefore(dragging, over);
+					}
+				});
+
+				var drop = function(e) {
+					if (!dragging) {
+						return;
+					}
+
+					e.preventDefault();
+					dragging = null;
+					update();
+				}
+
+				pills.addEventListener('drop', drop);
+				pills.addEventListener('dragend', drop);
+
+				update();
+			}
+
+			if (document.readyState === 'loading') {
+				document.addEventListener('DOMContentLoaded', init);
+			} else {
+				init();
+			}
+		})();
+	</script>`
+
+	return append(view.Bytes(), script...)
+}
@@ -0,0 +1,88 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+type testWorkflow struct {
+	Steps []string `json:"a&b"`
+}
+
+var testEnum = []EnumOption{{"draft", "Draft"}, {"review", "In review"}, {"live", `"Live" & <b>on</b>`}}
+
+func TestEnumPills(t *testing.T) {
+	p := &testContact{Links: []string{"live", "draft", "", "legacy", "draft"}}
+
+	view := string(EnumPills("Links", p, testEnum, map[string]string{"label": "Steps"}))
+	markup := view[:strings.Index(view, "<script>")]
+
+	// the stored values are rendered in order, once each, with their labels
+	last := -1
+	for i, want := range []string{
+		`data-value="live">&#34;Live&#34; &amp; &lt;b&gt;on&lt;/b&gt;<i`,
+		`<input type="hidden" name="links.0" value="live" />`,
+		`data-value="draft">Draft<i`,
+		`<input type="hidden" name="links.1" value="draft" />`,
+		`data-value="legacy">legacy<i`,
+		`<input type="hidden" name="links.2" value="legacy" />`,
+	} {
+		at := strings.Index(markup, want)
+		if at <= last {
+			t.Errorf("%d: expected %s after the previous pill, got: %s", i, want, markup)
+		}
+		last = at
+	}
+
+	if strings.Count(markup, `<div class="chip" draggable="true" tabindex="0" title="Drag to reorder"`) != 3 {
+		t.Errorf("Expected each pill to be draggable and focusable, got: %s", markup)
+	}
+
+	for _, js := range []string{
+		`enumOptions = [{"value":"draft","label":"Draft"},{"value":"review","label":"In review"},{"value":"live","label":"\"Live\" \u0026 \u003cb\u003eon\u003c/b\u003e"}],`,
+		"pills.addEventListener('dragover', function(e) {",
+		"if (e.key === 'ArrowLeft' || e.key === 'ArrowUp') {",
+		"chip.querySelector('input[type=hidden]').setAttribute('name', name + '.' + String(i));",
+	} {
+		if !strings.Contains(view, js) {
+			t.Errorf("Expected the script to contain %s, got: %s", js, view)
+		}
+	}
+
+	if strings.Contains(view, "$(") {
+		t.Errorf("Expected the script not to use jQuery, got: %s", view)
+	}
+}
+
+func TestEnumPillsEscapesName(t *testing.T) {
+	p := &testWorkflow{Steps: []string{`x" onclick="y`}}
+
+	view := string(EnumPills("Steps", p, testEnum, nil))
+	for _, want := range []string{
+		`<div class="__ponzu-enum-pills a&amp;b input-field col s12">`,
+		`<input type="hidden" name="a&amp;b.0" value="x&#34; onclick=&#34;y" />`,
+		`document.querySelector('.__ponzu-enum-pills.a\\26 b');`,
+		`name = "a\u0026b",`,
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %s, got: %s", want, view)
+		}
+	}
+}
+
+func TestEnumPillsNil(t *testing.T) {
+	view := string(EnumPills("Links", &testContact{}, nil, nil))
+	if strings.Contains(view, `class="chip"`) || !strings.Contains(view, "enumOptions = [],") {
+		t.Errorf("Expected no pills and no options, got: %s", view)
+	}
+
+	if strings.Contains(view, "<label") {
+		t.Errorf("Expected no label without attrs, got: %s", view)
+	}
+
+	var nilContact *testContact
+	view = string(EnumPills("Links", nilContact, testEnum, nil))
+	if !strings.HasPrefix(view, `<div class="input-field col s12"><span class="__ponzu-field-error red-text">`) {
+		t.Errorf("Expected an inline error for nil content, got: %s", view)
+	}
+}
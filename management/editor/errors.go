@@ -0,0 +1,263 @@
+package editor
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+)
+
+// FieldError is returned when a field name passed to an editor field cannot be
+// resolved on the content struct, e.g. because of a typo
+type FieldError struct {
+	// Field is the field name as it was passed, e.g. "Naem"
+	Field string
+
+	// Type is the type of the content struct, e.g. "*content.Person"
+	Type string
+
+	// Valid are the names of the fields which could have been used at the
+	// point where Field could not be resolved
+	Valid []string
+
+	// Reason explains what was wrong when the field exists but can't be used
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("field %q on %s: %s", e.Field, e.Type, e.Reason)
+	}
+
+	return fmt.Sprintf("field %q not found on %s (valid fields: %s)",
+		e.Field, e.Type, strings.Join(e.Valid, ", "))
+}
+
+// checkField returns a *FieldError unless the (possibly dotted) fieldName is an
+// exported field of the struct pointed to by p, has a `json` tag, and holds a
+// type which the editor can render as a string. Names are matched exactly,
+// including case.
+func checkField(fieldName string, p interface{}) error {
+	err := checkContent(fieldName, p)
+	if err != nil {
+		return err
+	}
+
+	typ := fmt.Sprintf("%v", reflect.TypeOf(p))
+	t := reflect.TypeOf(p).Elem()
+	for _, part := range strings.Split(fieldName, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return &FieldError{Field: fieldName, Type: typ, Reason: fmt.Sprintf("%s is not a struct", t)}
+		}
+
+		f, ok := t.FieldByName(part)
+		if !ok || f.PkgPath != "" {
+			return &FieldError{Field: fieldName, Type: typ, Valid: fieldNames(t)}
+		}
+
+		if _, ok := f.Tag.Lookup("json"); !ok {
+			return &FieldError{Field: fieldName, Type: typ, Reason: "struct fields for content types must have 'json' tags"}
+		}
+
+		t = f.Type
+	}
+
+	if t == timeType || (t.Kind() == reflect.Ptr && t.Elem() == timeType) {
+		return nil
+	}
+
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return nil
+	}
+
+	return &FieldError{Field: fieldName, Type: typ, Reason: fmt.Sprintf("type %s is not supported", t)}
+}
+
+// checkContent returns a *FieldError unless p, the content of the field named
+// fieldName, is a non-nil pointer to a struct, as every editor field requires
+func checkContent(fieldName string, p interface{}) error {
+	typ := fmt.Sprintf("%v", reflect.TypeOf(p))
+
+	t := reflect.TypeOf(p)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return &FieldError{Field: fieldName, Type: typ, Reason: "content must be a pointer to a struct"}
+	}
+
+	if reflect.ValueOf(p).IsNil() {
+		return &FieldError{Field: fieldName, Type: typ, Reason: "content is a nil pointer"}
+	}
+
+	return nil
+}
+
+// contentError returns an inline message rendered in place of the field named
+// fieldName when p is not valid content for it (see checkContent), so that a
+// content type which is wired up wrong shows what is wrong in its editor
+// rather than crashing it. It is nil when p is valid.
+func contentError(fieldName string, p interface{}) []byte {
+	err := checkContent(fieldName, p)
+	if err == nil {
+		return nil
+	}
+
+	logResolveFailure(fieldName, p, err)
+
+	return []byte(`<div class="input-field col s12"><span class="__ponzu-field-error red-text">` +
+		html.EscapeString(err.Error()) + `</span></div>`)
+}
+
+// fieldError returns the markup of the message of a field's validation error,
+// like those shown alongside the fields of a submission rejected over AJAX
+func fieldError(msg string) string {
+	return `<span class="__ponzu-field-error red-text">` + html.EscapeString(msg) + `</span>`
+}
+
+// fieldNames returns the names of the exported fields of the struct type t,
+// including those promoted from embedded structs
+func fieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if f.Anonymous {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				names = append(names, fieldNames(ft)...)
+				continue
+			}
+		}
+
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+// InputRepeaterErr is like InputRepeater, but returns a *FieldError rather than
+// panicking when fieldName cannot be resolved on p
+func InputRepeaterErr(fieldName string, p interface{}, attrs map[string]string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return InputRepeater(fieldName, p, attrs), nil
+}
+
+// SelectRepeaterErr is like SelectRepeater, but returns a *FieldError rather
+// than panicking when fieldName cannot be resolved on p
+func SelectRepeaterErr(fieldName string, p interface{}, attrs, options map[string]string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return SelectRepeater(fieldName, p, attrs, options), nil
+}
+
+// SelectRepeaterOrderedErr is like SelectRepeaterOrdered, but returns a
+// *FieldError rather than panicking when fieldName cannot be resolved on p
+func SelectRepeaterOrderedErr(fieldName string, p interface{}, attrs map[string]string, options []Option) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return SelectRepeaterOrdered(fieldName, p, attrs, options), nil
+}
+
+// FileRepeaterErr is like FileRepeater, but returns a *FieldError rather than
+// panicking when fieldName cannot be resolved on p
+func FileRepeaterErr(fieldName string, p interface{}, attrs map[string]string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return FileRepeater(fieldName, p, attrs), nil
+}
+
+// TextareaRepeaterErr is like TextareaRepeater, but returns a *FieldError
+// rather than panicking when fieldName cannot be resolved on p
+func TextareaRepeaterErr(fieldName string, p interface{}, attrs map[string]string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return TextareaRepeater(fieldName, p, attrs), nil
+}
+
+// NumberRepeaterErr is like NumberRepeater, but returns a *FieldError rather
+// than panicking when fieldName cannot be resolved on p
+func NumberRepeaterErr(fieldName string, p interface{}, attrs map[string]string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return NumberRepeater(fieldName, p, attrs), nil
+}
+
+// ColorRepeaterErr is like ColorRepeater, but returns a *FieldError rather
+// than panicking when fieldName cannot be resolved on p
+func ColorRepeaterErr(fieldName string, p interface{}, attrs map[string]string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return ColorRepeater(fieldName, p, attrs), nil
+}
+
+// TimeRepeaterErr is like TimeRepeater, but returns a *FieldError rather than
+// panicking when fieldName cannot be resolved on p
+func TimeRepeaterErr(fieldName string, p interface{}, attrs map[string]string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return TimeRepeater(fieldName, p, attrs), nil
+}
+
+// AutocompleteRepeaterErr is like AutocompleteRepeater, but returns a
+// *FieldError rather than panicking when fieldName cannot be resolved on p
+func AutocompleteRepeaterErr(fieldName string, p interface{}, attrs map[string]string, suggestions []string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return AutocompleteRepeater(fieldName, p, attrs, suggestions), nil
+}
+
+// ReferenceRepeaterErr is like ReferenceRepeater, but returns a *FieldError
+// rather than panicking when fieldName cannot be resolved on p
+func ReferenceRepeaterErr(fieldName string, p interface{}, attrs map[string]string, contentType string) ([]byte, error) {
+	err := checkField(fieldName, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReferenceRepeater(fieldName, p, attrs, contentType), nil
+}
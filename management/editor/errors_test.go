@@ -0,0 +1,142 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepeaterErrUnknownField(t *testing.T) {
+	p := &testContact{}
+
+	view, err := InputRepeaterErr("Naem", p, map[string]string{})
+	if err == nil {
+		t.Fatal("Expected an error for an unknown field, got nil")
+	}
+
+	if view != nil {
+		t.Errorf("Expected no view with an error, got: %s", view)
+	}
+
+	msg := err.Error()
+	for _, want := range []string{`"Naem"`, "*editor.testContact", "Name", "Links"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected error to contain %s, got: %s", want, msg)
+		}
+	}
+
+	if _, err := SelectRepeaterErr("links", p, map[string]string{}, nil); err == nil {
+		t.Error("Expected field names to be matched case-sensitively")
+	}
+
+	if _, err := FileRepeaterErr("Links", testContact{}, map[string]string{}); err == nil {
+		t.Error("Expected an error for a non-pointer content struct")
+	}
+}
+
+func TestRepeaterErrValidField(t *testing.T) {
+	p := &testContact{Links: []string{"a", "b"}}
+
+	view, err := InputRepeaterErr("Links", p, map[string]string{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(string(view), `name="links.1"`) {
+		t.Errorf("Expected the repeated inputs to be rendered, got: %s", view)
+	}
+}
+
+func TestRepeaterErrVariants(t *testing.T) {
+	p := &testContact{Links: []string{"a", "b"}}
+
+	variants := map[string]func(fieldName string) ([]byte, error){
+		"InputRepeaterErr": func(f string) ([]byte, error) {
+			return InputRepeaterErr(f, p, map[string]string{})
+		},
+		"TextareaRepeaterErr": func(f string) ([]byte, error) {
+			return TextareaRepeaterErr(f, p, map[string]string{})
+		},
+		"NumberRepeaterErr": func(f string) ([]byte, error) {
+			return NumberRepeaterErr(f, p, map[string]string{})
+		},
+		"SelectRepeaterErr": func(f string) ([]byte, error) {
+			return SelectRepeaterErr(f, p, map[string]string{}, map[string]string{"a": "A"})
+		},
+		"SelectRepeaterOrderedErr": func(f string) ([]byte, error) {
+			return SelectRepeaterOrderedErr(f, p, map[string]string{}, []Option{{Value: "a", Label: "A"}})
+		},
+		"FileRepeaterErr": func(f string) ([]byte, error) {
+			return FileRepeaterErr(f, p, map[string]string{})
+		},
+		"ColorRepeaterErr": func(f string) ([]byte, error) {
+			return ColorRepeaterErr(f, p, map[string]string{})
+		},
+		"TimeRepeaterErr": func(f string) ([]byte, error) {
+			return TimeRepeaterErr(f, p, map[string]string{})
+		},
+		"AutocompleteRepeaterErr": func(f string) ([]byte, error) {
+			return AutocompleteRepeaterErr(f, p, map[string]string{}, []string{"a"})
+		},
+		"ReferenceRepeaterErr": func(f string) ([]byte, error) {
+			return ReferenceRepeaterErr(f, p, map[string]string{}, "Contact")
+		},
+	}
+
+	for name, render := range variants {
+		view, err := render("Naem")
+		if _, ok := err.(*FieldError); !ok || view != nil {
+			t.Errorf("%s: expected a *FieldError and no view for an unknown field, got %v and %s", name, err, view)
+		}
+
+		view, err = render("Links")
+		if err != nil || !strings.Contains(string(view), `links.1`) {
+			t.Errorf("%s: expected the repeater to be rendered, got %v and %s", name, err, view)
+		}
+	}
+}
+
+func TestInvalidContentDoesNotPanic(t *testing.T) {
+	var nilContact *testContact
+
+	for _, p := range []interface{}{nil, 42, nilContact, testContact{}} {
+		attrs := func() map[string]string { return map[string]string{"label": "Links"} }
+
+		views := map[string][]byte{
+			"InputRepeater":          InputRepeater("Links", p, attrs()),
+			"TextareaRepeater":       TextareaRepeater("Links", p, attrs()),
+			"NumberRepeater":         NumberRepeater("Links", p, attrs()),
+			"SelectRepeater":         SelectRepeater("Links", p, attrs(), map[string]string{"a": "A"}),
+			"FileRepeater":           FileRepeater("Links", p, attrs()),
+			"ColorRepeater":          ColorRepeater("Links", p, attrs()),
+			"AutocompleteRepeater":   AutocompleteRepeater("Links", p, attrs(), nil),
+			"ReferenceRepeater":      ReferenceRepeater("Links", p, attrs(), "Contact"),
+			"WeightedSelectRepeater": WeightedSelectRepeater("Links", p, attrs(), map[string]string{"a": "A"}),
+			"BlockRepeater":          BlockRepeater("Links", p, nil, attrs()),
+			"KeyValue":               KeyValue("Links", p, attrs()),
+			"LinkList":               LinkList("Links", p, attrs()),
+			"EnumPills":              EnumPills("Links", p, nil, attrs()),
+		}
+
+		for name, view := range views {
+			if !strings.Contains(string(view), `class="__ponzu-field-error red-text"`) ||
+				!strings.Contains(string(view), "content ") {
+				t.Errorf("%s(%#v): expected an inline error, got: %s", name, p, view)
+			}
+		}
+
+		// scalar fields render without a value rather than panicking
+		for name, view := range map[string][]byte{
+			"Input":    Input("Name", p, map[string]string{"type": "text"}),
+			"Textarea": Textarea("Bio", p, map[string]string{}),
+			"Select":   Select("Name", p, map[string]string{}, map[string]string{"a": "A"}),
+		} {
+			if len(view) == 0 {
+				t.Errorf("%s(%#v): expected the field to be rendered, got nothing", name, p)
+			}
+		}
+
+		if _, err := InputRepeaterErr("Links", p, attrs()); err == nil {
+			t.Errorf("Expected an error for content %#v", p)
+		}
+	}
+}
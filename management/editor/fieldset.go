@@ -0,0 +1,63 @@
+package editor
+
+import (
+	"html"
+	"io"
+)
+
+// Fieldset returns a Field grouping fields in a <fieldset> HTML element with
+// legend as its caption, and a toggle to collapse and expand it, which starts
+// expanded. The grouping is only presentational, and the fields are submitted
+// under the same names as they would be outside of it, so repeaters and other
+// fieldsets may be nested within it. The fields are wrapped by their own Wrap
+// funcs, but not by the FormOptions' Wrap, which applies to the Fieldset. When
+// a field fails to render, the Fieldset's Render returns its error instead.
+func Fieldset(legend string, fields ...Field) Field {
+	view := []byte(`<fieldset class="__ponzu-fieldset col s12">` +
+		`<legend><a href="#" class="__ponzu-fieldset-toggle" aria-expanded="true">` +
+		`<i class="material-icons">expand_less</i>` + html.EscapeString(legend) + `</a></legend>` +
+		`<div class="__ponzu-fieldset-fields row">`)
+
+	for _, f := range fields {
+		wrapped, err := wrapField(f, nil)
+		if err != nil {
+			return Field{
+				Render: func(w io.Writer) error { return err },
+				Meta:   FieldMeta{Label: legend},
+			}
+		}
+
+		view = append(view, wrapped.View...)
+	}
+
+	view = append(view, `</div></fieldset>`+fieldsetScript...)
+
+	return Field{
+		View: view,
+		Meta: FieldMeta{Label: legend},
+	}
+}
+
+// fieldsetScript collapses and expands every Fieldset of the page
+const fieldsetScript = `
+<script>
+	$(function() {
+		if (window.__ponzuFieldset) {
+			return;
+		}
+		window.__ponzuFieldset = true;
+
+		$(document).on('click', '.__ponzu-fieldset-toggle', function(e) {
+			e.preventDefault();
+
+			var toggle = $(this),
+				expanded = toggle.attr('aria-expanded') !== 'true';
+
+			toggle.attr('aria-expanded', String(expanded));
+			toggle.find('.material-icons').first().text(expanded ? 'expand_less' : 'expand_more');
+			toggle.closest('.__ponzu-fieldset').toggleClass('__ponzu-fieldset-collapsed', !expanded)
+				.children('.__ponzu-fieldset-fields').toggle(expanded);
+		});
+	});
+</script>
+`
@@ -0,0 +1,28 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldset(t *testing.T) {
+	p := &testContact{Name: "Jane", Links: []string{"a"}}
+
+	wrapped := func(view []byte, meta FieldMeta) []byte {
+		return append([]byte(`<div class="wrapped">`), append(view, `</div>`...)...)
+	}
+
+	f := Fieldset("SEO & Links",
+		Field{View: Input("Name", p, map[string]string{"type": "text"})},
+		Fieldset("Nested", Field{View: InputRepeater("Links", p, map[string]string{}), Wrap: wrapped}),
+	)
+	view := string(f.View)
+
+	if strings.Count(view, "<fieldset") != 2 || !strings.Contains(view, "SEO &amp; Links</a></legend>") {
+		t.Errorf("Expected nested fieldsets with escaped legends, got: %s", view)
+	}
+
+	if !strings.Contains(view, `name="name"`) || !strings.Contains(view, `<div class="wrapped"><span class="__ponzu-repeat links"`) {
+		t.Errorf("Expected the fields unchanged but for their own Wrap, got: %s", view)
+	}
+}
@@ -0,0 +1,289 @@
+package editor
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// update rewrites the golden files with the current output, for a change of
+// the markup which is intended:
+//
+//	go test -run TestGolden -update
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+type testGolden struct {
+	ID     int      `json:"id"`
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Color  string   `json:"color"`
+	Price  float64  `json:"price"`
+	Photo  string   `json:"photo"`
+	Config string   `json:"config"`
+	Tags   []string `json:"tags"`
+	Photos []string `json:"photos"`
+	Opens  string   `json:"opens"`
+	Slots  []string `json:"slots"`
+	Place  string   `json:"place"`
+	Public bool     `json:"public"`
+}
+
+func (g *testGolden) ItemID() int { return g.ID }
+
+// goldenContents are the representative contents each helper is rendered
+// with: new and empty, stored with a single value in each field, and stored
+// with several values, some of which need escaping
+var goldenContents = []struct {
+	name string
+	p    *testGolden
+}{
+	{"empty", &testGolden{}},
+	{"single", &testGolden{
+		ID:     1,
+		Title:  "Hello",
+		Body:   "Some text",
+		Color:  "#ff0000",
+		Price:  12.5,
+		Photo:  "/api/uploads/a.jpg",
+		Config: `{"a": 1}`,
+		Tags:   []string{"go"},
+		Photos: []string{"/api/uploads/a.jpg"},
+		Opens:  "09:30",
+		Slots:  []string{"09:00"},
+		Place:  "51.5074,-0.1278",
+		Public: true,
+	}},
+	{"multi", &testGolden{
+		ID:     2,
+		Title:  `Say "hi" & <b>bye</b>`,
+		Body:   "First line\nSecond </textarea> line",
+		Color:  "#00ff00",
+		Price:  1234.56,
+		Photo:  "/api/uploads/b.png",
+		Config: "{\n  \"a\": \"<b>\"\n}",
+		Tags:   []string{"go", `"quoted"`, "<tag>"},
+		Photos: []string{"/api/uploads/a.jpg", "/api/uploads/b.pdf"},
+		Opens:  "17:45",
+		Slots:  []string{"09:00", "13:30"},
+		Place:  "-33.8688, 151.2093",
+	}},
+}
+
+// goldenHelpers render each helper of the editor for the content p, with the
+// attrs of the helper
+var goldenHelpers = []struct {
+	name   string
+	attrs  map[string]string
+	render func(p *testGolden, attrs map[string]string) []byte
+}{
+	{"Input", map[string]string{"label": "Title", "type": "text", "placeholder": "Enter a title"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Input("Title", p, attrs)
+	}},
+	{"Hidden", nil, func(p *testGolden, attrs map[string]string) []byte {
+		return Hidden("Title", p)
+	}},
+	{"Textarea", map[string]string{"label": "Body"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Textarea("Body", p, attrs)
+	}},
+	{"Select", map[string]string{"label": "Title"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Select("Title", p, attrs, map[string]string{"Hello": "Hello", "Bye": "Bye"})
+	}},
+	{"Checkbox", map[string]string{"label": "Tags"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Checkbox("Tags", p, attrs, map[string]string{"go": "Go", "js": "JavaScript"})
+	}},
+	{"File", map[string]string{"label": "Photo"}, func(p *testGolden, attrs map[string]string) []byte {
+		return File("Photo", p, attrs)
+	}},
+	{"Tags", map[string]string{"label": "Tags"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Tags("Tags", p, attrs)
+	}},
+	{"Color", map[string]string{"label": "Color"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Color("Color", p, attrs)
+	}},
+	{"Money", map[string]string{"label": "Price", "currency": "EUR"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Money("Price", p, attrs)
+	}},
+	{"Code", map[string]string{"label": "Config"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Code("Config", p, attrs)
+	}},
+	{"Markdown", map[string]string{"label": "Body"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Markdown("Body", p, attrs)
+	}},
+	{"InputRepeater", map[string]string{"label": "Tags", "type": "text"}, func(p *testGolden, attrs map[string]string) []byte {
+		return InputRepeater("Tags", p, attrs)
+	}},
+	{"TextareaRepeater", map[string]string{"label": "Tags"}, func(p *testGolden, attrs map[string]string) []byte {
+		return TextareaRepeater("Tags", p, attrs)
+	}},
+	{"NumberRepeater", map[string]string{"label": "Tags", "min": "0"}, func(p *testGolden, attrs map[string]string) []byte {
+		return NumberRepeater("Tags", p, attrs)
+	}},
+	{"SelectRepeater", map[string]string{"label": "Tags"}, func(p *testGolden, attrs map[string]string) []byte {
+		return SelectRepeater("Tags", p, attrs, map[string]string{"go": "Go", "<tag>": "Tag"})
+	}},
+	{"FileRepeater", map[string]string{"label": "Photos"}, func(p *testGolden, attrs map[string]string) []byte {
+		return FileRepeater("Photos", p, attrs)
+	}},
+	{"ColorRepeater", map[string]string{"label": "Tags"}, func(p *testGolden, attrs map[string]string) []byte {
+		return ColorRepeater("Tags", p, attrs)
+	}},
+	{"Time", map[string]string{"label": "Opens", "step": "15"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Time("Opens", p, attrs)
+	}},
+	{"TimeRepeater", map[string]string{"label": "Slots", "clock": "12h", "step": "30"}, func(p *testGolden, attrs map[string]string) []byte {
+		return TimeRepeater("Slots", p, attrs)
+	}},
+	{"LatLng", map[string]string{"label": "Place", "tiles": "https://tiles.example.com/{z}/{x}/{y}.png", "attribution": "© Example"}, func(p *testGolden, attrs map[string]string) []byte {
+		return LatLng("Place", p, attrs)
+	}},
+	{"Toggle", map[string]string{"label": "Public"}, func(p *testGolden, attrs map[string]string) []byte {
+		return Toggle("Public", p, attrs)
+	}},
+	{"LinkList", map[string]string{"label": "Links"}, func(p *testGolden, attrs map[string]string) []byte {
+		return LinkList("Tags", p, attrs)
+	}},
+}
+
+// rxTag matches the opening tags of markup, with their attributes
+var rxTag = regexp.MustCompile(`<([a-zA-Z][\w-]*)((?:\s+[^\s=<>"/]+(?:="[^"]*")?)*)\s*(/?)>`)
+
+// rxTagAttr matches each attribute of a tag matched by rxTag
+var rxTagAttr = regexp.MustCompile(`[^\s=<>"/]+(?:="[^"]*")?`)
+
+// rxSpace matches runs of whitespace, along with those around a tag
+var rxSpace = regexp.MustCompile(`\s*([<>])\s*|\s+`)
+
+// normalizeMarkup returns markup with its whitespace collapsed, the attributes
+// of each tag sorted, and each tag starting a line, so that neither a change of
+// formatting nor the order of attributes written by hand fails a comparison
+func normalizeMarkup(markup []byte) string {
+	s := rxSpace.ReplaceAllStringFunc(string(markup), func(m string) string {
+		if t := strings.TrimSpace(m); t != "" {
+			return t
+		}
+
+		return " "
+	})
+
+	s = rxTag.ReplaceAllStringFunc(s, func(tag string) string {
+		m := rxTag.FindStringSubmatch(tag)
+		attrs := rxTagAttr.FindAllString(m[2], -1)
+		sort.Strings(attrs)
+
+		if len(attrs) == 0 {
+			return "<" + m[1] + m[3] + ">"
+		}
+
+		return "<" + m[1] + " " + strings.Join(attrs, " ") + m[3] + ">"
+	})
+
+	return strings.TrimSpace(strings.Replace(s, "><", ">\n<", -1)) + "\n"
+}
+
+// TestGolden compares the markup of each helper, rendered for each of the
+// goldenContents, with its golden file in testdata/golden
+func TestGolden(t *testing.T) {
+	for _, h := range goldenHelpers {
+		for _, c := range goldenContents {
+			name := h.name + "-" + c.name
+			path := filepath.Join("testdata", "golden", name+".html")
+			got := normalizeMarkup(h.render(c.p, copyAttrs(h.attrs)))
+
+			if *update {
+				err := os.MkdirAll(filepath.Dir(path), 0755)
+				if err == nil {
+					err = ioutil.WriteFile(path, []byte(got), 0644)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+
+			want, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Errorf("%s: %v, run go test -update to create it", name, err)
+				continue
+			}
+
+			if got != string(want) {
+				t.Errorf("%s: markup differs from %s at line %d, run go test -update if the change is intended\n got: %s\nwant: %s",
+					name, path, diffLine(got, string(want)), got, want)
+			}
+		}
+	}
+}
+
+func TestNormalizeMarkup(t *testing.T) {
+	a := normalizeMarkup([]byte("<div class=\"a\"   id=\"b\">\n\t<input value=\"x  y\" name=\"n\" disabled />  text\n</div>"))
+	b := normalizeMarkup([]byte(`<div id="b" class="a"><input disabled name="n" value="x  y"/> text</div>`))
+
+	if a != b {
+		t.Errorf("Expected whitespace and attribute order to be ignored, got:\n%s\nand:\n%s", a, b)
+	}
+
+	want := "<div class=\"a\" id=\"b\">\n<input disabled name=\"n\" value=\"x y\"/>text</div>\n"
+	if a != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, a)
+	}
+}
+
+// diffLine returns the number of the first line which differs between a and b
+func diffLine(a, b string) int {
+	al, bl := strings.Split(a, "\n"), strings.Split(b, "\n")
+	for i := 0; i < len(al) && i < len(bl); i++ {
+		if al[i] != bl[i] {
+			return i + 1
+		}
+	}
+
+	if len(al) < len(bl) {
+		return len(al) + 1
+	}
+
+	return len(bl) + 1
+}
+
+// copyAttrs returns a copy of attrs, so that a render can't see the changes of
+// another to them
+func copyAttrs(attrs map[string]string) map[string]string {
+	if attrs == nil {
+		return nil
+	}
+
+	c := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		c[k] = v
+	}
+
+	return c
+}
+
+// TestRenderIsDeterministic renders each of the goldenHelpers several times
+// with the same attrs, since the order of attributes and options mustn't
+// depend on the iteration of a map, nor may a helper modify its attrs
+func TestRenderIsDeterministic(t *testing.T) {
+	for _, h := range goldenHelpers {
+		attrs := copyAttrs(h.attrs)
+		for _, c := range goldenContents {
+			first := h.render(c.p, attrs)
+			for i := 0; i < 5; i++ {
+				if again := h.render(c.p, attrs); !bytes.Equal(first, again) {
+					t.Errorf("%s-%s: expected the same markup on every render, got:\n%s\nand:\n%s", h.name, c.name, first, again)
+					break
+				}
+			}
+		}
+
+		if !reflect.DeepEqual(attrs, h.attrs) {
+			t.Errorf("%s: expected attrs not to be changed, got: %v", h.name, attrs)
+		}
+	}
+}
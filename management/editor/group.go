@@ -0,0 +1,268 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RepeaterGroup returns the []byte of a repeatable group of fields, rendered as
+// a bordered block per element of a slice of structs, such as a list of links
+// where each item has a label and a URL. The fields of each block are returned
+// by render, and the form name of every field must begin with the item's name
+// and a dot, e.g. TagNameFromStructFieldMulti(fieldName, index, p)+".label",
+// which submits "links.0.label". Blocks can be added and removed, and the names
+// of their fields are renumbered so that the indexes stay contiguous. The
+// submitted items can be read back with ParseRepeaterGroup.
+// render is also called with an index equal to the length of the slice to
+// render the fields of a new block, and must then return empty fields.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func RepeaterGroup(fieldName string, p interface{}, render func(index int) []Field, attrs map[string]string) []byte {
+	checkAttrs("RepeaterGroup", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	scope := TagNameFromStructField(fieldName, p)
+	field, err := fieldByPath(fieldName, p)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var n int
+	if field.Kind() == reflect.Slice {
+		n = field.Len()
+	}
+
+	view := &bytes.Buffer{}
+	_, err = view.WriteString(`<div class="__ponzu-group ` + scope + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to RepeaterGroup buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to RepeaterGroup buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<div class="__ponzu-group-items">`)
+	if err != nil {
+		log.Println("Error writing HTML string to RepeaterGroup buffer")
+		return nil
+	}
+
+	for i := 0; i < n; i++ {
+		_, err = view.WriteString(groupItem(render(i)))
+		if err != nil {
+			log.Println("Error writing HTML string to RepeaterGroup buffer")
+			return nil
+		}
+	}
+
+	// the template of a new block is cloned when a block is added, and its
+	// fields are renumbered by GroupController
+	_, err = view.WriteString(`</div>` +
+		`<template class="__ponzu-group-template">` + groupItem(render(n)) + `</template>` +
+		`<button class="__ponzu-group-add btn waves-effect waves-light">` + htmlText("group.add") + `</button>` +
+		`</div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to RepeaterGroup buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), GroupController(fieldName, p)...)
+}
+
+// groupItem returns the markup of a single block of a RepeaterGroup holding the
+// views of fields
+func groupItem(fields []Field) string {
+	item := `<div class="__ponzu-group-item card-panel row">` +
+		`<button class="__ponzu-group-del right btn-flat waves-effect waves-red">-</button>`
+
+	for _, f := range fields {
+		wrapped, err := wrapField(f, nil)
+		if err != nil {
+			log.Println("Error rendering field to RepeaterGroup item")
+			continue
+		}
+
+		item += string(wrapped.View)
+	}
+
+	return item + `</div>`
+}
+
+// GroupController generates the javascript to add, remove and renumber the
+// blocks of a RepeaterGroup so that the indexes in the names of each block's
+// fields stay contiguous
+func GroupController(fieldName string, p interface{}) []byte {
+	scope := TagNameFromStructField(fieldName, p)
+	script := `
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-group.` + selectorClass(scope) + `'),
+				items = scope.find('.__ponzu-group-items'),
+				pattern = new RegExp('^' + ` + jsString(regexp.QuoteMeta(scope)) + ` + '\\.\\d+\\.');
+
+			var resetFieldNames = function() {
+				items.children('.__ponzu-group-item').each(function(i, item) {
+					var prefix = ` + jsString(scope) + ` + '.' + String(i) + '.';
+
+					$(item).find('[name]').each(function(j, el) {
+						var $el = $(el);
+						$el.attr('name', $el.attr('name').replace(pattern, prefix));
+					});
+				});
+			}
+
+			scope.on('click', '.__ponzu-group-del', function(e) {
+				e.preventDefault();
+
+				$(e.target).closest('.__ponzu-group-item').remove();
+				resetFieldNames();
+			});
+
+			scope.find('.__ponzu-group-add').on('click', function(e) {
+				e.preventDefault();
+
+				items.append($(scope.find('.__ponzu-group-template').html()));
+				resetFieldNames();
+			});
+		});
+	</script>`
+
+	return []byte(script)
+}
+
+// ParseRepeaterGroup reconstructs the items submitted by a RepeaterGroup from
+// the form values into dst, which must be a pointer to a slice of structs. The
+// values of each item are set on the struct fields matching their keys, by
+// `json` tag or by field name, and must be strings, bools or numbers. The items
+// are in the order they appeared in the editor.
+func ParseRepeaterGroup(form url.Values, fieldName string, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("editor: ParseRepeaterGroup needs a pointer to a slice, got %T", dst)
+	}
+
+	slice := v.Elem()
+	elem := slice.Type().Elem()
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("editor: ParseRepeaterGroup needs a slice of structs, got %T", dst)
+	}
+
+	items := ParseBlocks(form, fieldName)
+	out := reflect.MakeSlice(slice.Type(), 0, len(items))
+	for i, values := range items {
+		item := reflect.New(elem).Elem()
+		for key, value := range values {
+			f, ok := structFieldByKey(item, key)
+			if !ok {
+				continue
+			}
+
+			err := setFieldString(f, value)
+			if err != nil {
+				return fmt.Errorf("editor: %s.%d.%s: %s", fieldName, i, key, err.Error())
+			}
+		}
+
+		out = reflect.Append(out, item)
+	}
+
+	slice.Set(out)
+
+	return nil
+}
+
+// structFieldByKey returns the exported field of the struct v keyed by key, as
+// in blockValues: its `json` tag, or its name when it has none
+func structFieldByKey(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			continue
+		}
+
+		name := t.Field(i).Name
+		if tag, ok := t.Field(i).Tag.Lookup("json"); ok {
+			if tag = strings.Split(tag, ",")[0]; tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+		}
+
+		if name == key {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// setFieldString sets f to the value s, parsed according to the kind of f
+func setFieldString(f reflect.Value, s string) error {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+
+	case reflect.Bool:
+		b := s == "on"
+		if !b && s != "" {
+			var err error
+			b, err = strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+		}
+		f.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(s, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(s, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+
+	default:
+		return fmt.Errorf("unsupported field kind %s", f.Kind())
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package editor
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type testMenuItem struct {
+	Label  string  `json:"label"`
+	URL    string  `json:"url"`
+	Weight int     `json:"weight"`
+	Hidden bool    `json:"hidden"`
+	Price  float64 // no json tag, keyed by field name
+}
+
+type testMenu struct {
+	Items []testMenuItem `json:"items"`
+}
+
+func TestRepeaterGroup(t *testing.T) {
+	p := &testMenu{Items: []testMenuItem{{Label: "Home"}, {Label: "About"}}}
+
+	render := func(i int) []Field {
+		var item testMenuItem
+		if i < len(p.Items) {
+			item = p.Items[i]
+		}
+
+		name := TagNameFromStructFieldMulti("Items", i, p)
+		return []Field{{View: []byte(`<input name="` + name + `.label" value="` + item.Label + `" />`)}}
+	}
+
+	view := string(RepeaterGroup("Items", p, render, map[string]string{"label": "Menu"}))
+
+	for _, name := range []string{"items.0.label", "items.1.label", "items.2.label"} {
+		if !strings.Contains(view, `name="`+name+`"`) {
+			t.Errorf("Expected a field named %s, got: %s", name, view)
+		}
+	}
+
+	if strings.Count(view, `class="__ponzu-group-item `) != 3 {
+		t.Errorf("Expected two blocks plus the template, got: %s", view)
+	}
+}
+
+func TestParseRepeaterGroup(t *testing.T) {
+	form := url.Values{
+		"items.1.label":  {"About"},
+		"items.1.url":    {"/about"},
+		"items.1.weight": {"2"},
+		"items.0.label":  {"Home"},
+		"items.0.url":    {"/"},
+		"items.0.hidden": {"on"},
+		"items.0.Price":  {"1.5"},
+		"items.0.other":  {"ignored"},
+		"title":          {"ignored"},
+	}
+
+	var items []testMenuItem
+	err := ParseRepeaterGroup(form, "items", &items)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []testMenuItem{
+		{Label: "Home", URL: "/", Hidden: true, Price: 1.5},
+		{Label: "About", URL: "/about", Weight: 2},
+	}
+
+	if len(items) != len(expected) {
+		t.Fatalf("Expected %d items, got: %+v", len(expected), items)
+	}
+
+	for i := range expected {
+		if items[i] != expected[i] {
+			t.Errorf("Expected item %d to be %+v, got: %+v", i, expected[i], items[i])
+		}
+	}
+
+	form.Set("items.1.weight", "two")
+	if err = ParseRepeaterGroup(form, "items", &items); err == nil {
+		t.Error("Expected an error for a non-numeric weight")
+	}
+
+	if err = ParseRepeaterGroup(form, "items", items); err == nil {
+		t.Error("Expected an error for a non-pointer destination")
+	}
+}
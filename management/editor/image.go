@@ -0,0 +1,141 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+
+	// register decoders for the image formats accepted by ValidateImageDimensions
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// ImageConstraints are the dimensions, in pixels, an uploaded image must meet.
+// A zero value leaves that dimension unconstrained.
+type ImageConstraints struct {
+	MinWidth  int `json:"minwidth,omitempty"`
+	MinHeight int `json:"minheight,omitempty"`
+	Width     int `json:"width,omitempty"`
+	Height    int `json:"height,omitempty"`
+}
+
+// ImageConstraintsFromAttrs reads the image constraints set in the attrs of a
+// File field: "minwidth", "minheight", and "exactwidth" / "exactheight" for
+// images which must have an exact size, e.g. a 1200x630 social image.
+func ImageConstraintsFromAttrs(attrs map[string]string) ImageConstraints {
+	atoi := func(key string) int {
+		n, _ := strconv.Atoi(attrs[key])
+		return n
+	}
+
+	return ImageConstraints{
+		MinWidth:  atoi("minwidth"),
+		MinHeight: atoi("minheight"),
+		Width:     atoi("exactwidth"),
+		Height:    atoi("exactheight"),
+	}
+}
+
+// IsZero reports whether c doesn't constrain the image at all
+func (c ImageConstraints) IsZero() bool {
+	return c == ImageConstraints{}
+}
+
+// Check returns an error describing each constraint an image of the given
+// size fails to meet, or nil if it meets all of them
+func (c ImageConstraints) Check(width, height int) error {
+	var problems []string
+	if c.Width > 0 && width != c.Width {
+		problems = append(problems, fmt.Sprintf("width must be exactly %dpx", c.Width))
+	}
+
+	if c.Height > 0 && height != c.Height {
+		problems = append(problems, fmt.Sprintf("height must be exactly %dpx", c.Height))
+	}
+
+	if c.MinWidth > 0 && width < c.MinWidth {
+		problems = append(problems, fmt.Sprintf("width must be at least %dpx", c.MinWidth))
+	}
+
+	if c.MinHeight > 0 && height < c.MinHeight {
+		problems = append(problems, fmt.Sprintf("height must be at least %dpx", c.MinHeight))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("image is %dx%dpx, but its %s", width, height, strings.Join(problems, " and "))
+	}
+
+	return nil
+}
+
+// ValidateImageDimensions reads the dimensions of the image in r (GIF, JPEG or
+// PNG) and checks them against c. It is the server-side counterpart to the
+// dimension checks File performs in the browser, which can be bypassed.
+func ValidateImageDimensions(r io.Reader, c ImageConstraints) error {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return fmt.Errorf("couldn't read image dimensions: %s", err.Error())
+	}
+
+	return c.Check(cfg.Width, cfg.Height)
+}
+
+// imageDimensionsScript returns the javascript function checkDimensions(input),
+// which rejects an image selected in the file input if it doesn't meet c by
+// calling reject(message)
+func imageDimensionsScript(c ImageConstraints) string {
+	dims, err := json.Marshal(c)
+	if err != nil {
+		dims = []byte("{}")
+	}
+
+	return `
+				function checkDimensions(input, reject) {
+					var dims = ` + string(dims) + `,
+						file = input.files && input.files[0],
+						messages = {
+							width: ` + jsString(text("image.width")) + `,
+							height: ` + jsString(text("image.height")) + `,
+							minWidth: ` + jsString(text("image.minWidth")) + `,
+							minHeight: ` + jsString(text("image.minHeight")) + `,
+							and: ` + jsString(text("image.and")) + `,
+							rejected: ` + jsString(text("image.rejected")) + `
+						};
+
+					if (!file || !/^image\//.test(file.type) || !window.URL) {
+						return;
+					}
+
+					var img = new Image(),
+						src = URL.createObjectURL(file);
+
+					img.onload = function() {
+						URL.revokeObjectURL(src);
+
+						var w = img.naturalWidth, h = img.naturalHeight, problems = [];
+						if (dims.width && w !== dims.width) {
+							problems.push(messages.width.replace('{n}', dims.width));
+						}
+						if (dims.height && h !== dims.height) {
+							problems.push(messages.height.replace('{n}', dims.height));
+						}
+						if (dims.minwidth && w < dims.minwidth) {
+							problems.push(messages.minWidth.replace('{n}', dims.minwidth));
+						}
+						if (dims.minheight && h < dims.minheight) {
+							problems.push(messages.minHeight.replace('{n}', dims.minheight));
+						}
+
+						if (problems.length > 0) {
+							reject(messages.rejected.replace('{size}', w + 'x' + h).replace('{problems}', problems.join(messages.and)));
+						}
+					}
+
+					img.src = src;
+				}
+`
+}
@@ -0,0 +1,55 @@
+package editor
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testPNG returns a PNG image of the given size
+func testPNG(t *testing.T, width, height int) []byte {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestValidateImageDimensions(t *testing.T) {
+	social := ImageConstraints{Width: 1200, Height: 630}
+	minimum := ImageConstraints{MinWidth: 400, MinHeight: 300}
+
+	cases := []struct {
+		name  string
+		image []byte
+		c     ImageConstraints
+		err   string
+	}{
+		{"too small", testPNG(t, 399, 300), minimum, "image is 399x300px, but its width must be at least 400px"},
+		{"too short", testPNG(t, 400, 100), minimum, "height must be at least 300px"},
+		{"exact minimum", testPNG(t, 400, 300), minimum, ""},
+		{"above minimum", testPNG(t, 800, 600), minimum, ""},
+		{"exact size", testPNG(t, 1200, 630), social, ""},
+		{"too large", testPNG(t, 1201, 631), social, "width must be exactly 1200px and height must be exactly 630px"},
+		{"unconstrained", testPNG(t, 1, 1), ImageConstraints{}, ""},
+		{"undecodable", []byte("not an image"), minimum, "couldn't read image dimensions"},
+		{"truncated", testPNG(t, 400, 300)[:10], minimum, "couldn't read image dimensions"},
+	}
+
+	for _, c := range cases {
+		err := ValidateImageDimensions(bytes.NewReader(c.image), c.c)
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got: %s", c.name, err)
+			}
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: expected an error containing %q, got: %v", c.name, c.err, err)
+		}
+	}
+}
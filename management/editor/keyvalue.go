@@ -0,0 +1,126 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DuplicateKeys sets how ParseKeyValues handles a key submitted more than once
+type DuplicateKeys int
+
+const (
+	// DuplicateKeysError rejects the submitted values with an error
+	DuplicateKeysError DuplicateKeys = iota
+
+	// DuplicateKeysLastWins keeps the value of the last item with the key
+	DuplicateKeysLastWins
+)
+
+// KeyValue returns the []byte of a repeatable pair of <input> HTML elements,
+// one for the key and one for the value of each entry of a map, such as
+// arbitrary metadata. The entries are shown in order of their keys. Each item
+// submits its pair as "name.0.key" and "name.0.value", which can be read back
+// with ParseKeyValues.
+// The field must be a map, whose keys and values are shown as text.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func KeyValue(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("KeyValue", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	scope := TagNameFromStructField(fieldName, p)
+	field, err := fieldByPath(fieldName, p)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	entries := blockValues(field)
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		keys = append(keys, "")
+	}
+
+	keyText, valueText := htmlText("keyvalue.key"), htmlText("keyvalue.value")
+
+	view := &bytes.Buffer{}
+	_, err = view.WriteString(repeatOpen("__ponzu-key-value "+scope, attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to KeyValue buffer")
+		return nil
+	}
+
+	for i, k := range keys {
+		name := scope + "." + strconv.Itoa(i)
+
+		var label string
+		if i == 0 && attrs["label"] != "" {
+			label = `<label class="active" for="` + fieldID(name+".key") + `">` + attrs["label"] + `</label>`
+		}
+
+		item := `<div class="__ponzu-key-value-item row">` + label +
+			`<div class="input-field col s5"><input type="text" id="` + fieldID(name+".key") +
+			`" data-ponzu-key="key" data-ponzu-trim="true" name="` + name + `.key" value="` + html.EscapeString(k) +
+			`" placeholder="` + keyText + `" aria-label="` + keyText + `" /></div>` +
+			`<div class="input-field col s7"><input type="text" id="` + fieldID(name+".value") +
+			`" data-ponzu-key="value" name="` + name + `.value" value="` + html.EscapeString(entries[k]) +
+			`" placeholder="` + valueText + `" aria-label="` + valueText + `" /></div>` +
+			`</div>`
+
+		_, err = view.WriteString(item)
+		if err != nil {
+			log.Println("Error writing HTML string to KeyValue buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(repeatClose(attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to KeyValue buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), RepeatControllerWithOptions(fieldName, p, "input[data-ponzu-key]", "div.__ponzu-key-value-item", repeatControllerOptions(attrs))...)
+}
+
+// ParseKeyValues reconstructs the map submitted by a KeyValue from the form
+// values. Keys are trimmed of whitespace, and items with neither a key nor a
+// value are left out, while a value without a key is an error. A key submitted
+// more than once is handled as set by duplicates.
+func ParseKeyValues(form url.Values, fieldName string, duplicates DuplicateKeys) (map[string]string, error) {
+	entries := make(map[string]string)
+	for _, values := range ParseBlocks(form, fieldName) {
+		k := strings.TrimSpace(values["key"])
+		v := values["value"]
+
+		if k == "" {
+			if strings.TrimSpace(v) == "" {
+				continue
+			}
+
+			return nil, fmt.Errorf("%s: the value %q has no key", fieldName, v)
+		}
+
+		if _, ok := entries[k]; ok && duplicates != DuplicateKeysLastWins {
+			return nil, fmt.Errorf("%s: the key %q is repeated", fieldName, k)
+		}
+
+		entries[k] = v
+	}
+
+	return entries, nil
+}
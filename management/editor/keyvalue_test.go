@@ -0,0 +1,70 @@
+package editor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testMetadata struct {
+	Meta map[string]string `json:"meta"`
+}
+
+func TestKeyValue(t *testing.T) {
+	p := &testMetadata{Meta: map[string]string{"b": `"quoted"`, "a": "1"}}
+
+	view := string(KeyValue("Meta", p, map[string]string{"label": "Metadata"}))
+	if i := strings.Index(view, "<script>"); i != -1 {
+		view = view[:i]
+	}
+
+	if strings.Index(view, `name="meta.0.key" value="a"`) == -1 || strings.Index(view, `name="meta.1.key" value="b"`) == -1 {
+		t.Errorf("Expected the entries in order of their keys, got: %s", view)
+	}
+
+	if !strings.Contains(view, `name="meta.1.value" value="&#34;quoted&#34;"`) {
+		t.Errorf("Expected the escaped values, got: %s", view)
+	}
+
+	if strings.Count(view, "<label") != 1 || !strings.Contains(view, `for="field-meta-0-key"`) {
+		t.Errorf("Expected only the first key to be labeled, got: %s", view)
+	}
+
+	view = string(KeyValue("Meta", &testMetadata{}, map[string]string{}))
+	if strings.Count(view, `data-ponzu-key="key"`) != 1 {
+		t.Errorf("Expected an empty item for an empty map, got: %s", view)
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	form := url.Values{
+		"meta.2.key":   {"color"},
+		"meta.2.value": {"blue"},
+		"meta.0.key":   {" color "},
+		"meta.0.value": {"red"},
+		"meta.1.key":   {""},
+		"meta.1.value": {" "},
+		"meta.5.key":   {"size"},
+		"meta.5.value": {""},
+	}
+
+	_, err := ParseKeyValues(form, "meta", DuplicateKeysError)
+	if err == nil {
+		t.Error("Expected an error for the repeated key")
+	}
+
+	entries, err := ParseKeyValues(form, "meta", DuplicateKeysLastWins)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(entries, map[string]string{"color": "blue", "size": ""}) {
+		t.Errorf("Expected the last value of the key and no empty items, got: %v", entries)
+	}
+
+	_, err = ParseKeyValues(url.Values{"meta.0.value": {"orphan"}}, "meta", DuplicateKeysLastWins)
+	if err == nil {
+		t.Error("Expected an error for a value without a key")
+	}
+}
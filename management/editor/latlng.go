@@ -0,0 +1,271 @@
+package editor
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// DefaultLatLngZoom is the zoom level of the map of a LatLng without
+// attrs["zoom"], at which a city fills the map
+const DefaultLatLngZoom = 12
+
+// LatLng returns the []byte of a latitude and a longitude input, which store a
+// geographic point in a single field as "lat,lng", e.g. "51.5074,-0.1278". The
+// stored point is loaded back into the inputs, and each is validated as it is
+// typed: the latitude must be within -90 to 90, the longitude within -180 to
+// 180, and either both are set or neither is. Check the same constraints on the
+// server with ParseLatLng, since client-side checks can be bypassed.
+// A map is only shown when attrs["tiles"] is the URL template of a tile server,
+// e.g. "https://tile.openstreetmap.org/{z}/{x}/{y}.png", so that the field works
+// without any external tiles. Clicking the map picks the point under the
+// cursor. attrs["zoom"] is its initial zoom level, which defaults to
+// DefaultLatLngZoom, and attrs["attribution"] is the credit shown below it, as
+// required by most tile servers.
+// The field should be a string.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func LatLng(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("LatLng", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+	id := fieldID(name)
+
+	var lat, lng string
+	if value != "" {
+		if _, _, err := ParseLatLng(value); err != nil {
+			logf("editor: LatLng value is not a valid point", "value", value, "field", fieldName, "error", err.Error())
+			value = ""
+		} else {
+			parts := strings.SplitN(value, ",", 2)
+			lat, lng = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		}
+	}
+
+	view := `<div class="__ponzu-latlng ` + name + ` input-field col s12">`
+	if attrs["label"] != "" {
+		view += `<label class="active" for="` + id + `">` + attrs["label"] + `</label>`
+	}
+
+	view += `<div class="__ponzu-latlng-inputs">` +
+		`<input type="number" id="` + id + `" class="__ponzu-latlng-lat" data-ponzu-display="true" min="-90" max="90" step="any" ` +
+		`placeholder="` + htmlText("latlng.latitude") + `" aria-label="` + htmlText("latlng.latitude") + `" value="` + lat + `" />` +
+		`<input type="number" class="__ponzu-latlng-lng" data-ponzu-display="true" min="-180" max="180" step="any" ` +
+		`placeholder="` + htmlText("latlng.longitude") + `" aria-label="` + htmlText("latlng.longitude") + `" value="` + lng + `" />` +
+		`</div><span class="__ponzu-latlng-error red-text" hidden></span>`
+
+	if attrs["tiles"] != "" {
+		zoom := DefaultLatLngZoom
+		if attrs["zoom"] != "" {
+			z, err := strconv.Atoi(attrs["zoom"])
+			if err != nil || z < 0 || z > 19 {
+				logf("editor: invalid LatLng zoom ignored", "field", fieldName, "zoom", attrs["zoom"])
+			} else {
+				zoom = z
+			}
+		}
+
+		view += `<div class="__ponzu-latlng-map" data-tiles="` + html.EscapeString(attrs["tiles"]) + `" data-zoom="` + strconv.Itoa(zoom) + `">` +
+			`<div class="__ponzu-latlng-zoom">` +
+			`<button type="button" class="btn-flat" data-zoom="1" aria-label="` + htmlText("latlng.zoomIn") + `">+</button>` +
+			`<button type="button" class="btn-flat" data-zoom="-1" aria-label="` + htmlText("latlng.zoomOut") + `">-</button>` +
+			`</div></div>`
+
+		if attrs["attribution"] != "" {
+			view += `<span class="__ponzu-latlng-attribution">` + html.EscapeString(attrs["attribution"]) + `</span>`
+		}
+	}
+
+	view += `<input type="hidden" class="__ponzu-latlng-value" name="` + name + `" value="` + html.EscapeString(value) + `" />` +
+		helpText(attrs) + `</div>` + latLngScript()
+
+	return []byte(view)
+}
+
+// ParseLatLng returns the latitude and longitude of the point s stored by a
+// LatLng, e.g. "51.5074,-0.1278", or an error unless s is a latitude within -90
+// to 90 and a longitude within -180 to 180, separated by a comma
+func ParseLatLng(s string) (lat, lng float64, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%q is not a point, e.g. 51.5074,-0.1278", s)
+	}
+
+	lat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || !(lat >= -90 && lat <= 90) {
+		return 0, 0, fmt.Errorf("%q is not a latitude between -90 and 90", parts[0])
+	}
+
+	lng, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || !(lng >= -180 && lng <= 180) {
+		return 0, 0, fmt.Errorf("%q is not a longitude between -180 and 180", parts[1])
+	}
+
+	return lat, lng, nil
+}
+
+// latLngScript returns the script which validates the inputs of every LatLng
+// of the page as they are typed, storing their point once both are valid, and
+// draws the map of those with one from its tiles
+func latLngScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuLatLng) {
+			return;
+		}
+		window.__ponzuLatLng = true;
+
+		var messages = {
+			lat: ` + jsString(text("latlng.latRange")) + `,
+			lng: ` + jsString(text("latlng.lngRange")) + `,
+			both: ` + jsString(text("latlng.both")) + `
+		};
+
+		// update validates the inputs of field, storing their point when both
+		// are valid, or an empty value when both are empty
+		var update = function(field) {
+			var lat = field.find('.__ponzu-latlng-lat'),
+				lng = field.find('.__ponzu-latlng-lng'),
+				a = $.trim(lat.val()),
+				b = $.trim(lng.val()),
+				latProblem = '', lngProblem = '';
+
+			if (a !== '' && !(Number(a) >= -90 && Number(a) <= 90)) {
+				latProblem = messages.lat;
+			}
+			if (b !== '' && !(Number(b) >= -180 && Number(b) <= 180)) {
+				lngProblem = messages.lng;
+			}
+			if (!latProblem && !lngProblem && (a === '') !== (b === '')) {
+				latProblem = a === '' ? messages.both : '';
+				lngProblem = b === '' ? messages.both : '';
+			}
+
+			lat.get(0).setCustomValidity(latProblem);
+			lng.get(0).setCustomValidity(lngProblem);
+			lat.toggleClass('invalid', latProblem !== '');
+			lng.toggleClass('invalid', lngProblem !== '');
+
+			var problem = latProblem || lngProblem;
+			field.find('.__ponzu-latlng-error').text(problem).prop('hidden', problem === '');
+
+			if (!problem) {
+				field.find('.__ponzu-latlng-value').val(a === '' ? '' : a + ',' + b).trigger('change');
+			}
+
+			draw(field);
+		}
+
+		// project returns the position of the point in pixels, within the
+		// world drawn at zoom
+		var project = function(lat, lng, zoom) {
+			var size = 256 * Math.pow(2, zoom),
+				sin = Math.sin(Math.max(Math.min(lat, 85), -85) * Math.PI / 180);
+
+			return {
+				x: (lng + 180) / 360 * size,
+				y: (0.5 - Math.log((1 + sin) / (1 - sin)) / (4 * Math.PI)) * size
+			};
+		}
+
+		// unproject is the inverse of project
+		var unproject = function(x, y, zoom) {
+			var size = 256 * Math.pow(2, zoom),
+				n = Math.PI * (1 - 2 * y / size);
+
+			return {
+				lat: Math.atan((Math.exp(n) - Math.exp(-n)) / 2) * 180 / Math.PI,
+				lng: x / size * 360 - 180
+			};
+		}
+
+		// point returns the stored point of field, or null
+		var point = function(field) {
+			var parts = field.find('.__ponzu-latlng-value').val().split(',');
+			if (parts.length !== 2) {
+				return null;
+			}
+
+			return {lat: Number(parts[0]), lng: Number(parts[1])};
+		}
+
+		// draw renders the tiles of the map of field around its point, or the
+		// whole world without one, with a marker on the point
+		var draw = function(field) {
+			var map = field.find('.__ponzu-latlng-map');
+			if (!map.length) {
+				return;
+			}
+
+			var at = point(field),
+				zoom = at ? Number(map.attr('data-zoom')) : 1,
+				center = project(at ? at.lat : 0, at ? at.lng : 0, zoom),
+				w = map.width(), h = map.height(),
+				left = center.x - w / 2, top = center.y - h / 2,
+				n = Math.pow(2, zoom),
+				template = map.attr('data-tiles');
+
+			map.data('view', {left: left, top: top, zoom: zoom});
+			map.find('img, .__ponzu-latlng-marker').remove();
+
+			for (var tx = Math.floor(left / 256); tx * 256 < left + w; tx++) {
+				for (var ty = Math.floor(top / 256); ty * 256 < top + h; ty++) {
+					if (ty < 0 || ty >= n) {
+						continue;
+					}
+
+					var src = template.replace('{z}', zoom).replace('{x}', ((tx % n) + n) % n).replace('{y}', ty);
+					$('<img alt="" draggable="false" />').attr('src', src).css({
+						left: tx * 256 - left,
+						top: ty * 256 - top
+					}).prependTo(map);
+				}
+			}
+
+			if (at) {
+				$('<i class="material-icons __ponzu-latlng-marker">place</i>').css({left: w / 2, top: h / 2}).appendTo(map);
+			}
+		}
+
+		$(document).on('input change', '.__ponzu-latlng-lat, .__ponzu-latlng-lng', function() {
+			update($(this).closest('.__ponzu-latlng'));
+		});
+
+		$(document).on('click', '.__ponzu-latlng-map', function(e) {
+			var map = $(this),
+				view = map.data('view'),
+				field = map.closest('.__ponzu-latlng');
+
+			if (!view || $(e.target).closest('.__ponzu-latlng-zoom').length) {
+				return;
+			}
+
+			var offset = map.offset(),
+				at = unproject(view.left + e.pageX - offset.left, view.top + e.pageY - offset.top, view.zoom);
+
+			field.find('.__ponzu-latlng-lat').val(at.lat.toFixed(6));
+			field.find('.__ponzu-latlng-lng').val((((at.lng + 180) % 360 + 360) % 360 - 180).toFixed(6));
+			update(field);
+		});
+
+		$(document).on('click', '.__ponzu-latlng-zoom button', function(e) {
+			e.preventDefault();
+
+			var map = $(this).closest('.__ponzu-latlng-map'),
+				zoom = Number(map.attr('data-zoom')) + Number($(this).attr('data-zoom'));
+
+			map.attr('data-zoom', Math.max(0, Math.min(19, zoom)));
+			draw(map.closest('.__ponzu-latlng'));
+		});
+
+		$('.__ponzu-latlng').each(function() {
+			draw($(this));
+		});
+	});
+</script>
+`
+}
@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+type testPlace struct {
+	Location string `json:"location"`
+}
+
+func TestLatLng(t *testing.T) {
+	view := string(LatLng("Location", &testPlace{Location: "51.5074, -0.1278"}, map[string]string{"label": "Location"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	for _, s := range []string{
+		`<label class="active" for="field-location">Location</label>`,
+		`id="field-location" class="__ponzu-latlng-lat" data-ponzu-display="true" min="-90" max="90"`,
+		`value="51.5074" />`,
+		`class="__ponzu-latlng-lng" data-ponzu-display="true" min="-180" max="180"`,
+		`value="-0.1278" />`,
+		`name="location" value="51.5074, -0.1278"`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	if strings.Contains(view, "__ponzu-latlng-map") {
+		t.Errorf("Expected no map without tiles, got: %s", view)
+	}
+
+	view = string(LatLng("Location", &testPlace{Location: "91,0"}, map[string]string{
+		"tiles": "https://tiles.example.com/{z}/{x}/{y}.png?key=a&b", "zoom": "5", "attribution": "<OSM>",
+	}))
+	view = view[:strings.Index(view, "<script>")]
+
+	for _, s := range []string{
+		`data-tiles="https://tiles.example.com/{z}/{x}/{y}.png?key=a&amp;b" data-zoom="5"`,
+		`<span class="__ponzu-latlng-attribution">&lt;OSM&gt;</span>`,
+		`name="location" value=""`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	if strings.Count(view, `value="" />`) != 3 {
+		t.Errorf("Expected an invalid point to be shown unset, got: %s", view)
+	}
+}
+
+func TestParseLatLng(t *testing.T) {
+	lat, lng, err := ParseLatLng("-33.8688, 151.2093")
+	if err != nil || lat != -33.8688 || lng != 151.2093 {
+		t.Errorf("Expected -33.8688 151.2093, got: %v %v %v", lat, lng, err)
+	}
+
+	for _, s := range []string{"", "51.5", "91,0", "0,-181", "a,b", "NaN,0", "1,2,3"} {
+		if _, _, err := ParseLatLng(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Link is a single item of a LinkList, such as an entry in a menu. It can be
+// used as the element type of the field's slice.
+type Link struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// LinkList returns the []byte of a repeatable pair of <input> HTML elements,
+// one for the text and one for the URL of each link in a list such as a menu.
+// The URL inputs are normalized and validated like those of URL, including
+// honoring attrs["schemes"]. Each item submits its pair as "name.0.label" and
+// "name.0.url", which can be read back with ParseLinks.
+// The field must be a slice of Link, or of any struct or map with Label and URL
+// values (matched case-insensitively, by `json` tag or field name).
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func LinkList(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("LinkList", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	scope := TagNameFromStructField(fieldName, p)
+	field, err := fieldByPath(fieldName, p)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var links []Link
+	if field.Kind() == reflect.Slice {
+		for i := 0; i < field.Len(); i++ {
+			values := blockValues(field.Index(i))
+			links = append(links, Link{
+				Label: valueFold(values, "label"),
+				URL:   valueFold(values, "url"),
+			})
+		}
+	}
+
+	if len(links) == 0 {
+		links = append(links, Link{})
+	}
+
+	schemes := DefaultURLSchemes
+	if attrs["schemes"] != "" {
+		schemes = strings.Split(attrs["schemes"], ",")
+	}
+
+	view := &bytes.Buffer{}
+	_, err = view.WriteString(repeatOpen("__ponzu-link-list "+scope, attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to LinkList buffer")
+		return nil
+	}
+
+	for i, link := range links {
+		name := fmt.Sprintf("%s.%d", scope, i)
+
+		var label string
+		if i == 0 && attrs["label"] != "" {
+			label = `<label class="active" for="` + fieldID(name+".label") + `">` + attrs["label"] + `</label>`
+		}
+
+		item := `<div class="__ponzu-link-item row">` + label +
+			`<div class="input-field col s5"><input type="text" id="` + fieldID(name+".label") + `" data-ponzu-key="label" data-ponzu-trim="true" name="` +
+			name + `.label" value="` + html.EscapeString(link.Label) + `" placeholder="` + htmlText("links.label") + `" /></div>` +
+			`<div class="input-field col s7"><input type="url" id="` + fieldID(name+".url") + `" aria-label="` + htmlText("links.url") + `" data-ponzu-key="url" data-ponzu-url="` +
+			html.EscapeString(strings.ToLower(strings.Join(schemes, ","))) + `" data-ponzu-trim="true" name="` +
+			name + `.url" value="` + html.EscapeString(link.URL) + `" placeholder="https://" /></div>` +
+			`</div>`
+
+		_, err = view.WriteString(item)
+		if err != nil {
+			log.Println("Error writing HTML string to LinkList buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(repeatClose(attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to LinkList buffer")
+		return nil
+	}
+
+	_, err = view.Write(urlScript())
+	if err != nil {
+		log.Println("Error writing HTML string to LinkList buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), RepeatControllerWithOptions(fieldName, p, "input[data-ponzu-key]", "div.__ponzu-link-item", repeatControllerOptions(attrs))...)
+}
+
+// valueFold returns the value in values whose key matches key, ignoring case
+func valueFold(values map[string]string, key string) string {
+	if v, ok := values[key]; ok {
+		return v
+	}
+
+	for k, v := range values {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// ParseLinks reconstructs the links submitted by a LinkList from the form
+// values, in the order they appeared in the editor. Items with neither a label
+// nor a URL are left out. URLs are normalized with NormalizeURL, but should
+// still be checked with ValidateURL before they are stored.
+func ParseLinks(form url.Values, fieldName string) []Link {
+	var links []Link
+	for _, values := range ParseBlocks(form, fieldName) {
+		link := Link{
+			Label: strings.TrimSpace(values["label"]),
+			URL:   NormalizeURL(values["url"]),
+		}
+
+		if link.Label == "" && link.URL == "" {
+			continue
+		}
+
+		links = append(links, link)
+	}
+
+	return links
+}
@@ -0,0 +1,85 @@
+package editor
+
+import (
+	"html"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type testNav struct {
+	Links []Link `json:"links"`
+}
+
+func TestLinkList(t *testing.T) {
+	p := &testNav{Links: []Link{{"Home", "https://example.com/"}, {`Say "hi"`, "https://example.com/?a=1&b=2"}}}
+
+	view := string(LinkList("Links", p, map[string]string{"label": "Menu"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	for _, want := range []string{
+		`<label class="active" for="field-links-0-label">Menu</label>`,
+		`name="links.0.label" value="Home" placeholder="Link text" />`,
+		`aria-label="URL" data-ponzu-key="url"`,
+		`name="links.1.label" value="Say &#34;hi&#34;"`,
+		`name="links.1.url" value="https://example.com/?a=1&amp;b=2"`,
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %s, got: %s", want, view)
+		}
+	}
+
+	defer SetStrings(nil)
+	SetStrings(map[string]string{"links.label": "Texte", "links.url": `Adresse "web"`})
+
+	view = string(LinkList("Links", p, map[string]string{}))
+	if !strings.Contains(view, `placeholder="Texte"`) || !strings.Contains(view, `aria-label="Adresse &#34;web&#34;"`) {
+		t.Errorf("Expected the translated and escaped labels, got: %s", view)
+	}
+}
+
+func TestParseLinks(t *testing.T) {
+	form := url.Values{
+		"links.3.label": {" About "},
+		"links.3.url":   {"example.com/about"},
+		"links.0.label": {"Home"},
+		"links.0.url":   {"https://example.com/"},
+		"links.1.label": {"  "},
+		"links.1.url":   {""},
+		"links.2.url":   {"https://example.com/blog"},
+	}
+
+	want := []Link{{"Home", "https://example.com/"}, {"", "https://example.com/blog"}, {"About", "https://example.com/about"}}
+	if got := ParseLinks(form, "links"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLinks() = %v, want %v", got, want)
+	}
+
+	if got := ParseLinks(url.Values{}, "links"); len(got) != 0 {
+		t.Errorf("Expected no links, got %v", got)
+	}
+}
+
+func TestLinksRoundTrip(t *testing.T) {
+	stored := []Link{{"Home", "https://example.com/"}, {`Say "hi"`, "https://example.com/?a=1&b=2"}}
+
+	// a stored empty row is rendered, but dropped once submitted
+	p := &testNav{Links: append(append([]Link{}, stored...), Link{})}
+	view := string(LinkList("Links", p, map[string]string{}))
+	view = view[:strings.Index(view, "<script>")]
+
+	form := url.Values{}
+	field := regexp.MustCompile(`<input [^>]*name="([^"]+)" value="([^"]*)"`)
+	for _, m := range field.FindAllStringSubmatch(view, -1) {
+		form.Add(m[1], html.UnescapeString(m[2]))
+	}
+
+	if form.Get("links.2.label") != "" || len(form["links.2.url"]) != 1 {
+		t.Fatalf("Expected the empty row to be submitted, got: %v", form)
+	}
+
+	if got := ParseLinks(form, "links"); !reflect.DeepEqual(got, stored) {
+		t.Errorf("ParseLinks() = %v, want %v", got, stored)
+	}
+}
@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Logger receives structured diagnostics from the editor package, such as a
+// field name which cannot be resolved on a content struct. msg describes the
+// event and keyvals are alternating keys and values giving its details.
+type Logger interface {
+	Log(msg string, keyvals ...interface{})
+}
+
+// LoggerFunc adapts an ordinary function to a Logger
+type LoggerFunc func(msg string, keyvals ...interface{})
+
+// Log calls f(msg, keyvals...)
+func (f LoggerFunc) Log(msg string, keyvals ...interface{}) {
+	f(msg, keyvals...)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(msg string, keyvals ...interface{}) {}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger = nopLogger{}
+)
+
+// SetLogger sets the Logger which receives the editor package's diagnostics,
+// for example during development:
+//
+//	editor.SetLogger(editor.LoggerFunc(func(msg string, kv ...interface{}) {
+//		log.Println(append([]interface{}{msg}, kv...)...)
+//	}))
+//
+// By default diagnostics are discarded. Passing nil restores the default.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = nopLogger{}
+	}
+
+	loggerMu.Lock()
+	logger = l
+	loggerMu.Unlock()
+}
+
+func logf(msg string, keyvals ...interface{}) {
+	loggerMu.RLock()
+	l := logger
+	loggerMu.RUnlock()
+
+	l.Log(msg, keyvals...)
+}
+
+// logResolveFailure reports that the field name could not be resolved on post,
+// naming the struct type and the first caller outside of this package, which
+// is usually the MarshalEditor method using the wrong name
+func logResolveFailure(name string, post interface{}, err error) {
+	logf("editor: cannot resolve field",
+		"field", name,
+		"type", fmt.Sprintf("%v", reflect.TypeOf(post)),
+		"caller", externalCaller(),
+		"error", err,
+	)
+}
+
+// externalCaller returns the "file:line (function)" of the first caller on
+// the stack outside of this package
+func externalCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	pkg := packagePath()
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkg+".") || isTestFunc(frame.Function) {
+			return fmt.Sprintf("%s:%d (%s)", frame.File, frame.Line, frame.Function)
+		}
+
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// packagePath returns the import path of this package, as it is named in the
+// functions of stack frames
+func packagePath() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+
+	return name[:strings.LastIndex(name, ".")]
+}
+
+// isTestFunc reports whether fn is a test within this package, which counts as
+// an external caller
+func isTestFunc(fn string) bool {
+	name := fn[strings.LastIndex(fn, ".")+1:]
+	return strings.HasPrefix(name, "Test") || strings.HasPrefix(name, "Benchmark")
+}
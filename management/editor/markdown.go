@@ -0,0 +1,327 @@
+package editor
+
+import (
+	"bytes"
+	"log"
+	"strings"
+)
+
+// markdownTool is a toolbar button of the Markdown editor
+type markdownTool struct {
+	title string // the key of its title in DefaultStrings
+	icon  string
+}
+
+// markdownTools are the toolbar buttons which can be chosen with
+// attrs["toolbar"], keyed by their names
+var markdownTools = map[string]markdownTool{
+	"bold":    {"markdown.bold", "format_bold"},
+	"italic":  {"markdown.italic", "format_italic"},
+	"heading": {"markdown.heading", "title"},
+	"link":    {"markdown.link", "insert_link"},
+	"quote":   {"markdown.quote", "format_quote"},
+	"code":    {"markdown.code", "code"},
+	"ul":      {"markdown.ul", "format_list_bulleted"},
+	"ol":      {"markdown.ol", "format_list_numbered"},
+}
+
+// DefaultMarkdownToolbar are the toolbar buttons of a Markdown editor which
+// doesn't set attrs["toolbar"]
+var DefaultMarkdownToolbar = []string{"bold", "italic", "heading", "link", "quote", "code", "ul", "ol"}
+
+// Markdown returns the []byte of a <textarea> HTML element for editing markdown,
+// with a toolbar and a preview of the rendered markdown. The raw markdown is
+// stored in the field. attrs["toolbar"] is a comma-separated list of the
+// toolbar buttons, out of bold, italic, heading, link, quote, code, ul and ol,
+// and defaults to DefaultMarkdownToolbar, or "none" for no toolbar. The preview
+// is toggled with a button, or is shown side by side with the textarea when
+// attrs["preview"] is "side". Without javascript, the field is a plain textarea.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Markdown(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Markdown", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+
+	tools := DefaultMarkdownToolbar
+	switch attrs["toolbar"] {
+	case "":
+	case "none":
+		tools = nil
+	default:
+		tools = strings.Split(attrs["toolbar"], ",")
+	}
+
+	preview := "toggle"
+	if attrs["preview"] == "side" {
+		preview = "side"
+	}
+
+	// style the source textarea, without changing attrs
+	className := "materialize-textarea __ponzu-markdown-source"
+	taAttrs := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		taAttrs[k] = v
+	}
+
+	if _, ok := taAttrs["class"]; ok {
+		taAttrs["class"] += " " + className
+	} else {
+		taAttrs["class"] = className
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-markdown ` + name + ` col s12" data-preview="` + preview + `">`)
+	if err != nil {
+		log.Println("Error writing HTML string to Markdown buffer")
+		return nil
+	}
+
+	// the toolbar is hidden until the script runs, since it does nothing without
+	_, err = view.WriteString(`<div class="__ponzu-markdown-toolbar" hidden>`)
+	if err != nil {
+		log.Println("Error writing HTML string to Markdown buffer")
+		return nil
+	}
+
+	for _, t := range tools {
+		t = strings.TrimSpace(t)
+		tool, ok := markdownTools[t]
+		if !ok {
+			logf("editor: unknown Markdown toolbar button", "button", t, "field", fieldName)
+			continue
+		}
+
+		_, err = view.WriteString(`<button type="button" class="btn-flat" data-md="` + t + `" title="` + htmlText(tool.title) + `">` +
+			`<i class="material-icons">` + tool.icon + `</i></button>`)
+		if err != nil {
+			log.Println("Error writing HTML string to Markdown buffer")
+			return nil
+		}
+	}
+
+	if preview == "toggle" {
+		_, err = view.WriteString(`<button type="button" class="btn-flat right __ponzu-markdown-toggle" title="` + htmlText("markdown.preview") + `">` +
+			`<i class="material-icons">visibility</i></button>`)
+		if err != nil {
+			log.Println("Error writing HTML string to Markdown buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`</div><div class="row">`)
+	if err != nil {
+		log.Println("Error writing HTML string to Markdown buffer")
+		return nil
+	}
+
+	_, err = view.Write(DOMElement(NewElement("textarea", attrs["label"], fieldName, p, taAttrs)))
+	if err != nil {
+		log.Println("Error writing HTML string to Markdown buffer")
+		return nil
+	}
+
+	_, err = view.WriteString(`<div class="__ponzu-markdown-preview card-panel" hidden></div></div></div>` + markdownScript())
+	if err != nil {
+		log.Println("Error writing HTML string to Markdown buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// markdownScript returns the script which renders the preview of, and applies
+// the toolbar buttons to, every Markdown editor of the page. Its renderer
+// supports the markdown the toolbar can produce, plus emphasis with asterisks
+// and fenced code blocks, and escapes everything else.
+func markdownScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuMarkdown) {
+			return;
+		}
+		window.__ponzuMarkdown = true;
+
+		var titles = {
+			edit: ` + jsString(text("markdown.edit")) + `,
+			preview: ` + jsString(text("markdown.preview")) + `
+		};
+
+		var escape = function(s) {
+			return s.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;');
+		}
+
+		var inline = function(s) {
+			return escape(s)
+				.replace(/` + "`" + `([^` + "`" + `]+)` + "`" + `/g, '<code>$1</code>')
+				.replace(/\*\*([^*]+)\*\*/g, '<strong>$1</strong>')
+				.replace(/(^|\W)[_*]([^_*]+)[_*](?=\W|$)/g, '$1<em>$2</em>')
+				.replace(/\[([^\]]+)\]\(([^)\s]+)\)/g, function(m, text, href) {
+					// only link to web, mail and relative URLs
+					if (/^[a-z][a-z0-9+.-]*:/i.test(href) && !/^(https?|mailto):/i.test(href)) {
+						return text;
+					}
+					return '<a href="' + href + '" target="_blank">' + text + '</a>';
+				});
+		}
+
+		var render = function(md) {
+			var lines = md.replace(/\r\n?/g, '\n').split('\n'),
+				out = [], para = [], list = null, code = null;
+
+			var flush = function() {
+				if (para.length) {
+					out.push('<p>' + inline(para.join(' ')) + '</p>');
+					para = [];
+				}
+				if (list) {
+					out.push('</' + list + '>');
+					list = null;
+				}
+			}
+
+			for (var i = 0; i < lines.length; i++) {
+				var line = lines[i], m;
+
+				if (code !== null) {
+					if (/^` + "```" + `/.test(line)) {
+						out.push('<pre><code>' + escape(code.join('\n')) + '</code></pre>');
+						code = null;
+					} else {
+						code.push(line);
+					}
+					continue;
+				}
+
+				if (/^` + "```" + `/.test(line)) {
+					flush();
+					code = [];
+				} else if ((m = /^(#{1,6})\s+(.*)$/.exec(line))) {
+					flush();
+					out.push('<h' + m[1].length + '>' + inline(m[2]) + '</h' + m[1].length + '>');
+				} else if ((m = /^>\s?(.*)$/.exec(line))) {
+					flush();
+					out.push('<blockquote>' + inline(m[1]) + '</blockquote>');
+				} else if ((m = /^\s*(?:([-*+])|\d+[.)])\s+(.*)$/.exec(line))) {
+					var tag = m[1] ? 'ul' : 'ol';
+					if (list !== tag) {
+						flush();
+						list = tag;
+						out.push('<' + tag + '>');
+					}
+					out.push('<li>' + inline(m[2]) + '</li>');
+				} else if (/^\s*$/.test(line)) {
+					flush();
+				} else {
+					if (list) {
+						flush();
+					}
+					para.push(line);
+				}
+			}
+
+			if (code !== null) {
+				out.push('<pre><code>' + escape(code.join('\n')) + '</code></pre>');
+			}
+			flush();
+
+			return out.join('\n');
+		}
+
+		// wrap surrounds the selection of the textarea el with before and
+		// after, or inserts placeholder between them if nothing is selected
+		var wrap = function(el, before, after, placeholder) {
+			var start = el.selectionStart, end = el.selectionEnd,
+				selected = el.value.slice(start, end) || placeholder;
+
+			el.value = el.value.slice(0, start) + before + selected + after + el.value.slice(end);
+			el.setSelectionRange(start + before.length, start + before.length + selected.length);
+		}
+
+		// prefix inserts prefix at the start of each line of the selection of
+		// the textarea el
+		var prefix = function(el, prefix) {
+			var start = el.selectionStart > 0 ? el.value.lastIndexOf('\n', el.selectionStart - 1) + 1 : 0,
+				end = el.selectionEnd,
+				lines = el.value.slice(start, end).split('\n');
+
+			for (var i = 0; i < lines.length; i++) {
+				lines[i] = (prefix === '1. ' ? String(i + 1) + '. ' : prefix) + lines[i];
+			}
+
+			var text = lines.join('\n');
+			el.value = el.value.slice(0, start) + text + el.value.slice(end);
+			el.setSelectionRange(start, start + text.length);
+		}
+
+		var tools = {
+			bold: function(el) { wrap(el, '**', '**', 'bold text'); },
+			italic: function(el) { wrap(el, '_', '_', 'italic text'); },
+			code: function(el) { wrap(el, '` + "`" + `', '` + "`" + `', 'code'); },
+			link: function(el) { wrap(el, '[', '](https://)', 'link text'); },
+			heading: function(el) { prefix(el, '## '); },
+			quote: function(el) { prefix(el, '> '); },
+			ul: function(el) { prefix(el, '- '); },
+			ol: function(el) { prefix(el, '1. '); }
+		};
+
+		var update = function(editor) {
+			var source = editor.find('.__ponzu-markdown-source'),
+				preview = editor.find('.__ponzu-markdown-preview');
+
+			if (!preview.is('[hidden]')) {
+				preview.html(render(source.val()));
+			}
+		}
+
+		$('.__ponzu-markdown').each(function() {
+			var editor = $(this);
+			editor.find('.__ponzu-markdown-toolbar').removeAttr('hidden');
+
+			if (editor.attr('data-preview') === 'side') {
+				editor.find('.__ponzu-markdown-source').closest('.input-field').removeClass('s12').addClass('s6');
+				editor.find('.__ponzu-markdown-preview').removeAttr('hidden').addClass('col s6');
+			}
+
+			update(editor);
+		});
+
+		$(document).on('input', '.__ponzu-markdown-source', function() {
+			update($(this).closest('.__ponzu-markdown'));
+		});
+
+		$(document).on('click', '.__ponzu-markdown-toolbar button[data-md]', function(e) {
+			e.preventDefault();
+
+			var editor = $(this).closest('.__ponzu-markdown'),
+				source = editor.find('.__ponzu-markdown-source'),
+				tool = tools[$(this).attr('data-md')];
+
+			if (!tool || source.is(':hidden')) {
+				return;
+			}
+
+			tool(source.get(0));
+			source.trigger('input').focus();
+		});
+
+		$(document).on('click', '.__ponzu-markdown-toggle', function(e) {
+			e.preventDefault();
+
+			var editor = $(this).closest('.__ponzu-markdown'),
+				field = editor.find('.__ponzu-markdown-source').closest('.input-field'),
+				preview = editor.find('.__ponzu-markdown-preview'),
+				previewing = preview.is('[hidden]');
+
+			field.toggle(!previewing);
+			preview.attr('hidden', previewing ? null : 'hidden');
+			$(this).find('.material-icons').text(previewing ? 'edit' : 'visibility');
+			$(this).attr('title', previewing ? titles.edit : titles.preview);
+			update(editor);
+		});
+	});
+</script>
+`
+}
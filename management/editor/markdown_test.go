@@ -0,0 +1,40 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownToolbar(t *testing.T) {
+	p := &testContact{Bio: "# Hello <world>"}
+
+	view := string(Markdown("Bio", p, map[string]string{"label": "Bio"}))
+	for _, tool := range DefaultMarkdownToolbar {
+		if !strings.Contains(view, `data-md="`+tool+`"`) {
+			t.Errorf("Expected the default toolbar to include %s, got: %s", tool, view)
+		}
+	}
+
+	if !strings.Contains(view, `name="bio" ># Hello &lt;world&gt;</textarea>`) {
+		t.Errorf("Expected the stored markdown in the textarea, got: %s", view)
+	}
+
+	view = string(Markdown("Bio", p, map[string]string{"toolbar": "bold, link,unknown", "preview": "side"}))
+	view = view[:strings.Index(view, "<script>")]
+	if strings.Count(view, `data-md="`) != 2 || !strings.Contains(view, `data-md="link"`) {
+		t.Errorf("Expected only the bold and link buttons, got: %s", view)
+	}
+
+	if !strings.Contains(view, `data-preview="side"`) || strings.Contains(view, "__ponzu-markdown-toggle") {
+		t.Errorf("Expected a side by side preview without a toggle, got: %s", view)
+	}
+
+	if strings.Contains(view, ` toolbar="`) || strings.Contains(view, ` preview="`) {
+		t.Errorf("Expected the editor attrs not to be rendered, got: %s", view)
+	}
+
+	view = string(Markdown("Bio", p, map[string]string{"toolbar": "none"}))
+	if strings.Contains(view, `data-md="`) {
+		t.Errorf("Expected no toolbar buttons, got: %s", view)
+	}
+}
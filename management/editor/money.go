@@ -0,0 +1,259 @@
+package editor
+
+import (
+	"bytes"
+	"html"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of a Money field without attrs["currency"]
+const DefaultCurrency = "USD"
+
+// rxCurrency matches ISO 4217 currency codes, such as "USD" or "EUR"
+var rxCurrency = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+// Money returns the []byte of an <input> HTML element for an amount of money,
+// such as a price, which is displayed formatted with the currency symbol and
+// grouping separators, e.g. "$1,234.56", while the plain decimal value, e.g.
+// "1234.56", is submitted under the field's name. attrs["currency"] is the
+// ISO 4217 code of the currency, which defaults to DefaultCurrency, and
+// attrs["locale"] is the BCP 47 locale the amount is formatted for, e.g.
+// "de-DE", which defaults to the browser's. Amounts are validated and
+// formatted again as they are entered, and the editor can't be saved while
+// one is not a valid amount.
+// An empty value is displayed empty rather than as zero, as is the zero value
+// of new content, which hasn't been set yet.
+// The field may be a string or any number.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Money(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Money", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := moneyValue(p, defaultValue(p, ValueFromStructField(fieldName, p), attrs))
+
+	currency := strings.ToUpper(attrs["currency"])
+	if currency == "" {
+		currency = DefaultCurrency
+	} else if !rxCurrency.MatchString(currency) {
+		logf("editor: invalid currency ignored", "field", fieldName, "currency", attrs["currency"])
+		currency = DefaultCurrency
+	}
+
+	displayAttrs := make(map[string]string, len(attrs)+3)
+	for k, v := range attrs {
+		if k != "label" {
+			displayAttrs[k] = v
+		}
+	}
+	displayAttrs["type"] = "text"
+	displayAttrs["inputmode"] = "decimal"
+	displayAttrs["autocomplete"] = "off"
+	if displayAttrs["class"] != "" {
+		displayAttrs["class"] += " __ponzu-money-display"
+	} else {
+		displayAttrs["class"] = "__ponzu-money-display"
+	}
+
+	e := &Element{
+		TagName: "input",
+		Attrs:   displayAttrs,
+		Name:    name,
+		Label:   attrs["label"],
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-money ` + name + ` input-field col s12" data-currency="` + currency +
+		`" data-locale="` + html.EscapeString(attrs["locale"]) + `">`)
+	if err != nil {
+		log.Println("Error writing HTML string to Money buffer")
+		return nil
+	}
+
+	if e.Label != "" {
+		_, err = view.WriteString(`<label class="active` + requiredClass(attrs) + `" for="` + html.EscapeString(elementID(e)) + `">` + e.Label + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to Money buffer")
+			return nil
+		}
+	}
+
+	// the displayed amount has no name, so that it isn't submitted
+	_, err = view.WriteString(`<input value="` + html.EscapeString(value) + `" `)
+	if err != nil {
+		log.Println("Error writing HTML string to Money buffer")
+		return nil
+	}
+
+	err = writeAttrs(view, displayAttrs)
+	if err != nil {
+		log.Println("Error writing HTML string to Money buffer")
+		return nil
+	}
+
+	err = writeLabelAttrs(view, e)
+	if err != nil {
+		log.Println("Error writing HTML string to Money buffer")
+		return nil
+	}
+
+	var required string
+	if isRequired(attrs) {
+		required = ` required`
+	}
+
+	_, err = view.WriteString(`/><input type="hidden" class="__ponzu-money-value" name="` + name + `" value="` +
+		html.EscapeString(value) + `"` + required + ` />` + helpText(attrs) + `</div>` + moneyScript())
+	if err != nil {
+		log.Println("Error writing HTML string to Money buffer")
+		return nil
+	}
+
+	if isRequired(attrs) {
+		_, err = view.WriteString(requiredScript())
+		if err != nil {
+			log.Println("Error writing HTML string to Money buffer")
+			return nil
+		}
+	}
+
+	return view.Bytes()
+}
+
+// moneyValue returns the stored value of a Money field as a plain decimal,
+// without an exponent, or empty for the zero value of new content
+func moneyValue(p interface{}, value string) string {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	if n == 0 && isNew(p) {
+		return ""
+	}
+
+	if strings.ContainsAny(value, "eE") {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	return value
+}
+
+// moneyScript returns the script which formats the amount of every Money field
+// of the page, and keeps the submitted decimal value in sync with it
+func moneyScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuMoney) {
+			return;
+		}
+		window.__ponzuMoney = true;
+
+		var message = ` + jsString(text("money.invalid")) + `;
+
+		// formatter returns the currency format of the Money field scope, or
+		// null if the browser doesn't support its currency or locale
+		var formatter = function(scope) {
+			try {
+				return new Intl.NumberFormat(scope.attr('data-locale') || undefined, {
+					style: 'currency',
+					currency: scope.attr('data-currency')
+				});
+			} catch (err) {
+				return null;
+			}
+		}
+
+		// parse returns the plain decimal value of the amount s, e.g. "1234.56"
+		// for "$1,234.56", or null if s isn't an amount. The separators of the
+		// field's locale are recognized, and the currency symbol is ignored.
+		var parse = function(scope, s) {
+			s = $.trim(s);
+			if (s === '') {
+				return '';
+			}
+
+			var f = formatter(scope), group = ',', decimal = '.';
+			if (f && f.formatToParts) {
+				$.each(f.formatToParts(12345.6), function(i, part) {
+					if (part.type === 'group') {
+						group = part.value;
+					}
+					if (part.type === 'decimal') {
+						decimal = part.value;
+					}
+				});
+			}
+
+			var negative = /^\(.*\)$|[-−]/.test(s);
+			s = s.split(group).join('').split(decimal).join('.').replace(/[^0-9.]/g, '');
+			if (!/^(\d+\.?\d*|\.\d+)$/.test(s)) {
+				return null;
+			}
+
+			s = s.replace(/\.$/, '').replace(/^\./, '0.').replace(/^0+(?=\d)/, '');
+			return (negative ? '-' : '') + s;
+		}
+
+		var format = function(scope, value) {
+			var f = formatter(scope);
+			if (value === '' || !f) {
+				return value;
+			}
+
+			return f.format(Number(value));
+		}
+
+		// update stores the amount of the displayed input, and formats it when
+		// reformat is true, flagging it when it isn't an amount
+		var update = function(input, reformat) {
+			var scope = $(input).closest('.__ponzu-money'),
+				value = parse(scope, input.value),
+				invalid = value === null;
+
+			input.setCustomValidity(invalid ? message : '');
+			$(input).toggleClass('invalid', invalid);
+			if (invalid) {
+				return;
+			}
+
+			scope.find('.__ponzu-money-value').val(value);
+			if (reformat) {
+				input.value = format(scope, value);
+			}
+		}
+
+		$(document).on('input', '.__ponzu-money-display', function() {
+			update(this, false);
+		});
+
+		$(document).on('change focusout', '.__ponzu-money-display', function() {
+			update(this, true);
+		});
+
+		$('form').on('submit', function(e) {
+			var invalid = $(this).find('.__ponzu-money-display.invalid');
+			if (invalid.length === 0) {
+				return;
+			}
+
+			e.preventDefault();
+			e.stopImmediatePropagation();
+
+			Materialize.toast(message, 4000);
+			invalid.first().focus();
+		});
+
+		$('.__ponzu-money').each(function() {
+			var scope = $(this);
+			scope.find('.__ponzu-money-display').val(format(scope, scope.find('.__ponzu-money-value').val()));
+		});
+	});
+</script>
+`
+}
@@ -0,0 +1,55 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+type testProduct struct {
+	ID    int     `json:"id"`
+	Price float64 `json:"price"`
+	Sale  string  `json:"sale"`
+}
+
+func (p *testProduct) ItemID() int { return p.ID }
+
+func TestMoney(t *testing.T) {
+	p := &testProduct{ID: 1, Price: 1234.56}
+
+	view := string(Money("Price", p, map[string]string{"label": "Price", "currency": "eur", "locale": "de-DE"}))
+	if !strings.Contains(view, `<input type="hidden" class="__ponzu-money-value" name="price" value="1234.56" />`) {
+		t.Errorf("Expected the decimal value to be submitted under the field name, got: %s", view)
+	}
+
+	if !strings.Contains(view, `data-currency="EUR" data-locale="de-DE"`) {
+		t.Errorf("Expected the currency and locale to be set, got: %s", view)
+	}
+
+	markup := view[:strings.Index(view, "<script>")]
+	if strings.Count(markup, `name="`) != 1 || strings.Contains(markup, `currency="eur"`) || strings.Contains(markup, ` locale=`) {
+		t.Errorf("Expected only the value to be submitted, and no editor attrs rendered, got: %s", markup)
+	}
+
+	if !strings.Contains(markup, `<label class="active" for="field-price">Price</label>`) || !strings.Contains(markup, `id="field-price"`) {
+		t.Errorf("Expected the label to be associated with the displayed amount, got: %s", markup)
+	}
+
+	for _, c := range []struct {
+		p    *testProduct
+		want string
+	}{
+		{&testProduct{}, `value=""`},
+		{&testProduct{ID: 1}, `value="0"`},
+		{&testProduct{ID: 1, Price: 2e6}, `value="2000000"`},
+	} {
+		view := string(Money("Price", c.p, map[string]string{}))
+		if !strings.Contains(view, `name="price" `+c.want) {
+			t.Errorf("Expected %s for %+v, got: %s", c.want, c.p, view)
+		}
+	}
+
+	view = string(Money("Sale", &testProduct{}, map[string]string{"currency": "dollars", "required": "true"}))
+	if !strings.Contains(view, `data-currency="USD"`) || !strings.Contains(view, `name="sale" value="" required />`) {
+		t.Errorf("Expected an invalid currency to be ignored, and the value to be required, got: %s", view)
+	}
+}
@@ -0,0 +1,123 @@
+package editor
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NumberRange returns the []byte of a pair of numeric <input> HTML elements
+// holding the low and high ends of a range, such as a price or age range. The
+// pair is submitted as "name.min" and "name.max", and is marked invalid unless
+// the low end is less than or equal to the high end. Either end may be left
+// empty. attrs["step"] sets the step of both inputs, and the "min" and "max"
+// attrs bound the values of both. Check the same constraint on the server with
+// ValidateNumberRange, since client-side checks can be bypassed.
+// The field must be a struct or map holding "min" and "max" values (matched
+// case-insensitively, by `json` tag or field name), e.g.
+//
+//	type PriceRange struct {
+//		Min float64 `json:"min"`
+//		Max float64 `json:"max"`
+//	}
+//
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func NumberRange(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("NumberRange", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	field, err := fieldByPath(fieldName, p)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	values := blockValues(field)
+
+	var bounds string
+	for _, a := range []string{"step", "min", "max"} {
+		if attrs[a] != "" {
+			bounds += ` ` + a + `="` + html.EscapeString(attrs[a]) + `"`
+		}
+	}
+
+	input := func(end, placeholder string) string {
+		return `<div class="input-field col s6"><input type="number"` + bounds +
+			` name="` + name + `.` + end + `" value="` + html.EscapeString(valueFold(values, end)) +
+			`" placeholder="` + placeholder + `" /></div>`
+	}
+
+	view := `<div class="__ponzu-number-range ` + name + ` col s12">`
+	if attrs["label"] != "" {
+		view += `<label class="active">` + attrs["label"] + `</label>`
+	}
+
+	view += `<div class="row">` + input("min", "From") + input("max", "To") + `</div>` +
+		`<span class="error red-text"></span></div>
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-number-range.` + selectorClass(name) + `'),
+				low = scope.find('input[name$=".min"]'),
+				high = scope.find('input[name$=".max"]'),
+				error = scope.find('.error');
+
+			var check = function() {
+				var l = parseFloat(low.val()),
+					h = parseFloat(high.val()),
+					message = '';
+
+				if (!isNaN(l) && !isNaN(h) && l > h) {
+					message = ` + jsString(text("numberrange.order")) + `;
+				}
+
+				high.get(0).setCustomValidity(message);
+				low.toggleClass('invalid', message !== '');
+				high.toggleClass('invalid', message !== '');
+				error.text(message);
+			}
+
+			scope.on('input change', 'input', check);
+			check();
+		});
+	</script>`
+
+	return []byte(view)
+}
+
+// ValidateNumberRange checks the values submitted by a NumberRange named
+// fieldName, e.g. "price", and returns an error if either end is not a number,
+// or if the low end is greater than the high end. Empty ends are allowed.
+func ValidateNumberRange(form url.Values, fieldName string) error {
+	parse := func(end string) (float64, bool, error) {
+		s := strings.TrimSpace(form.Get(fieldName + "." + end))
+		if s == "" {
+			return 0, false, nil
+		}
+
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("%s.%s must be a number, got %q", fieldName, end, s)
+		}
+
+		return n, true, nil
+	}
+
+	low, hasLow, err := parse("min")
+	if err != nil {
+		return err
+	}
+
+	high, hasHigh, err := parse("max")
+	if err != nil {
+		return err
+	}
+
+	if hasLow && hasHigh && low > high {
+		return fmt.Errorf("%s.min (%v) must not be greater than %s.max (%v)", fieldName, low, fieldName, high)
+	}
+
+	return nil
+}
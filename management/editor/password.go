@@ -0,0 +1,58 @@
+package editor
+
+// Password returns the []byte of an <input type="password"> HTML element for
+// secrets such as API keys, with a toggle to show and hide the stored value.
+// The browser is asked not to autofill the input with a saved password, which
+// would otherwise replace the stored secret.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Password(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Password", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+
+	inputAttrs := make(map[string]string, len(attrs)+4)
+	for k, v := range attrs {
+		inputAttrs[k] = v
+	}
+	inputAttrs["type"] = "password"
+	inputAttrs["autocomplete"] = "new-password"
+	inputAttrs["spellcheck"] = "false"
+	if inputAttrs["class"] != "" {
+		inputAttrs["class"] += " __ponzu-password-input"
+	} else {
+		inputAttrs["class"] = "__ponzu-password-input"
+	}
+
+	e := NewElement("input", attrs["label"], fieldName, p, inputAttrs)
+
+	return []byte(`<div class="__ponzu-password ` + name + `">` + string(DOMElementSelfClose(e)) +
+		`<button type="button" class="__ponzu-password-toggle btn-flat" title="` + htmlText("password.show") +
+		`" data-show="` + htmlText("password.show") + `" data-hide="` + htmlText("password.hide") + `">` +
+		`<i class="material-icons">visibility</i></button></div>` + passwordScript)
+}
+
+// passwordScript shows and hides the value of every Password of the page
+const passwordScript = `
+<script>
+	$(function() {
+		if (window.__ponzuPassword) {
+			return;
+		}
+		window.__ponzuPassword = true;
+
+		$(document).on('click', '.__ponzu-password-toggle', function(e) {
+			e.preventDefault();
+
+			var toggle = $(this),
+				input = toggle.closest('.__ponzu-password').find('.__ponzu-password-input'),
+				show = input.attr('type') === 'password';
+
+			input.attr('type', show ? 'text' : 'password');
+			toggle.attr('title', toggle.attr(show ? 'data-hide' : 'data-show'));
+			toggle.find('.material-icons').text(show ? 'visibility_off' : 'visibility');
+		});
+	});
+</script>
+`
@@ -0,0 +1,20 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPassword(t *testing.T) {
+	p := &testContact{Name: `s3cr"t`}
+
+	view := string(Password("Name", p, map[string]string{"label": "API key", "autocomplete": "on"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	for _, s := range []string{`type="password"`, `autocomplete="new-password"`, `value="s3cr&#34;t"`, `name="name"`,
+		`class="__ponzu-password-toggle btn-flat" title="Show"`} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package editor
+
+import (
+	"html"
+)
+
+// Range returns the []byte of an <input type="range"> HTML element, a slider
+// for values such as a priority from 0 to 100, with a readout of its value
+// which updates as the slider is dragged. The "min", "max" and "step" attrs
+// bound the slider, and default to 0, 100 and 1. An unset value starts the
+// slider in the middle of its bounds, as browsers do.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Range(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Range", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+
+	inputAttrs := make(map[string]string, len(attrs)+4)
+	for k, v := range attrs {
+		inputAttrs[k] = v
+	}
+	inputAttrs["type"] = "range"
+	for a, v := range map[string]string{"min": "0", "max": "100", "step": "1"} {
+		if inputAttrs[a] == "" {
+			inputAttrs[a] = v
+		}
+	}
+	if inputAttrs["class"] != "" {
+		inputAttrs["class"] += " __ponzu-range-input"
+	} else {
+		inputAttrs["class"] = "__ponzu-range-input"
+	}
+
+	e := NewElement("input", attrs["label"], fieldName, p, inputAttrs)
+
+	return []byte(`<div class="__ponzu-range ` + name + ` range-field">` + string(DOMElementSelfClose(e)) +
+		`<output class="__ponzu-range-value">` + html.EscapeString(e.Data) + `</output></div>` + rangeScript)
+}
+
+// rangeScript shows the value of every Range of the page as it is dragged
+const rangeScript = `
+<script>
+	$(function() {
+		if (window.__ponzuRange) {
+			return;
+		}
+		window.__ponzuRange = true;
+
+		var show = function(input) {
+			$(input).closest('.__ponzu-range').find('.__ponzu-range-value').text(input.value);
+		}
+
+		// show the value a browser starts an unset slider at
+		$('.__ponzu-range-input').each(function() {
+			show(this);
+		});
+
+		$(document).on('input change', '.__ponzu-range-input', function() {
+			show(this);
+		});
+	});
+</script>
+`
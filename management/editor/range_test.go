@@ -0,0 +1,18 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	p := &testMenuItem{Weight: 40}
+
+	view := string(Range("Weight", p, map[string]string{"label": "Priority", "max": "50"}))
+	for _, s := range []string{`type="range"`, `min="0"`, `max="50"`, `step="1"`, `value="40"`, `name="weight"`,
+		`<output class="__ponzu-range-value">40</output>`} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+}
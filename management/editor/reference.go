@@ -0,0 +1,291 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// DefaultReferenceURL is the admin endpoint which Reference fields search for
+// the content they can refer to
+const DefaultReferenceURL = "/admin/edit/reference"
+
+// DefaultReferenceCount is the most options returned by ReferenceOptions for a
+// search when no count is given
+const DefaultReferenceCount = 20
+
+// Reference returns the []byte of a searchable select of the items of another
+// content type, such as the author of a post, which stores the ID of the chosen
+// item, or its slug when attrs["store"] is "slug". Options are loaded from the
+// server as the editor types, rather than being inlined, so that content types
+// with many items can be referenced. Each option is labeled by the item's value
+// for the `json` key attrs["display"], which defaults to "title", and the
+// stored reference is labeled the same way once loaded.
+// The options are loaded from attrs["endpoint"], which defaults to
+// DefaultReferenceURL, with the query parameters "type", "display", "store",
+// and either "q" to search or "value" to look up the stored reference, and
+// with the editor's CSRF token in the CSRFHeaderName header. Any
+// endpoint must respond with JSON in the shape {"data": [{"value": "...",
+// "label": "..."}, ...]}, see ReferenceOptions.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Reference(fieldName string, p interface{}, attrs map[string]string, contentType string) []byte {
+	checkAttrs("Reference", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+
+	return []byte(`<div class="__ponzu-reference-field ` + name + `">` +
+		referenceInput(name, value, attrs["label"], contentType, attrs) + `</div>` + referenceScript)
+}
+
+// ReferenceRepeater returns the []byte of a Reference for each of the stored
+// references of a slice field, for many-to-many relations. It also includes
+// repeat controllers (+ / -) so the references can be dynamically multiplied or
+// reduced.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func ReferenceRepeater(fieldName string, p interface{}, attrs map[string]string, contentType string) []byte {
+	checkAttrs("ReferenceRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	scope := TagNameFromStructField(fieldName, p)
+	vals := ValuesFromStructField(fieldName, p)
+	if len(vals) == 0 {
+		vals = []string{""}
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(repeatOpen(scope, attrs))
+	if err != nil {
+		log.Println("Error writing HTML string to ReferenceRepeater buffer")
+		return nil
+	}
+
+	for i, val := range vals {
+		var label string
+		if i == 0 {
+			label = attrs["label"]
+		}
+
+		_, err = view.WriteString(referenceInput(TagNameFromStructFieldMulti(fieldName, i, p), val, label, contentType, attrs))
+		if err != nil {
+			log.Println("Error writing HTML string to ReferenceRepeater buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(repeatClose(attrs) + referenceScript)
+	if err != nil {
+		log.Println("Error writing HTML string to ReferenceRepeater buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), RepeatControllerWithOptions(fieldName, p, "input.__ponzu-reference-value", "div.__ponzu-reference", repeatControllerOptions(attrs))...)
+}
+
+// referenceInput returns the markup of a single reference named name holding
+// value. The search input is marked with data-ponzu-display, so that it is never
+// named by RepeatController.
+func referenceInput(name, value, label, contentType string, attrs map[string]string) string {
+	endpoint := attrs["endpoint"]
+	if endpoint == "" {
+		endpoint = DefaultReferenceURL
+	}
+
+	display := attrs["display"]
+	if display == "" {
+		display = "title"
+	}
+
+	store := "id"
+	if attrs["store"] == "slug" {
+		store = "slug"
+	}
+
+	placeholder := attrs["placeholder"]
+	if placeholder == "" {
+		placeholder = text("reference.search")
+	}
+
+	id := fieldID(name)
+	if label != "" {
+		label = `<label class="active" for="` + id + `">` + label + `</label>`
+	}
+
+	return `<div class="__ponzu-reference input-field col s12" data-endpoint="` + html.EscapeString(endpoint) +
+		`" data-type="` + html.EscapeString(contentType) + `" data-display="` + html.EscapeString(display) +
+		`" data-store="` + store + `">` + label +
+		`<input type="text" id="` + id + `" class="__ponzu-reference-search" data-ponzu-display="true" autocomplete="off" placeholder="` +
+		html.EscapeString(placeholder) + `" value="` + html.EscapeString(value) + `" />` +
+		`<ul class="__ponzu-reference-results collection"></ul>` +
+		`<input type="hidden" class="__ponzu-reference-value" name="` + name + `" value="` + html.EscapeString(value) + `" />` +
+		`</div>`
+}
+
+// ReferenceOptions returns the options for a Reference out of items, the JSON
+// encoded items of the referenced content type, as served by the endpoint of a
+// Reference. Each option's Value is the item's "id", or its "slug" when store
+// is "slug", and its Label is the item's value for the key display, or its slug
+// if that is empty. When value is not empty, only the item it refers to is
+// returned. Otherwise, the items whose labels contain query, ignoring case, are
+// returned, up to count of them, or DefaultReferenceCount if count is zero.
+func ReferenceOptions(items [][]byte, display, store, query, value string, count int) []Option {
+	if store != "slug" {
+		store = "id"
+	}
+
+	if count == 0 {
+		count = DefaultReferenceCount
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	opts := []Option{}
+	for _, b := range items {
+		var item map[string]interface{}
+		err := json.Unmarshal(b, &item)
+		if err != nil {
+			continue
+		}
+
+		opt := Option{
+			Value: jsonString(item[store]),
+			Label: jsonString(item[display]),
+		}
+
+		if opt.Label == "" {
+			opt.Label = jsonString(item["slug"])
+		}
+
+		if value != "" {
+			if opt.Value == value {
+				return []Option{opt}
+			}
+
+			continue
+		}
+
+		if !strings.Contains(strings.ToLower(opt.Label), query) {
+			continue
+		}
+
+		opts = append(opts, opt)
+		if count > 0 && len(opts) == count {
+			break
+		}
+	}
+
+	return opts
+}
+
+// jsonString returns the decoded JSON value v as a string, formatting numbers
+// without exponents so that IDs are unchanged
+func jsonString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// referenceScript searches for and chooses the references of every Reference
+// of the page, including those added by RepeatController
+const referenceScript = `
+<script>
+	$(function() {
+		if (window.__ponzuReference) {
+			return;
+		}
+		window.__ponzuReference = true;
+
+		// load requests the options of the reference field from its endpoint
+		var load = function(field, params, done) {
+			params.type = field.attr('data-type');
+			params.display = field.attr('data-display');
+			params.store = field.attr('data-store');
+
+			$.ajax({
+				url: field.attr('data-endpoint'),
+				data: params,
+				dataType: 'json',
+				headers: {'` + CSRFHeaderName + `': ` + csrfToken + `}
+			}).done(function(resp) {
+				done((resp && resp.data) || []);
+			});
+		}
+
+		var choose = function(field, value, label) {
+			field.find('.__ponzu-reference-value').val(value).trigger('change');
+			field.find('.__ponzu-reference-search').val(label);
+			field.find('.__ponzu-reference-results').empty();
+		}
+
+		// label the stored references once their items are loaded
+		$('.__ponzu-reference').each(function() {
+			var field = $(this),
+				value = field.find('.__ponzu-reference-value').val();
+
+			if (value === '') {
+				return;
+			}
+
+			load(field, {value: value}, function(opts) {
+				if (opts.length > 0) {
+					field.find('.__ponzu-reference-search').val(opts[0].label);
+				}
+			});
+		});
+
+		var timer = null;
+		$(document).on('input', '.__ponzu-reference-search', function() {
+			var field = $(this).closest('.__ponzu-reference'),
+				results = field.find('.__ponzu-reference-results'),
+				q = $.trim(this.value);
+
+			// clearing the search clears the reference
+			if (q === '') {
+				choose(field, '', '');
+				return;
+			}
+
+			clearTimeout(timer);
+			timer = setTimeout(function() {
+				load(field, {q: q}, function(opts) {
+					results.empty();
+					$.each(opts, function(i, opt) {
+						results.append($('<li class="collection-item"></li>')
+							.text(opt.label)
+							.attr('data-value', opt.value));
+					});
+				});
+			}, 250);
+		});
+
+		$(document).on('mousedown', '.__ponzu-reference-results li', function(e) {
+			e.preventDefault();
+			choose($(this).closest('.__ponzu-reference'), $(this).attr('data-value'), $(this).text());
+		});
+
+		$(document).on('blur', '.__ponzu-reference-search', function() {
+			var results = $(this).closest('.__ponzu-reference').find('.__ponzu-reference-results');
+
+			// leave time for a click on a result to register
+			setTimeout(function() { results.empty(); }, 200);
+		});
+	});
+</script>
+`
@@ -0,0 +1,56 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReferenceOptions(t *testing.T) {
+	items := [][]byte{
+		[]byte(`{"id": 1, "slug": "jane-doe", "name": "Jane Doe"}`),
+		[]byte(`{"id": 12345678, "slug": "john-doe", "name": "John Doe"}`),
+		[]byte(`{"id": 3, "slug": "no-name"}`),
+		[]byte(`not json`),
+	}
+
+	opts := ReferenceOptions(items, "name", "", "DOE", "", 0)
+	if len(opts) != 2 || opts[0] != (Option{"1", "Jane Doe"}) || opts[1] != (Option{"12345678", "John Doe"}) {
+		t.Errorf("Expected both Does by id, got: %+v", opts)
+	}
+
+	opts = ReferenceOptions(items, "name", "slug", "", "", 1)
+	if len(opts) != 1 || opts[0].Value != "jane-doe" {
+		t.Errorf("Expected the first item by slug, got: %+v", opts)
+	}
+
+	opts = ReferenceOptions(items, "name", "id", "", "3", 0)
+	if len(opts) != 1 || opts[0] != (Option{"3", "no-name"}) {
+		t.Errorf("Expected the referenced item labeled by its slug, got: %+v", opts)
+	}
+
+	opts = ReferenceOptions(items, "name", "id", "", "4", 0)
+	if opts == nil || len(opts) != 0 {
+		t.Errorf("Expected no options for an unknown reference, got: %+v", opts)
+	}
+}
+
+func TestReferenceRepeater(t *testing.T) {
+	p := &testContact{Links: []string{"1", `2"`}}
+
+	view := string(ReferenceRepeater("Links", p, map[string]string{"label": "Authors", "store": "slug"}, "Author"))
+
+	for _, s := range []string{
+		`data-type="Author" data-display="title" data-store="slug"`,
+		`name="links.0" value="1"`,
+		`name="links.1" value="2&#34;"`,
+		`data-endpoint="` + DefaultReferenceURL + `"`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	if strings.Count(view, "<label") != 1 {
+		t.Errorf("Expected only the first reference to be labeled, got: %s", view)
+	}
+}
@@ -3,13 +3,29 @@ package editor
 import (
 	"bytes"
 	"fmt"
+	"html"
+	"io"
 	"log"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // InputRepeater returns the []byte of an <input> HTML element with a label.
 // It also includes repeat controllers (+ / -) so the element can be
 // dynamically multiplied or reduced.
+// Setting attrs["unique"] to "true", or to "ignorecase" to ignore the case of
+// the values, requires every value to be different before saving.
+// attrs["pattern"] is a regular expression which each value must match, such as
+// a postal code format, and attrs["title"] is the message shown for a value
+// which doesn't. Each value is validated as it is typed, and empty values are
+// always valid unless attrs["required"] is set. attrs["maxlength"] limits the
+// length of each value, shown by a live counter of the characters used by each
+// item, and attrs["countwords"] set to "true" counts their words instead.
+// attrs["prefix"] and attrs["suffix"] are fixed text shown before and after
+// each input, e.g. "https://" or "%", which is not part of its value.
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
@@ -33,466 +49,1843 @@ import (
 // 		)
 // 	}
 func InputRepeater(fieldName string, p interface{}, attrs map[string]string) []byte {
+	view := &bytes.Buffer{}
+	err := InputRepeaterTo(view, fieldName, p, attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to InputRepeater buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// InputRepeaterTo is like InputRepeater, but writes the element to w as it is
+// rendered, rather than returning it, so that a form of many repeaters can be
+// streamed without being held in memory (see FormTo)
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func InputRepeaterTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string) error {
+	checkAttrs("InputRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		_, err := w.Write(view)
+		return err
+	}
+
 	// find the field values in p to determine pre-filled inputs
-	fieldVals := ValueFromStructField(fieldName, p)
-	vals := strings.Split(fieldVals, "__ponzu")
+	vals := defaultValues(p, ValuesFromStructField(fieldName, p), attrs)
 
-	scope := TagNameFromStructField(fieldName, p)
-	html := bytes.Buffer{}
+	var script string
+	if repeatUnique(attrs) != "" {
+		script += uniqueScript()
+	}
+	if attrs["pattern"] != "" {
+		script += patternScript
+	}
+
+	counted := counterAttrs(fieldName, attrs)
+	if counted["data-ponzu-count"] != "" {
+		script += counterScript()
+	}
+
+	r := repeater{
+		class:  TagNameFromStructField(fieldName, p),
+		values: vals,
+		script: script,
+		input:  "input",
+		clone:  ".input-field",
+	}
+
+	// each input is rendered into the same buffer before being written to w
+	buf := &bytes.Buffer{}
+	itemAttrs := repeatItemAttrs(counted)
+	return renderRepeater(w, fieldName, p, attrs, r, func(w io.Writer, item repeatItem) error {
+		buf.Reset()
+		_, err := w.Write(DOMElementSelfClose(&Element{
+			TagName: "input",
+			Attrs:   invalidAttrs(itemAttrs, item.Error != ""),
+			Name:    item.Name,
+			Label:   item.Label,
+			Data:    item.Value,
+			ViewBuf: buf,
+		}))
+		return err
+	})
+}
 
-	_, err := html.WriteString(`<span class="__ponzu-repeat ` + scope + `">`)
+// TextareaRepeater returns the []byte of a <textarea> HTML element with a label.
+// It also includes repeat controllers (+ / -) so the element can be
+// dynamically multiplied or reduced, and is useful for lists of longer text such
+// as testimonials or FAQ answers. attrs["rows"] sets the height of each textarea.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func TextareaRepeater(fieldName string, p interface{}, attrs map[string]string) []byte {
+	view := &bytes.Buffer{}
+	err := TextareaRepeaterTo(view, fieldName, p, attrs)
 	if err != nil {
-		log.Println("Error writing HTML string to InputRepeater buffer")
+		log.Println("Error writing HTML string to TextareaRepeater buffer")
 		return nil
 	}
 
-	for i, val := range vals {
-		el := &Element{
+	return view.Bytes()
+}
+
+// TextareaRepeaterTo is like TextareaRepeater, but writes the element to w as
+// it is rendered, rather than returning it
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func TextareaRepeaterTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string) error {
+	checkAttrs("TextareaRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		_, err := w.Write(view)
+		return err
+	}
+
+	// find the field values in p to determine pre-filled textareas
+	vals := defaultValues(p, ValuesFromStructField(fieldName, p), attrs)
+
+	// add materialize css class to make UI correct, without changing attrs
+	taAttrs := make(map[string]string, len(attrs)+1)
+	for k, v := range repeatItemAttrs(attrs) {
+		taAttrs[k] = v
+	}
+
+	if taAttrs["class"] != "" {
+		taAttrs["class"] += " materialize-textarea"
+	} else {
+		taAttrs["class"] = "materialize-textarea"
+	}
+
+	r := repeater{
+		class:  TagNameFromStructField(fieldName, p),
+		values: vals,
+		input:  "textarea",
+		clone:  ".input-field",
+	}
+
+	buf := &bytes.Buffer{}
+	return renderRepeater(w, fieldName, p, attrs, r, func(w io.Writer, item repeatItem) error {
+		buf.Reset()
+		_, err := w.Write(DOMElement(&Element{
+			TagName: "textarea",
+			Attrs:   invalidAttrs(taAttrs, item.Error != ""),
+			Name:    item.Name,
+			Label:   item.Label,
+			Data:    item.Value,
+			ViewBuf: buf,
+		}))
+		return err
+	})
+}
+
+// NumberRepeater returns the []byte of an <input type="number"> HTML element
+// with a label, which accepts only numeric characters and is marked valid or
+// invalid as it is edited, according to the "min", "max" and "step" attrs. It
+// also includes repeat controllers (+ / -) so the element can be dynamically
+// multiplied or reduced. Empty inputs are left out when the form is submitted,
+// rather than being stored as 0, and the submitted values can be read back with
+// ParseNumbers.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func NumberRepeater(fieldName string, p interface{}, attrs map[string]string) []byte {
+	view := &bytes.Buffer{}
+	err := NumberRepeaterTo(view, fieldName, p, attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to NumberRepeater buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// NumberRepeaterTo is like NumberRepeater, but writes the element to w as it
+// is rendered, rather than returning it
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func NumberRepeaterTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string) error {
+	checkAttrs("NumberRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		_, err := w.Write(view)
+		return err
+	}
+
+	// make the inputs numeric, without changing attrs
+	numAttrs := make(map[string]string, len(attrs)+2)
+	for k, v := range attrs {
+		numAttrs[k] = v
+	}
+
+	numAttrs["type"] = "number"
+	if _, ok := numAttrs["inputmode"]; !ok {
+		numAttrs["inputmode"] = "decimal"
+	}
+
+	r := repeater{
+		class:  "__ponzu-number-repeat " + TagNameFromStructField(fieldName, p),
+		values: defaultValues(p, ValuesFromStructField(fieldName, p), attrs),
+		script: numberRepeaterScript,
+		input:  "input",
+		clone:  ".input-field",
+	}
+
+	buf := &bytes.Buffer{}
+	itemAttrs := repeatItemAttrs(numAttrs)
+	return renderRepeater(w, fieldName, p, numAttrs, r, func(w io.Writer, item repeatItem) error {
+		buf.Reset()
+		_, err := w.Write(DOMElementSelfClose(&Element{
 			TagName: "input",
-			Attrs:   attrs,
-			Name:    TagNameFromStructFieldMulti(fieldName, i, p),
-			Data:    val,
-			ViewBuf: &bytes.Buffer{},
+			Attrs:   invalidAttrs(itemAttrs, item.Error != ""),
+			Name:    item.Name,
+			Label:   item.Label,
+			Data:    item.Value,
+			ViewBuf: buf,
+		}))
+		return err
+	})
+}
+
+// patternScript validates the inputs of every repeater of the page which have a
+// pattern as they are typed, showing their title as the message of a value
+// which doesn't match. Browsers don't match empty values against a pattern, so
+// an empty optional input stays valid.
+const patternScript = `
+<script>
+	(function() {
+		if (window.__ponzuPattern) {
+			return;
 		}
+		window.__ponzuPattern = true;
 
-		// only add the label to the first input in repeated list
-		if i == 0 {
-			el.Label = attrs["label"]
+		var check = function(e) {
+			var input = e.target;
+			if (!input.matches || !input.matches('.__ponzu-repeat input[pattern]')) {
+				return;
+			}
+
+			input.setCustomValidity('');
+			if (input.validity.patternMismatch && input.title) {
+				input.setCustomValidity(input.title);
+			}
+
+			var valid = input.checkValidity();
+			input.classList.toggle('invalid', !valid);
+			input.classList.toggle('valid', valid && input.value.trim() !== '');
+		}
+
+		['input', 'change', 'focusout'].forEach(function(type) {
+			document.addEventListener(type, check);
+		});
+	})();
+</script>
+`
+
+// numberRepeaterScript restricts the inputs of every NumberRepeater of the page
+// to numeric characters, validates them as they are edited, and leaves out the
+// empty ones when their form is submitted, renumbering the rest
+const numberRepeaterScript = `
+<script>
+	(function() {
+		if (window.__ponzuNumberRepeater) {
+			return;
+		}
+		window.__ponzuNumberRepeater = true;
+
+		var sel = '.__ponzu-number-repeat input[type=number]';
+
+		var isNumber = function(el) {
+			return el.matches ? el.matches(sel) : false;
+		}
+
+		document.addEventListener('keypress', function(e) {
+			if (!isNumber(e.target) || e.ctrlKey || e.metaKey || e.which < 32) {
+				return;
+			}
+
+			if (!/[0-9.,eE+-]/.test(String.fromCharCode(e.which))) {
+				e.preventDefault();
+			}
+		});
+
+		['input', 'change'].forEach(function(type) {
+			document.addEventListener(type, function(e) {
+				if (!isNumber(e.target)) {
+					return;
+				}
+
+				var valid = e.target.checkValidity();
+				e.target.classList.toggle('invalid', !valid);
+				e.target.classList.toggle('valid', valid && e.target.value !== '');
+			});
+		});
+
+		// renumber before any other submit handler serializes the form
+		document.addEventListener('submit', function(e) {
+			Array.prototype.forEach.call(e.target.querySelectorAll('.__ponzu-repeat.__ponzu-number-repeat'), function(scope) {
+				var i = 0;
+				Array.prototype.forEach.call(scope.querySelectorAll('input[type=number]'), function(input) {
+					var name = input.getAttribute('name') || input.getAttribute('data-ponzu-name') || '';
+					if (name === '') {
+						return;
+					}
+
+					input.setAttribute('data-ponzu-name', name);
+					if (input.value === '') {
+						input.setAttribute('name', '');
+						return;
+					}
+
+					input.setAttribute('name', name.replace(/\.\d+$/, '.' + String(i)));
+					i++;
+				});
+			});
+		}, true);
+	})();
+</script>
+`
+
+// ParseNumbers returns the numbers submitted by a NumberRepeater named fieldName,
+// e.g. "prices", in order. They are read from the field's indexed values
+// (name.0, name.1, ...), or from its values once they have been folded into the
+// field's name by the admin. Empty values are skipped, and an error is
+// returned for any value which isn't a number.
+func ParseNumbers(form url.Values, fieldName string) ([]float64, error) {
+	vals := indexedValues(form, fieldName)
+	if len(vals) == 0 {
+		vals = form[fieldName]
+	}
+
+	var nums []float64
+	for i, v := range vals {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
 		}
 
-		_, err := html.Write(DOMElementSelfClose(el))
+		n, err := strconv.ParseFloat(v, 64)
 		if err != nil {
-			log.Println("Error writing DOMElementSelfClose to InputRepeater buffer")
-			return nil
+			return nil, fmt.Errorf("%s.%d must be a number, got %q", fieldName, i, v)
 		}
+
+		nums = append(nums, n)
 	}
-	_, err = html.WriteString(`</span>`)
-	if err != nil {
-		log.Println("Error writing HTML string to InputRepeater buffer")
-		return nil
+
+	return nums, nil
+}
+
+// indexedValues returns the first values of the indexed names of fieldName in
+// form (name.0, name.1, ...), ordered by their indexes
+func indexedValues(form url.Values, fieldName string) []string {
+	byIndex := make(map[int]string)
+	var indexes []int
+	for key, vals := range form {
+		if !strings.HasPrefix(key, fieldName+".") || len(vals) == 0 {
+			continue
+		}
+
+		i, err := strconv.Atoi(strings.TrimPrefix(key, fieldName+"."))
+		if err != nil || i < 0 {
+			continue
+		}
+
+		byIndex[i] = vals[0]
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	vals := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		vals = append(vals, byIndex[i])
 	}
 
-	return append(html.Bytes(), RepeatController(fieldName, p, "input", ".input-field")...)
+	return vals
 }
 
 // SelectRepeater returns the []byte of a <select> HTML element plus internal <options> with a label.
 // It also includes repeat controllers (+ / -) so the element can be
 // dynamically multiplied or reduced.
+// Setting attrs["unique"] to "true", or to "ignorecase" to ignore the case of
+// the values, requires every value to be different, and disables the options
+// already chosen in the other selects.
+// Each select starts with a call to action, labeled by attrs["ctaLabel"] when
+// it is set, or omitted when it is set empty, followed by a "None" option
+// which resets the value, unless attrs["hideReset"] is "true".
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
 func SelectRepeater(fieldName string, p interface{}, attrs, options map[string]string) []byte {
 	// options are the value attr and the display value, i.e.
-	// <option value="{map key}">{map value}</option>
-	scope := TagNameFromStructField(fieldName, p)
-	html := bytes.Buffer{}
-	_, err := html.WriteString(`<span class="__ponzu-repeat ` + scope + `">`)
+	// <option value="{map key}">{map value}</option>, displayed in order of
+	// their display values so the order is the same on every render
+	return SelectRepeaterOrdered(fieldName, p, attrs, sortedOptions(options))
+}
+
+// SelectRepeaterTo is like SelectRepeater, but writes the element to w as it is
+// rendered, rather than returning it
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func SelectRepeaterTo(w io.Writer, fieldName string, p interface{}, attrs, options map[string]string) error {
+	return SelectRepeaterOrderedTo(w, fieldName, p, attrs, sortedOptions(options))
+}
+
+// SelectRepeaterOrdered is like SelectRepeater, but renders the options in the
+// order they are given. The call to action and "None" options, when they are
+// shown, always come first.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func SelectRepeaterOrdered(fieldName string, p interface{}, attrs map[string]string, options []Option) []byte {
+	view := &bytes.Buffer{}
+	err := SelectRepeaterOrderedTo(view, fieldName, p, attrs, options)
 	if err != nil {
 		log.Println("Error writing HTML string to SelectRepeater buffer")
 		return nil
 	}
 
+	return view.Bytes()
+}
+
+// SelectRepeaterOrderedTo is like SelectRepeaterOrdered, but writes the element
+// to w as it is rendered, rather than returning it
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func SelectRepeaterOrderedTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string, options []Option) error {
+	checkAttrs("SelectRepeater", fieldName, attrs)
+
+	return selectRepeaterTo(w, fieldName, p, attrs, []OptGroup{{Options: options}})
+}
+
+// OptGroup is a labeled group of the options offered by a select, rendered as
+// an <optgroup>, e.g. the countries of a continent
+type OptGroup struct {
+	Label   string   `json:"label"`
+	Options []Option `json:"options"`
+}
+
+// SelectRepeaterGrouped is like SelectRepeaterOrdered, but renders the options
+// within the labeled groups, which are rendered in the order they are given.
+// The options of a group without a Label are rendered outside of any group.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func SelectRepeaterGrouped(fieldName string, p interface{}, attrs map[string]string, groups []OptGroup) []byte {
+	view := &bytes.Buffer{}
+	err := SelectRepeaterGroupedTo(view, fieldName, p, attrs, groups)
+	if err != nil {
+		log.Println("Error writing HTML string to SelectRepeaterGrouped buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// SelectRepeaterGroupedTo is like SelectRepeaterGrouped, but writes the element
+// to w as it is rendered, rather than returning it
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func SelectRepeaterGroupedTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string, groups []OptGroup) error {
+	checkAttrs("SelectRepeaterGrouped", fieldName, attrs)
+
+	return selectRepeaterTo(w, fieldName, p, attrs, groups)
+}
+
+// selectRepeaterTo writes the SelectRepeater of the options in groups to w
+func selectRepeaterTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string, groups []OptGroup) error {
+	if view := contentError(fieldName, p); view != nil {
+		_, err := w.Write(view)
+		return err
+	}
+
 	// find the field values in p to determine if an option is pre-selected
-	fieldVals := ValueFromStructField(fieldName, p)
-	vals := strings.Split(fieldVals, "__ponzu")
+	vals := defaultValues(p, ValuesFromStructField(fieldName, p), attrs)
+
+	// style the selects, without changing attrs
+	selAttrs := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		selAttrs[k] = v
+	}
 
-	if _, ok := attrs["class"]; ok {
-		attrs["class"] += " browser-default"
+	if _, ok := selAttrs["class"]; ok {
+		selAttrs["class"] += " browser-default"
 	} else {
-		attrs["class"] = "browser-default"
+		selAttrs["class"] = "browser-default"
 	}
 
-	// loop through vals and create selects and options for each, adding to html
-	if len(vals) > 0 {
-		for i, val := range vals {
-			sel := &Element{
-				TagName: "select",
-				Attrs:   attrs,
-				Name:    TagNameFromStructFieldMulti(fieldName, i, p),
-				ViewBuf: &bytes.Buffer{},
-			}
+	// option returns the element of an option, selected when it holds val
+	option := func(o Option, val string) *Element {
+		optAttrs := map[string]string{"value": o.Value}
+		if o.Value == val {
+			optAttrs["selected"] = "true"
+		}
+
+		return &Element{
+			TagName: "option",
+			Attrs:   optAttrs,
+			Data:    o.Label,
+			ViewBuf: &bytes.Buffer{},
+		}
+	}
+
+	cta, ok := attrs["ctaLabel"]
+	if !ok {
+		cta = text("select.cta")
+	}
+	hideReset := attrs["hideReset"] == "true"
 
-			// only add the label to the first select in repeated list
-			if i == 0 {
-				sel.Label = attrs["label"]
+	// selectable reports whether an option of the select holds val
+	selectable := func(val string) bool {
+		if val == "" {
+			return !hideReset
+		}
+
+		for _, g := range groups {
+			for _, o := range g.Options {
+				if o.Value == val {
+					return true
+				}
 			}
+		}
+
+		return false
+	}
+
+	var script string
+	if repeatUnique(attrs) != "" {
+		script = uniqueScript()
+	}
+
+	r := repeater{
+		class:  TagNameFromStructField(fieldName, p),
+		values: vals,
+		script: script,
+		input:  "select",
+		clone:  ".input-field",
+	}
+
+	// create a select and options for each value, writing them to w from the
+	// same buffer
+	buf := &bytes.Buffer{}
+	itemAttrs := repeatItemAttrs(selAttrs)
+	return renderRepeater(w, fieldName, p, selAttrs, r, func(w io.Writer, item repeatItem) error {
+		buf.Reset()
+		sel := &Element{
+			TagName: "select",
+			Attrs:   invalidAttrs(itemAttrs, item.Error != ""),
+			Name:    item.Name,
+			Label:   item.Label,
+			ViewBuf: buf,
+		}
 
-			// create options for select element
-			var opts []*Element
+		var opts []*Element
 
-			// provide a call to action for the select element
-			cta := &Element{
+		// provide a call to action for the select element. Without one, an
+		// item which no option can show still starts on an empty one, so that
+		// the first option isn't chosen for it silently.
+		if cta != "" || !selectable(item.Value) {
+			opts = append(opts, &Element{
 				TagName: "option",
 				Attrs:   map[string]string{"disabled": "true", "selected": "true"},
-				Data:    "Select an option...",
+				Data:    cta,
 				ViewBuf: &bytes.Buffer{},
+			})
+		}
+
+		// provide a selection reset (will store empty string in db)
+		if !hideReset {
+			resetAttrs := map[string]string{"value": ""}
+			if cta == "" && item.Value == "" {
+				resetAttrs["selected"] = "true"
 			}
 
-			// provide a selection reset (will store empty string in db)
-			reset := &Element{
+			opts = append(opts, &Element{
 				TagName: "option",
-				Attrs:   map[string]string{"value": ""},
-				Data:    "None",
+				Attrs:   resetAttrs,
+				Data:    text("select.none"),
 				ViewBuf: &bytes.Buffer{},
-			}
+			})
+		}
 
-			opts = append(opts, cta, reset)
+		_, err := w.Write(domElementSelect(sel, func(buf *bytes.Buffer) error {
+			for _, opt := range opts {
+				_, err := buf.Write(DOMElement(opt))
+				if err != nil {
+					return err
+				}
+			}
 
-			for k, v := range options {
-				optAttrs := map[string]string{"value": k}
-				if k == val {
-					optAttrs["selected"] = "true"
+			for _, g := range groups {
+				if g.Label != "" {
+					_, err := buf.WriteString(`<optgroup label="` + html.EscapeString(g.Label) + `">`)
+					if err != nil {
+						return err
+					}
 				}
-				opt := &Element{
-					TagName: "option",
-					Attrs:   optAttrs,
-					Data:    v,
-					ViewBuf: &bytes.Buffer{},
+
+				for _, o := range g.Options {
+					_, err := buf.Write(DOMElement(option(o, item.Value)))
+					if err != nil {
+						return err
+					}
 				}
 
-				opts = append(opts, opt)
+				if g.Label != "" {
+					_, err := buf.WriteString(`</optgroup>`)
+					if err != nil {
+						return err
+					}
+				}
 			}
 
-			_, err := html.Write(DOMElementWithChildrenSelect(sel, opts))
-			if err != nil {
-				log.Println("Error writing DOMElementWithChildrenSelect to SelectRepeater buffer")
-				return nil
-			}
-		}
-	}
+			return nil
+		}))
+		return err
+	})
+}
 
-	_, err = html.WriteString(`</span>`)
+// FileRepeater returns the []byte of a <input type="file"> HTML element with a label.
+// It also includes repeat controllers (+ / -) so the element can be
+// dynamically multiplied or reduced.
+// attrs["accept"] limits the files which can be selected or dropped, like the
+// accept attribute of the input, e.g. "image/*,.pdf", and attrs["maxsize"] is
+// the largest file, in bytes, which can be uploaded. A file which isn't allowed
+// is cleared from the item, with an error shown in its place, before it is
+// ever uploaded. Clearing a stored file with its reset button submits an empty
+// value under the item's name, so that the file is removed from the field
+// when the content is saved.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func FileRepeater(fieldName string, p interface{}, attrs map[string]string) []byte {
+	view := &bytes.Buffer{}
+	err := FileRepeaterTo(view, fieldName, p, attrs)
 	if err != nil {
-		log.Println("Error writing HTML string to SelectRepeater buffer")
+		log.Println("Error writing HTML string to FileRepeater buffer")
 		return nil
 	}
 
-	return append(html.Bytes(), RepeatController(fieldName, p, "select", ".input-field")...)
+	return view.Bytes()
 }
 
-// FileRepeater returns the []byte of a <input type="file"> HTML element with a label.
-// It also includes repeat controllers (+ / -) so the element can be
-// dynamically multiplied or reduced.
+// FileRepeaterTo is like FileRepeater, but writes the element to w as it is
+// rendered, rather than returning it
 // IMPORTANT:
 // The `fieldName` argument will cause a panic if it is not exactly the string
 // form of the struct field that this editor input is representing
-func FileRepeater(fieldName string, p interface{}, attrs map[string]string) []byte {
+func FileRepeaterTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string) error {
+	checkAttrs("FileRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		_, err := w.Write(view)
+		return err
+	}
+
 	// find the field values in p to determine if an option is pre-selected
-	fieldVals := ValueFromStructField(fieldName, p)
-	vals := strings.Split(fieldVals, "__ponzu")
+	vals := ValuesFromStructField(fieldName, p)
 
-	addLabelFirst := func(i int, label string) string {
-		if i == 0 {
-			return `<label class="active">` + label + `</label>`
+	name := TagNameFromStructField(fieldName, p)
+
+	placeholder := attrs["label"]
+	if placeholder == "" {
+		placeholder = humanize(fieldName)
+	}
+	placeholder = htmlText("file.add", "name", placeholder)
+	upload := htmlText("file.upload")
+
+	var accept string
+	if attrs["accept"] != "" {
+		accept = ` accept="` + html.EscapeString(attrs["accept"]) + `"`
+	}
+
+	var maxSize int64
+	if attrs["maxsize"] != "" {
+		n, err := strconv.ParseInt(attrs["maxsize"], 10, 64)
+		if err != nil || n <= 0 {
+			logf("editor: invalid maxsize ignored", "field", fieldName, "maxsize", attrs["maxsize"])
+		} else {
+			maxSize = n
 		}
+	}
 
-		return ""
+	r := repeater{
+		class:  name,
+		values: vals,
+		script: fileRepeaterScript(name, attrs["accept"], maxSize),
+		input:  "input.upload",
+		clone:  "div.file-input." + selectorClass(fieldName),
 	}
 
-	tmpl :=
-		`<div class="file-input %[5]s %[4]s input-field col s12">
-			%[2]s
+	return renderRepeater(w, fieldName, p, attrs, r, func(w io.Writer, item repeatItem) error {
+		id := fieldID(item.Name)
+
+		// only the first item is labeled, even when the label is empty
+		var label string
+		if item.Index == 0 {
+			label = `<label class="active" for="` + id + `">` + item.Label + `</label>`
+		}
+
+		var invalid string
+		if item.Error != "" {
+			invalid = " invalid"
+		}
+
+		className := name + "-" + strconv.Itoa(item.Index)
+		return fileRepeaterItemTemplate.execute(w, item.Name, label, html.EscapeString(item.Value), className, fieldName, placeholder, upload, id, accept, invalid)
+	})
+}
+
+// fileRepeaterItemTemplate is the markup of each item of a FileRepeater, which
+// is parsed once rather than being formatted for every item
+var fileRepeaterItemTemplate = parseTemplate(`<div class="file-input {{field}} {{class}} input-field col s12">
+			{{label}}
 			<div class="file-field input-field">
 				<div class="btn">
-					<span>Upload</span>
-					<input class="upload %[4]s" type="file" />
+					<span>{{upload}}</span>
+					<input class="upload {{class}}" id="{{id}}" aria-label="{{placeholder}}" type="file"{{accept}} />
 				</div>
 				<div class="file-path-wrapper">
-					<input class="file-path validate" placeholder="Add %[5]s" type="text" />
+					<input class="file-path validate{{invalid}}" placeholder="{{placeholder}}" type="text" />
 				</div>
 			</div>
+			<span class="file-error red-text"></span>
 			<div class="preview"><div class="img-clip"></div></div>			
-			<input class="store %[4]s" type="hidden" name="%[1]s" value="%[3]s" />
-		</div>`
-		// 1=nameidx, 2=addLabelFirst, 3=val, 4=className, 5=fieldName
-	script :=
-		`<script>
-			$(function() {
-				var $file = $('.file-input.%[2]s'),
-					upload = $file.find('input.upload'),
-					store = $file.find('input.store'),
-					preview = $file.find('.preview'),
-					clip = preview.find('.img-clip'),
-					reset = document.createElement('div'),
-					img = document.createElement('img'),
-					video = document.createElement('video'),
-					unknown = document.createElement('div'),
-					viewLink = document.createElement('a'),
-					viewLinkText = document.createTextNode('Download / View '),
-					iconLaunch = document.createElement('i'),
-					iconLaunchText = document.createTextNode('launch'),
-					uploadSrc = store.val();
-					video.setAttribute
-					preview.hide();
-					viewLink.setAttribute('href', '%[3]s');
-					viewLink.setAttribute('target', '_blank');
-					viewLink.appendChild(viewLinkText);
-					viewLink.style.display = 'block';
-					viewLink.style.marginRight = '10px';					
-					viewLink.style.textAlign = 'right';
-					iconLaunch.className = 'material-icons tiny';
-					iconLaunch.style.position = 'relative';
-					iconLaunch.style.top = '3px';
-					iconLaunch.appendChild(iconLaunchText);
-					viewLink.appendChild(iconLaunch);
-					preview.append(viewLink);
-				
-				// when %[2]s input changes (file is selected), remove
-				// the 'name' and 'value' attrs from the hidden store input.
-				// add the 'name' attr to %[2]s input
-				upload.on('change', function(e) {
-					resetImage();
-				});
+			<input class="store {{class}}" type="hidden" name="{{name}}" value="{{value}}" />
+		</div>`, "name", "label", "value", "class", "field", "placeholder", "upload", "id", "accept", "invalid")
+
+// humanize returns the struct field name fieldName as words for display, e.g.
+// "ProfilePhoto" becomes "Profile Photo" and "OGImageURL" becomes "OG Image URL"
+func humanize(fieldName string) string {
+	runes := []rune(fieldName)
+	var words []rune
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := !unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				words = append(words, ' ')
+			}
+		}
 
-				if (uploadSrc.length > 0) {
-					var ext = uploadSrc.substring(uploadSrc.lastIndexOf('.'));
-					ext = ext.toLowerCase();
-					switch (ext) {
-						case '.jpg':
-						case '.jpeg':
-						case '.webp':
-						case '.gif':
-						case '.png':
-							$(img).attr('src', store.val());
-							clip.append(img);
-							break;
-						case '.mp4':
-						case '.webm':
-							$(video)
-								.attr('src', store.val())
-								.attr('type', 'video/'+ext.substring(1))
-								.attr('controls', true)
-								.css('width', '100%%');
-							clip.append(video);
-							break;
-						default:
-							$(img).attr('src', '/admin/static/dashboard/img/ponzu-file.png');
-							$(unknown)
-								.css({
-									position: 'absolute', 
-									top: '10px', 
-									left: '10px',
-									border: 'solid 1px #ddd',
-									padding: '7px 7px 5px 12px',
-									fontWeight: 'bold',
-									background: '#888',
-									color: '#fff',
-									textTransform: 'uppercase',
-									letterSpacing: '2px' 
-								})
-								.text(ext);
-							clip.append(img);
-							clip.append(unknown);
-							clip.css('maxWidth', '200px');
+		if r == '_' {
+			r = ' '
+		}
+		words = append(words, r)
+	}
+
+	return strings.Join(strings.Fields(string(words)), " ")
+}
+
+// fileRepeaterScript generates the javascript for every file input of the
+// FileRepeater scope, which previews the stored files, offers a reset button to
+// clear them, and submits the upload instead of the stored value once a new file
+// is selected, either with its button or by dropping it onto the item. It
+// handles items added by RepeatController too. Stored files are previewed by
+// the extension of their URL, as an image, a video or audio player, or else by
+// their name with a link to download them. Files which don't match accept, or
+// which are larger than maxSize bytes when it isn't zero, are rejected.
+func fileRepeaterScript(scope, accept string, maxSize int64) string {
+	return string(fileRepeaterScriptTemplate.render(selectorClass(scope), jsString(scope), jsString(accept),
+		strconv.FormatInt(maxSize, 10), jsString(text("file.tooLarge", "size", formatBytes(maxSize))),
+		jsString(text("file.notAccepted"))))
+}
+
+// formatBytes returns n bytes in the largest unit it reaches, e.g. "2.5 MB"
+func formatBytes(n int64) string {
+	units := []string{"bytes", "KB", "MB", "GB"}
+
+	size, unit := float64(n), 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+
+	return strings.TrimSuffix(strconv.FormatFloat(size, 'f', 1, 64), ".0") + " " + units[unit]
+}
+
+// fileRepeaterScriptTemplate is the script of fileRepeaterScript, which is
+// parsed once rather than being rebuilt for every FileRepeater
+var fileRepeaterScriptTemplate = parseTemplate(`
+		<script>
+			(function() {
+				var init = function() {
+					var scope = document.querySelector('.__ponzu-repeat.{{scope}}');
+					if (!scope) {
+						return;
+					}
+
+					var items = function() {
+						return Array.prototype.slice.call(scope.querySelectorAll('.file-input'));
+					}
+
+					// previewKinds are the elements which preview files by their
+					// extensions, and any other file is shown by its name
+					var previewKinds = {
+						jpg: 'img', jpeg: 'img', png: 'img', gif: 'img', webp: 'img', avif: 'img', svg: 'img', bmp: 'img', ico: 'img',
+						mp4: 'video', m4v: 'video', webm: 'video', ogv: 'video', mov: 'video',
+						mp3: 'audio', m4a: 'audio', aac: 'audio', wav: 'audio', oga: 'audio', ogg: 'audio', flac: 'audio', opus: 'audio'
+					};
+
+					// fileName returns the name of the file at url, without the
+					// url's query or fragment
+					var fileName = function(url) {
+						var path = url.split(/[?#]/)[0],
+							name = path.substring(path.lastIndexOf('/') + 1);
+
+						try {
+							return decodeURIComponent(name);
+						} catch (e) {
+							return name;
+						}
+					}
+
+					// resetImage clears the stored file of the item file. When a
+					// file is being uploaded in its place, its upload input
+					// submits under the item's current name instead. Otherwise
+					// the store input, marked as cleared, submits an empty value
+					// under that name, so that the stored file is removed when
+					// the item is saved rather than left as it was.
+					var resetImage = function(file, uploading) {
+						var upload = file.querySelector('input.upload'),
+							store = file.querySelector('input.store'),
+							clip = file.querySelector('.preview .img-clip'),
+							name = {{jsScope}} + '.' + String(items().indexOf(file));
+
+						store.value = '';
+						if (uploading) {
+							store.setAttribute('name', '');
+							store.removeAttribute('data-ponzu-cleared');
+							upload.setAttribute('name', name);
+						} else {
+							store.setAttribute('name', name);
+							store.setAttribute('data-ponzu-cleared', 'true');
+							upload.setAttribute('name', '');
+						}
+
+						if (clip) {
+							clip.innerHTML = '';
+							clip.classList.remove('audio');
+						}
+					}
+
+					// rejected returns why the selected file can't be uploaded,
+					// if it isn't accepted or is too large. Dropped files aren't
+					// filtered by the accept attribute, so it's checked here too.
+					var accept = {{accept}}, maxSize = {{maxSize}};
+					var rejected = function(selected) {
+						if (maxSize > 0 && selected.size > maxSize) {
+							return {{tooLarge}};
+						}
+
+						if (!accept) {
+							return '';
+						}
+
+						var name = selected.name.toLowerCase(),
+							type = (selected.type || '').toLowerCase();
+
+						var ok = accept.split(',').some(function(a) {
+							a = a.trim().toLowerCase();
+							if (a.charAt(0) === '.') {
+								return name.slice(-a.length) === a;
+							}
+
+							if (a.slice(-2) === '/*') {
+								return type.indexOf(a.slice(0, -1)) === 0;
+							}
+
+							return a !== '' && type === a;
+						});
+
+						return ok ? '' : {{notAccepted}};
 					}
-					preview.show();
 
-					$(reset).addClass('reset %[2]s btn waves-effect waves-light grey');
-					$(reset).html('<i class="material-icons tiny">clear<i>');
-					$(reset).on('click', function(e) {
+					// when an upload input changes (file is selected), remove the
+					// 'name' and 'value' attrs from the hidden store input, and
+					// add the 'name' attr to the upload input. A file which is
+					// rejected is cleared instead, keeping the stored file.
+					scope.addEventListener('change', function(e) {
+						if (!e.target.matches('input.upload')) {
+							return;
+						}
+
+						var file = e.target.closest('.file-input'),
+							error = file.querySelector('.file-error'),
+							selected = e.target.files && e.target.files[0],
+							msg = selected ? rejected(selected) : '';
+
+						if (error) {
+							error.textContent = msg;
+						}
+
+						if (msg) {
+							e.target.value = '';
+							file.querySelector('.file-path').value = '';
+							return;
+						}
+
+						resetImage(file, true);
+					});
+
+					scope.addEventListener('click', function(e) {
+						var reset = e.target.closest('.preview .reset');
+						if (!reset || !scope.contains(reset)) {
+							return;
+						}
+
+						e.preventDefault();
+
+						var file = reset.closest('.file-input'),
+							preview = file.querySelector('.preview');
+
+						preview.style.transition = 'opacity 0.2s ease';
+						preview.style.opacity = 0.1;
+						setTimeout(function() {
+							preview.style.display = 'none';
+							preview.style.opacity = '';
+							resetImage(file, false);
+						}, 250);
+					});
+
+					// files dragged onto an item are dropped into its upload
+					// input, as if chosen with its button. dropTarget returns
+					// the item files are dragged over, if any.
+					var dropTarget = function(e) {
+						var types = e.dataTransfer && e.dataTransfer.types;
+						if (!types || Array.prototype.indexOf.call(types, 'Files') === -1) {
+							return null;
+						}
+
+						var file = e.target.closest ? e.target.closest('.file-input') : null;
+						return file && scope.contains(file) ? file : null;
+					}
+
+					scope.addEventListener('dragover', function(e) {
+						var file = dropTarget(e);
+						if (!file) {
+							return;
+						}
+
+						e.preventDefault();
+						e.dataTransfer.dropEffect = 'copy';
+						file.classList.add('__ponzu-file-dragover');
+					});
+
+					scope.addEventListener('dragleave', function(e) {
+						var file = dropTarget(e);
+						if (file && !file.contains(e.relatedTarget)) {
+							file.classList.remove('__ponzu-file-dragover');
+						}
+					});
+
+					scope.addEventListener('drop', function(e) {
+						var file = dropTarget(e);
+						if (!file) {
+							return;
+						}
+
 						e.preventDefault();
-						preview.animate({"opacity": 0.1}, 200, function() {
-							preview.slideUp(250, function() {
-								resetImage();
-							});
-						})
-						
+						file.classList.remove('__ponzu-file-dragover');
+
+						var upload = file.querySelector('input.upload'),
+							files = e.dataTransfer.files;
+						if (!files || files.length === 0) {
+							return;
+						}
+
+						// the upload input holds a single file, and browsers
+						// which can't set its files keep using the button
+						try {
+							var list = new DataTransfer();
+							list.items.add(files[0]);
+							upload.files = list.files;
+						} catch (err) {
+							try {
+								upload.files = files;
+							} catch (err) {
+								return;
+							}
+						}
+
+						var change = document.createEvent('HTMLEvents');
+						change.initEvent('change', true, false);
+						upload.dispatchEvent(change);
+					});
+
+					items().forEach(function(file) {
+						var store = file.querySelector('input.store'),
+							preview = file.querySelector('.preview'),
+							clip = preview.querySelector('.img-clip'),
+							reset = document.createElement('div'),
+							viewLink = document.createElement('a'),
+							viewLinkText = document.createTextNode(` + jsString(text("file.view")) + `),
+							iconLaunch = document.createElement('i'),
+							iconLaunchText = document.createTextNode('launch'),
+							uploadSrc = store.value;
+
+						preview.style.display = 'none';
+						viewLink.setAttribute('href', uploadSrc);
+						viewLink.setAttribute('target', '_blank');
+						viewLink.appendChild(viewLinkText);
+						viewLink.style.display = 'block';
+						viewLink.style.marginRight = '10px';
+						viewLink.style.textAlign = 'right';
+						iconLaunch.className = 'material-icons tiny';
+						iconLaunch.style.position = 'relative';
+						iconLaunch.style.top = '3px';
+						iconLaunch.appendChild(iconLaunchText);
+						viewLink.appendChild(iconLaunch);
+						preview.appendChild(viewLink);
+
+						if (uploadSrc.length === 0) {
+							return;
+						}
+
+						var name = fileName(uploadSrc),
+							ext = name.lastIndexOf('.') === -1 ? '' : name.substring(name.lastIndexOf('.') + 1).toLowerCase();
+
+						switch (previewKinds[ext]) {
+							case 'img':
+								var img = document.createElement('img');
+								img.setAttribute('src', uploadSrc);
+								img.setAttribute('alt', name);
+								clip.appendChild(img);
+								break;
+							case 'video':
+							case 'audio':
+								var media = document.createElement(previewKinds[ext]);
+								media.setAttribute('src', uploadSrc);
+								media.setAttribute('controls', true);
+								media.setAttribute('preload', 'metadata');
+								media.style.width = '100%';
+								clip.appendChild(media);
+								clip.classList.toggle('audio', previewKinds[ext] === 'audio');
+								break;
+							default:
+								// other files are shown by their name, and
+								// opened or downloaded by the link
+								var file = document.createElement('div'),
+									icon = document.createElement('i'),
+									label = document.createElement('span');
+
+								file.className = '__ponzu-file-preview';
+								icon.className = 'material-icons';
+								icon.textContent = ext === 'pdf' ? 'picture_as_pdf' : 'insert_drive_file';
+								label.textContent = name;
+								file.appendChild(icon);
+								file.appendChild(label);
+								clip.appendChild(file);
+								viewLink.setAttribute('download', name);
+						}
+						preview.style.display = '';
+
+						reset.className = 'reset btn waves-effect waves-light grey';
+						reset.innerHTML = '<i class="material-icons tiny">clear</i>';
+						clip.appendChild(reset);
 					});
-					clip.append(reset);
 				}
 
-				function resetImage() {
-					store.val('');
-					store.attr('name', '');
-					upload.attr('name', '%[1]s');
-					clip.empty();
+				if (document.readyState === 'loading') {
+					document.addEventListener('DOMContentLoaded', init);
+				} else {
+					init();
 				}
-			});	
-		</script>`
-		// 1=nameidx, 2=className
+			})();
+		</script>`, "scope", "jsScope", "accept", "maxSize", "tooLarge", "notAccepted")
+
+// repeatItem is an item of a repeater rendered by renderRepeater
+type repeatItem struct {
+	Index int    // the position of the item among the repeater's items
+	Name  string // the indexed form name of the item, e.g. "names.0"
+	Label string // attrs["label"] for the first item, and empty for the others
+	Value string // the stored value of the item
+	Error string // the message of the item's error, see Rejected
+}
 
-	name := TagNameFromStructField(fieldName, p)
+// repeater is the scaffolding of a repeater rendered by renderRepeater
+type repeater struct {
+	class  string   // the classes of the container, including the field's scope
+	values []string // the values of the items
+	script string   // written after the container, e.g. to validate the items
+	input  string   // the selector of each item's input, see RepeatController
+	clone  string   // the selector of the items cloned by RepeatController
+}
 
-	html := bytes.Buffer{}
-	_, err := html.WriteString(`<span class="__ponzu-repeat ` + name + `">`)
+// renderRepeater writes the repeater of fieldName to w: the container opened
+// by repeatOpen, an item rendered by item for each of r.values, named by its
+// index and labeled only when it is the first, the close of the container,
+// r.script and the RepeatController of the items. When p is Rejected, the
+// message of an item's error is placed within the container of the item, which
+// its markup ends by closing, while the field's other errors follow the close
+// of the repeater's container.
+func renderRepeater(w io.Writer, fieldName string, p interface{}, attrs map[string]string, r repeater, item func(w io.Writer, item repeatItem) error) error {
+	_, err := io.WriteString(w, repeatOpen(r.class, attrs))
 	if err != nil {
-		log.Println("Error writing HTML string to FileRepeater buffer")
-		return nil
+		return err
 	}
 
-	for i, val := range vals {
-		className := fmt.Sprintf("%s-%d", name, i)
-		nameidx := TagNameFromStructFieldMulti(fieldName, i, p)
+	itemErrors, fieldErrors := repeatErrors(p, TagNameFromStructField(fieldName, p), len(r.values))
+
+	// an item with an error is rendered into buf, to place its message
+	buf := &bytes.Buffer{}
+	for i, val := range r.values {
+		it := repeatItem{
+			Index: i,
+			Name:  TagNameFromStructFieldMulti(fieldName, i, p),
+			Value: val,
+			Error: itemErrors[i],
+		}
+
+		// only add the label to the first item in repeated list
+		if i == 0 {
+			it.Label = attrs["label"]
+		}
 
-		_, err := html.WriteString(fmt.Sprintf(tmpl, nameidx, addLabelFirst(i, attrs["label"]), val, className, fieldName))
+		if it.Error == "" {
+			err = item(w, it)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		buf.Reset()
+		err = item(buf, it)
 		if err != nil {
-			log.Println("Error writing HTML string to FileRepeater buffer")
-			return nil
+			return err
 		}
 
-		_, err = html.WriteString(fmt.Sprintf(script, nameidx, className, val))
+		markup := buf.Bytes()
+		end := bytes.LastIndex(markup, []byte("</div>"))
+		if end == -1 {
+			end = len(markup)
+		}
+
+		_, err = w.Write(append(append(markup[:end:end], fieldError(it.Error)...), markup[end:]...))
 		if err != nil {
-			log.Println("Error writing HTML string to FileRepeater buffer")
-			return nil
+			return err
 		}
 	}
-	_, err = html.WriteString(`</span>`)
+
+	_, err = io.WriteString(w, repeatClose(attrs))
 	if err != nil {
-		log.Println("Error writing HTML string to FileRepeater buffer")
-		return nil
+		return err
+	}
+
+	for _, msg := range fieldErrors {
+		_, err = io.WriteString(w, fieldError(msg))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, r.script)
+	if err != nil {
+		return err
+	}
+
+	return writeRepeatController(w, fieldName, p, r.input, r.clone, repeatControllerOptions(attrs))
+}
+
+// repeatErrors returns the messages of the ValidationErrors of p, when it is
+// Rejected, for the repeater named name holding n items: those of its items by
+// their index, and those of the field itself, or of items it doesn't hold, in
+// order of the names they were given
+func repeatErrors(p interface{}, name string, n int) (map[int]string, []string) {
+	rejected, ok := p.(Rejected)
+	if !ok {
+		return nil, nil
+	}
+
+	errs := rejected.ValidationErrors()
+	keys := make([]string, 0, len(errs))
+	for k := range errs {
+		if k == name || strings.HasPrefix(k, name+".") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	items := make(map[int]string)
+	var field []string
+	for _, k := range keys {
+		i, err := strconv.Atoi(strings.TrimPrefix(k, name+"."))
+		if k != name && err == nil && i >= 0 && i < n {
+			items[i] = errs[k]
+			continue
+		}
+
+		field = append(field, errs[k])
+	}
+
+	return items, field
+}
+
+// invalidAttrs returns a copy of attrs whose class marks the element invalid
+// when invalid is true, and otherwise attrs itself
+func invalidAttrs(attrs map[string]string, invalid bool) map[string]string {
+	if !invalid {
+		return attrs
+	}
+
+	marked := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		marked[k] = v
+	}
+
+	if marked["class"] != "" {
+		marked["class"] += " invalid"
+	} else {
+		marked["class"] = "invalid"
+	}
+
+	return marked
+}
+
+// repeatOpen returns the opening tag of a repeater's container, with the
+// classes in class. When attrs["numbered"] is "true", the items are displayed
+// as a numbered list, which follows their order as they are added, removed
+// and reordered. When attrs["sortable"] is "true", the items can be reordered by
+// dragging them by their handles. attrs["wrapperClass"] adds classes to the
+// container, and attrs["wrapperId"] sets its id, as hooks for styles and
+// scripts targeting the repeater as a whole.
+func repeatOpen(class string, attrs map[string]string) string {
+	if attrs["wrapperClass"] != "" {
+		class += " " + html.EscapeString(attrs["wrapperClass"])
+	}
+
+	var id string
+	if attrs["wrapperId"] != "" {
+		id = ` id="` + html.EscapeString(attrs["wrapperId"]) + `"`
+	}
+
+	if attrs["numbered"] == "true" {
+		return `<span class="__ponzu-repeat __ponzu-repeat-numbered ` + class + `"` + id + ` role="list"` +
+			repeatLimits(attrs) + repeatSortable(attrs) + repeatUnique(attrs) + repeatRequired(attrs) + `>`
+	}
+
+	return `<span class="__ponzu-repeat ` + class + `"` + id + repeatLimits(attrs) + repeatSortable(attrs) + repeatUnique(attrs) + repeatRequired(attrs) + `>`
+}
+
+// repeatClose returns the closing tag of a repeater's container opened by
+// repeatOpen, followed by the help text of attrs, which is shown once under
+// the whole repeater rather than under each item, and the script which
+// requires its first item when attrs["required"] is set
+func repeatClose(attrs map[string]string) string {
+	if isRequired(attrs) {
+		return `</span>` + helpText(attrs) + requiredScript()
+	}
+
+	return `</span>` + helpText(attrs)
+}
+
+// repeatOnlyAttrs are the attrs applied to a repeater as a whole by repeatOpen
+// and repeatClose, rather than to each of its items
+var repeatOnlyAttrs = []string{"help", "required", "wrapperClass", "wrapperId"}
+
+// repeatItemAttrs returns the attrs of each item of a repeater, which are attrs
+// without the repeatOnlyAttrs. "required" is among them since the repeater
+// itself requires only its first item, see repeatRequired.
+func repeatItemAttrs(attrs map[string]string) map[string]string {
+	var found bool
+	for _, k := range repeatOnlyAttrs {
+		if _, ok := attrs[k]; ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return attrs
+	}
+
+	item := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		if !hasString(repeatOnlyAttrs, k) {
+			item[k] = v
+		}
+	}
+
+	return item
+}
+
+// repeatSortable returns the data attribute which allows the items of a
+// repeater to be reordered by dragging, when attrs["sortable"] is "true"
+func repeatSortable(attrs map[string]string) string {
+	if attrs["sortable"] == "true" {
+		return ` data-sortable="true"`
+	}
+
+	return ""
+}
+
+// repeatLimits returns the data attributes of a repeater's limits on its number
+// of items, which are read by RepeatController. attrs["minItems"] sets the
+// fewest items the repeater can hold, which is at least one, and
+// attrs["maxItems"] sets the most. A minimum above the maximum is lowered to it.
+func repeatLimits(attrs map[string]string) string {
+	min, _ := strconv.Atoi(attrs["minItems"])
+	max, _ := strconv.Atoi(attrs["maxItems"])
+	if max > 0 && min > max {
+		min = max
+	}
+
+	var limits string
+	if min > 1 {
+		limits += fmt.Sprintf(` data-min-items="%d"`, min)
 	}
 
-	return append(html.Bytes(), RepeatController(fieldName, p, "input.upload", "div.file-input."+fieldName)...)
+	if max > 0 {
+		limits += fmt.Sprintf(` data-max-items="%d"`, max)
+	}
+
+	return limits
 }
 
 // RepeatController generates the javascript to control any repeatable form
-// element in an editor based on its type, field name and HTML tag name
+// element in an editor based on its type, field name and HTML tag name.
+// Items made of several inputs mark each with a data-ponzu-key attribute, which
+// is appended to the item's indexed name, e.g. "links.0.url".
+// Inputs which only display the item, and are never submitted, are marked with
+// a data-ponzu-display attribute. Each item added is announced by a bubbling
+// "ponzu-repeat-add" event dispatched on it, so that scripts can set it up.
 func RepeatController(fieldName string, p interface{}, inputSelector, cloneSelector string) []byte {
+	return RepeatControllerWithOptions(fieldName, p, inputSelector, cloneSelector, RepeatControllerOptions{})
+}
+
+// RepeatControllerOptions configures the controls added to each item of a
+// repeater by RepeatControllerWithOptions. The zero value renders the same
+// + / - controls as RepeatController.
+type RepeatControllerOptions struct {
+	// AddLabel and DelLabel are the text of the controls which add and remove
+	// an item, and default to "+" and "-"
+	AddLabel string
+	DelLabel string
+
+	// AddIcon and DelIcon are the names of Material icons, e.g. "add_circle"
+	// and "remove_circle", shown in place of the text of the controls. The
+	// text is then kept as the control's title and aria-label.
+	AddIcon string
+	DelIcon string
+
+	// Collapsible wraps the items in a container which is collapsed and
+	// expanded by clicking its header, which shows the Title and the count of
+	// items, e.g. "Images (12)". The container starts collapsed when there are
+	// more than CollapseAbove items, or DefaultCollapseAbove when it is 0, and
+	// is expanded whenever one of its items is invalid.
+	Collapsible   bool
+	CollapseAbove int
+
+	// Title is shown in the header of a Collapsible repeater, and defaults to
+	// the field name, humanized
+	Title string
+}
+
+// DefaultCollapseAbove is the number of items above which a Collapsible
+// repeater starts collapsed, unless it sets CollapseAbove
+const DefaultCollapseAbove = 5
+
+// RepeatControllerWithOptions is like RepeatController, but renders the
+// controls of each item as configured by opts. The controls keep their
+// repeater-add and repeater-del classes however they are labeled.
+func RepeatControllerWithOptions(fieldName string, p interface{}, inputSelector, cloneSelector string, opts RepeatControllerOptions) []byte {
+	return repeatControllerTemplate.render(repeatControllerValues(fieldName, p, inputSelector, cloneSelector, opts)...)
+}
+
+// writeRepeatController writes the script of RepeatControllerWithOptions to w
+func writeRepeatController(w io.Writer, fieldName string, p interface{}, inputSelector, cloneSelector string, opts RepeatControllerOptions) error {
+	return repeatControllerTemplate.execute(w, repeatControllerValues(fieldName, p, inputSelector, cloneSelector, opts)...)
+}
+
+// repeatControllerValues returns the values of the repeatControllerTemplate of
+// a RepeatController
+func repeatControllerValues(fieldName string, p interface{}, inputSelector, cloneSelector string, opts RepeatControllerOptions) []string {
 	scope := TagNameFromStructField(fieldName, p)
-	script := `
+	if strings.ContainsAny(scope, " \t\n\r\f") {
+		logf("editor: repeater name must not contain whitespace, since it is used as a class", "field", fieldName, "name", scope)
+	}
+
+	if opts.AddLabel == "" {
+		opts.AddLabel = "+"
+	}
+
+	if opts.DelLabel == "" {
+		opts.DelLabel = "-"
+	}
+
+	// a repeater which isn't collapsible is never collapsed
+	collapse := -1
+	if opts.Collapsible {
+		collapse = opts.CollapseAbove
+		if collapse <= 0 {
+			collapse = DefaultCollapseAbove
+		}
+	}
+
+	if opts.Title == "" {
+		opts.Title = humanize(fieldName)
+	}
+
+	return []string{selectorClass(scope), inputSelector, cloneSelector,
+		jsString(text("repeat.drag")), jsString(text("repeat.max")), jsString(text("repeat.min")),
+		jsString(opts.AddLabel), jsString(opts.DelLabel), jsString(opts.AddIcon), jsString(opts.DelIcon),
+		fieldID(scope) + "-", jsString(scope), strconv.Itoa(collapse), jsString(opts.Title)}
+}
+
+// repeatControllerOptions returns the RepeatControllerOptions set by the
+// "addLabel", "delLabel", "addIcon" and "delIcon" attrs of a repeater, and by
+// "collapsible" and "collapseAbove", with the repeater's label as the Title
+func repeatControllerOptions(attrs map[string]string) RepeatControllerOptions {
+	above, _ := strconv.Atoi(attrs["collapseAbove"])
+
+	return RepeatControllerOptions{
+		AddLabel:      attrs["addLabel"],
+		DelLabel:      attrs["delLabel"],
+		AddIcon:       attrs["addIcon"],
+		DelIcon:       attrs["delIcon"],
+		Collapsible:   attrs["collapsible"] == "true",
+		CollapseAbove: above,
+		Title:         attrs["label"],
+	}
+}
+
+// repeatControllerTemplate is the script of RepeatController, which is parsed
+// once rather than being rebuilt for every repeater
+var repeatControllerTemplate = parseTemplate(`
     <script>
-        $(function() {
-            // define the scope of the repeater
-            var scope = $('.__ponzu-repeat.` + scope + `');
+        (function() {
+            // each calls fn with every element of the list
+            var each = function(list, fn) {
+                Array.prototype.forEach.call(list, fn);
+            }
 
-            var getChildren = function() {
-                return scope.find('` + cloneSelector + `')
+            var remove = function(el) {
+                if (el.parentNode) {
+                    el.parentNode.removeChild(el);
+                }
             }
 
-            var resetFieldNames = function() {
-                // loop through children, set its name to the fieldName.i where
-                // i is the current index number of children array
-                var children = getChildren();
-
-                for (var i = 0; i < children.length; i++) {
-					var preset = false;					
-                    var $el = children.eq(i);
-					var name = '` + scope + `.'+String(i);
-
-                    $el.find('` + inputSelector + `').attr('name', name);
-
-					// ensure no other input-like elements besides ` + inputSelector + `
-					// get the new name by setting it to an empty string
-					$el.find('input, select, textarea').each(function(i, elem) {
-						var $elem = $(elem);
-						
-						// if the elem is not ` + inputSelector + ` and has no value 
-						// set the name to an empty string
-						if (!$elem.is('` + inputSelector + `')) {
-							if ($elem.val() === '' || $elem.is('.file-path')) {
-								$elem.attr('name', '');
-							} else {
-								$elem.attr('name', name);
-								preset = true;
-							}						
-						}
-					});      
+            var init = function() {
+                // define the scope of the repeater
+                var scope = document.querySelector('.__ponzu-repeat.{{scope}}');
+                if (!scope) {
+                    return;
+                }
 
-					// if there is a preset value, remove the name attr from the
-					// ` + inputSelector + ` element so it doesn't overwrite db
-					if (preset) {
-						$el.find('` + inputSelector + `').attr('name', '');														
-					}          
+                // the fewest items allowed, the most items allowed if limited,
+                // and the "X / Y" count of them
+                var min = Math.max(parseInt(scope.getAttribute('data-min-items'), 10) || 1, 1);
+                var max = parseInt(scope.getAttribute('data-max-items'), 10) || 0;
+                var counter = document.createElement('span');
+                counter.className = '__ponzu-repeat-count grey-text';
+                if (max > 0) {
+                    scope.parentNode.insertBefore(counter, scope.nextSibling);
+                }
 
-                    // reset controllers
-                    $el.find('.controls').remove();
+                var getChildren = function() {
+                    return Array.prototype.slice.call(scope.querySelectorAll('{{clone}}'));
                 }
 
-                applyRepeatControllers();
-            }
+                var resetFieldNames = function() {
+                    // loop through children, set its name to the fieldName.i
+                    // where i is the current index number of children array
+                    var children = getChildren();
+
+                    for (var i = 0; i < children.length; i++) {
+                        var preset = false;
+                        var el = children[i];
+                        var name = {{jsScope}} + '.' + String(i);
+
+                        // inputs with a data-ponzu-key are one part of the
+                        // item, and are named fieldName.i.key
+                        each(el.querySelectorAll('{{input}}'), function(input) {
+                            var key = input.getAttribute('data-ponzu-key');
+                            input.setAttribute('name', key ? name + '.' + key : name);
+                        });
+
+                        // ensure no other input-like elements besides
+                        // {{input}} get the new name by setting it
+                        // to an empty string
+                        each(el.querySelectorAll('input, select, textarea'), function(elem) {
+                            // if the elem is not {{input}} and has no
+                            // value set the name to an empty string, unless
+                            // it was cleared on purpose, so that its empty
+                            // value is submitted to clear the stored one
+                            if (!elem.matches('{{input}}')) {
+                                var cleared = elem.hasAttribute('data-ponzu-cleared');
+                                if ((elem.value === '' && !cleared) || elem.matches('.file-path, [data-ponzu-display]')) {
+                                    elem.setAttribute('name', '');
+                                } else {
+                                    elem.setAttribute('name', name);
+                                    preset = true;
+                                }
+                            }
+                        });
+
+                        // if there is a preset value, remove the name attr from
+                        // the {{input}} element so it doesn't
+                        // overwrite db
+                        if (preset) {
+                            each(el.querySelectorAll('{{input}}'), function(input) {
+                                input.setAttribute('name', '');
+                            });
+                        }
+
+                        // renumber the ids derived from the item's names, and
+                        // the for of its label, so they stay unique
+                        each(el.querySelectorAll('[id^="{{id}}"], label[for^="{{id}}"]'), function(elem) {
+                            each(['id', 'for'], function(attr) {
+                                var id = elem.getAttribute(attr);
+                                if (id && id.indexOf('{{id}}') === 0) {
+                                    elem.setAttribute(attr, '{{id}}' + i + id.slice('{{id}}'.length).replace(/^\d+/, ''));
+                                }
+                            });
+                        });
+
+                        // mark the item so that it can be numbered
+                        el.classList.add('__ponzu-repeat-item');
+                        if (scope.classList.contains('__ponzu-repeat-numbered')) {
+                            el.setAttribute('role', 'listitem');
+                        }
+
+                        // reset controllers
+                        each(el.querySelectorAll('.controls'), remove);
+                    }
+
+                    applyRepeatControllers();
+                }
 
-            var addRepeater = function(e) {
-                e.preventDefault();
-                
-                var add = e.target;
+                var addRepeater = function(e) {
+                    e.preventDefault();
 
-                // find and clone the repeatable input-like element
-                var source = $(add).parent().closest('` + cloneSelector + `');
-                var clone = source.clone();
+                    if (max > 0 && getChildren().length >= max) {
+                        return;
+                    }
 
-                // if clone has label, remove it
-                clone.find('label').remove();
-                
-                // remove the pre-filled value from clone
-                clone.find('` + inputSelector + `').val('');
-				clone.find('input').val('');
+                    // find and clone the repeatable input-like element
+                    var source = e.currentTarget.parentNode.closest('{{clone}}');
 
-                // remove controls from clone if already present
-                clone.find('.controls').remove();
+                    // add clone to scope and reset field name attributes
+                    var clone = cloneChild(source);
+                    scope.appendChild(clone);
 
-				// remove input preview on clone if copied from source
-				clone.find('.preview').remove();
+                    resetFieldNames();
 
-                // add clone to scope and reset field name attributes
-                scope.append(clone);
+                    // announce the clone, so that scripts can set up its inputs
+                    var added = document.createEvent('HTMLEvents');
+                    added.initEvent('ponzu-repeat-add', true, false);
+                    clone.dispatchEvent(added);
+                }
 
-                resetFieldNames();
-            }
+                // cloneChild returns an empty copy of the repeatable element
+                // source. cloneNode doesn't copy event listeners, so the clone's
+                // controls are recreated and bound by applyRepeatControllers.
+                var cloneChild = function(source) {
+                    var clone = source.cloneNode(true);
 
-            var delRepeater = function(e) {
-                e.preventDefault();
+                    // if clone has label, remove it
+                    each(clone.querySelectorAll('label'), remove);
 
-                // do nothing if the input is the only child
-                var children = getChildren();
-                if (children.length === 1) {
-                    return;
+                    // remove the pre-filled value from clone
+                    each(clone.querySelectorAll('{{input}}, input'), function(input) {
+                        input.value = '';
+                    });
+
+                    // reset the pickers which display it to their first option
+                    each(clone.querySelectorAll('select[data-ponzu-display]'), function(select) {
+                        select.selectedIndex = 0;
+                    });
+
+                    // a new item has nothing stored to clear
+                    each(clone.querySelectorAll('[data-ponzu-cleared]'), function(input) {
+                        input.removeAttribute('data-ponzu-cleared');
+                    });
+
+                    // nor an error of the source to show
+                    each(clone.querySelectorAll('.__ponzu-field-error'), remove);
+                    each(clone.querySelectorAll('.invalid'), function(el) {
+                        el.classList.remove('invalid');
+                    });
+
+                    // remove controls from clone if already present
+                    each(clone.querySelectorAll('.controls'), remove);
+
+                    // remove input preview on clone if copied from source
+                    each(clone.querySelectorAll('.preview'), remove);
+
+                    return clone;
+                }
+
+                var delRepeater = function(e) {
+                    e.preventDefault();
+
+                    // do nothing if the repeater is at its fewest items allowed
+                    var children = getChildren();
+                    if (children.length <= min) {
+                        return;
+                    }
+
+                    // pass label onto next input-like element if del 0 index
+                    var wrapper = e.currentTarget.parentNode.closest('{{clone}}');
+                    var label = wrapper.querySelector('label');
+                    var next = wrapper.nextElementSibling;
+                    if (children.indexOf(wrapper) === 0 && label && next) {
+                        next.insertBefore(label, next.firstChild);
+                    }
+
+                    remove(wrapper);
+
+                    resetFieldNames();
                 }
 
-                var del = e.target;
-                
-                // pass label onto next input-like element if del 0 index
-                var wrapper = $(del).parent().closest('` + cloneSelector + `');
-                if (wrapper.find('` + inputSelector + `').attr('name') === '` + scope + `.0') {
-                    wrapper.next().append(wrapper.find('label'))
+                // control returns a button labeled by text, or by the Material
+                // icon named icon when set, keeping text as its aria-label
+                var control = function(text, icon, className) {
+                    var button = document.createElement('button');
+                    button.className = className;
+                    button.textContent = text;
+
+                    if (icon) {
+                        var i = document.createElement('i');
+                        i.className = 'material-icons';
+                        i.textContent = icon;
+
+                        button.textContent = '';
+                        button.setAttribute('aria-label', text);
+                        button.appendChild(i);
+                    }
+
+                    return button;
                 }
-                
-                wrapper.remove();
 
-                resetFieldNames();
-            }
+                var createControls = function() {
+                    // create + / - controls for each input-like child element
+                    var add = control({{addLabel}}, {{addIcon}}, 'repeater-add btn-flat waves-effect waves-green');
+                    var del = control({{delLabel}}, {{delIcon}}, 'repeater-del btn-flat waves-effect waves-red');
 
-            var createControls = function() {
-                // create + / - controls for each input-like child element of scope
-                var add = $('<button>+</button>');
-                add.addClass('repeater-add');
-                add.addClass('btn-flat waves-effect waves-green');
+                    var controls = document.createElement('span');
+                    controls.className = 'controls right';
 
-                var del = $('<button>-</button>');
-                del.addClass('repeater-del');
-                del.addClass('btn-flat waves-effect waves-red');                
+                    // bind listeners to child's controls
+                    add.addEventListener('click', addRepeater);
+                    del.addEventListener('click', delRepeater);
 
-                var controls = $('<span></span>');
-                controls.addClass('controls');
-                controls.addClass('right');
+                    if (sortable) {
+                        var handle = document.createElement('i');
+                        handle.className = 'material-icons __ponzu-drag-handle';
+                        handle.title = {{drag}};
+                        handle.textContent = 'drag_handle';
+                        controls.appendChild(handle);
+                    }
 
-                // bind listeners to child's controls
-                add.on('click', addRepeater);
-                del.on('click', delRepeater);
+                    controls.appendChild(add);
+                    controls.appendChild(del);
 
-                controls.append(add);
-                controls.append(del);
+                    return controls;
+                }
 
-                return controls;
-            }
+                // when sortable, children can be dragged by their handle to
+                // reorder them, and are renamed to their new positions once
+                // dropped
+                var sortable = scope.hasAttribute('data-sortable'),
+                    dragging = null;
 
-            var applyRepeatControllers = function() {
-                // add controls to each child
-                var children = getChildren()
-                for (var i = 0; i < children.length; i++) {
-                    var el = children[i];
-                    
-                    $(el).find('` + inputSelector + `').parent().find('.controls').remove();
-                    
-                    var controls = createControls();                                        
-                    $(el).append(controls);
+                // child returns the child of the scope which holds target
+                var child = function(target) {
+                    var el = target.closest ? target.closest('{{clone}}') : null;
+                    return el && scope.contains(el) ? el : null;
                 }
-            }
 
-			resetFieldNames();
-        });
+                if (sortable) {
+                    // only make a child draggable while its handle is held, so
+                    // that its inputs still work normally
+                    scope.addEventListener('mousedown', function(e) {
+                        if (e.target.closest('.__ponzu-drag-handle') && child(e.target)) {
+                            child(e.target).setAttribute('draggable', 'true');
+                        }
+                    });
+
+                    scope.addEventListener('dragstart', function(e) {
+                        if (!child(e.target)) {
+                            return;
+                        }
+
+                        dragging = child(e.target);
+                        e.dataTransfer.effectAllowed = 'move';
+                        e.dataTransfer.setData('text/plain', '');
+                    });
+
+                    scope.addEventListener('dragover', function(e) {
+                        var over = child(e.target);
+                        if (!over) {
+                            return;
+                        }
+
+                        e.preventDefault();
+                        if (!dragging || dragging.contains(over)) {
+                            return;
+                        }
+
+                        var rect = over.getBoundingClientRect();
+                        if (e.clientY - rect.top > rect.height / 2) {
+                            over.parentNode.insertBefore(dragging, over.nextSibling);
+                        } else {
+                            over.parentNode.insertBefore(dragging, over);
+                        }
+                    });
+
+                    var drop = function(e) {
+                        if (!child(e.target)) {
+                            return;
+                        }
+
+                        e.preventDefault();
+                        if (!dragging) {
+                            return;
+                        }
+
+                        dragging.removeAttribute('draggable');
+                        dragging = null;
+
+                        // keep the label on the first child
+                        var children = getChildren(), label = null;
+                        for (var i = 0; i < children.length && !label; i++) {
+                            label = children[i].querySelector('label');
+                        }
+
+                        if (label && children.indexOf(child(label)) !== 0) {
+                            children[0].insertBefore(label, children[0].firstChild);
+                        }
+
+                        resetFieldNames();
+                    }
+
+                    scope.addEventListener('drop', drop);
+                    scope.addEventListener('dragend', drop);
+                }
 
-    </script>
-    `
+                var applyRepeatControllers = function() {
+                    // add controls to each child
+                    var children = getChildren();
+                    for (var i = 0; i < children.length; i++) {
+                        var el = children[i];
 
-	return []byte(script)
-}
+                        each(el.querySelectorAll('{{input}}'), function(input) {
+                            each(input.parentNode.querySelectorAll('.controls'), remove);
+                        });
+
+                        el.appendChild(createControls());
+                    }
+
+                    updateLimits();
+                }
+
+                // updateLimits disables the + and - controls at the most and
+                // fewest items allowed, and updates the count of items
+                var updateLimits = function() {
+                    var n = getChildren().length,
+                        full = max > 0 && n >= max,
+                        fewest = n <= min;
+
+                    each(scope.querySelectorAll('.repeater-add'), function(add) {
+                        add.disabled = full;
+                        add.title = full ? {{max}}.replace('{n}', max) : add.getAttribute('aria-label') || '';
+                    });
+
+                    each(scope.querySelectorAll('.repeater-del'), function(del) {
+                        del.disabled = fewest;
+                        del.title = fewest && min > 1 ? {{min}}.replace('{n}', min) : del.getAttribute('aria-label') || '';
+                    });
+
+                    if (max > 0) {
+                        counter.textContent = n + ' / ' + max;
+                    }
+
+                    if (badge) {
+                        badge.textContent = '(' + n + ')';
+                    }
+                }
+
+                // when collapsible, the scope is wrapped in a container with a
+                // header which collapses and expands it, showing the count of
+                // items, and which starts collapsed above the threshold
+                var collapse = {{collapse}},
+                    badge = null;
+
+                if (collapse >= 0) {
+                    var box = document.createElement('div');
+                    box.className = '__ponzu-repeat-collapse';
+
+                    var toggle = document.createElement('button');
+                    toggle.type = 'button';
+                    toggle.className = '__ponzu-repeat-toggle btn-flat';
+
+                    var title = document.createElement('span');
+                    title.textContent = {{title}} + ' ';
+
+                    badge = document.createElement('span');
+                    badge.className = '__ponzu-repeat-badge';
+
+                    var arrow = document.createElement('i');
+                    arrow.className = 'material-icons';
+
+                    toggle.appendChild(arrow);
+                    toggle.appendChild(title);
+                    toggle.appendChild(badge);
+
+                    scope.parentNode.insertBefore(box, scope);
+                    box.appendChild(toggle);
+                    box.appendChild(scope);
+
+                    var setCollapsed = function(collapsed) {
+                        scope.hidden = collapsed;
+                        toggle.setAttribute('aria-expanded', String(!collapsed));
+                        arrow.textContent = collapsed ? 'expand_more' : 'expand_less';
+                    }
+
+                    toggle.addEventListener('click', function(e) {
+                        e.preventDefault();
+                        setCollapsed(!scope.hidden);
+                    });
+
+                    var expandInvalid = function() {
+                        if (scope.hidden && scope.querySelector('.invalid')) {
+                            setCollapsed(false);
+                        }
+                    }
+
+                    // expand the items once a submit flags one as invalid,
+                    // after every other submit handler has run, or once the
+                    // errors of a background save are shown
+                    document.addEventListener('submit', function() {
+                        setTimeout(expandInvalid, 0);
+                    }, true);
+                    document.addEventListener('ponzu:invalid', expandInvalid);
+
+                    setCollapsed(getChildren().length > collapse && !scope.querySelector('.invalid'));
+                }
+
+                // start with at least the fewest items allowed
+                while (getChildren().length > 0 && getChildren().length < min) {
+                    var children = getChildren();
+                    scope.appendChild(cloneChild(children[children.length - 1]));
+                }
+
+                resetFieldNames();
+            }
+
+            if (document.readyState === 'loading') {
+                document.addEventListener('DOMContentLoaded', init);
+            } else {
+                init();
+            }
+        })();
+    </script>
+    `, "scope", "input", "clone", "drag", "max", "min",
+	"addLabel", "delLabel", "addIcon", "delIcon", "id", "jsScope", "collapse", "title")
@@ -0,0 +1,126 @@
+package editor
+
+// isRequired reports whether attrs["required"] requires a value of the field
+func isRequired(attrs map[string]string) bool {
+	v, ok := attrs["required"]
+	return ok && boolAttr(v)
+}
+
+// requiredClass returns the class which marks the label of a required field
+// with an asterisk
+func requiredClass(attrs map[string]string) string {
+	if isRequired(attrs) {
+		return " __ponzu-required"
+	}
+
+	return ""
+}
+
+// repeatRequired returns the data attribute which requires the first item of a
+// repeater to have a value, when attrs["required"] is set. The items of a
+// repeater are not required themselves, since only one value is needed.
+func repeatRequired(attrs map[string]string) string {
+	if isRequired(attrs) {
+		return ` data-required="true"`
+	}
+
+	return ""
+}
+
+// requiredScript returns the script which stops the forms of the page from
+// being submitted while any of their required fields is empty, highlighting
+// them instead. Fields hidden by ShowWhen are not submitted, so they are not
+// required while they are hidden.
+func requiredScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuRequired) {
+			return;
+		}
+		window.__ponzuRequired = true;
+
+		var message = ` + jsString(text("field.required")) + `;
+
+		// submitted reports whether el is submitted with its form, which a
+		// disabled field or one hidden by ShowWhen is not
+		var submitted = function(el) {
+			return !!el.getAttribute('name') && !el.disabled &&
+				$(el).parents('.__ponzu-show-when').filter(':hidden').length === 0;
+		}
+
+		var empty = function(el) {
+			if (el.type === 'checkbox' || el.type === 'radio') {
+				return $(el.form).find('[name="' + el.name.replace(/["\\]/g, '\\$&') + '"]:checked').length === 0;
+			}
+
+			return $.trim($(el).val() || '') === '';
+		}
+
+		// firstItems requires the first named field of each required repeater
+		// of form, in whatever order its items now are, and returns them
+		var firstItems = function(form) {
+			return $(form).find('.__ponzu-repeat[data-required]').map(function() {
+				var items = $(this).find('[name]').prop('required', false);
+				return items.first().prop('required', true).get(0) || null;
+			});
+		}
+
+		// check flags the empty required fields of form, and returns the first
+		var check = function(form) {
+			var missing = null;
+
+			firstItems(form);
+			$(form).find('[required]').each(function(i, el) {
+				var invalid = submitted(el) && empty(el);
+
+				if (typeof el.setCustomValidity === 'function') {
+					el.setCustomValidity(invalid ? message : '');
+				}
+				$(el).toggleClass('invalid', invalid);
+				$(el).closest('.input-field, p').toggleClass('__ponzu-required-missing', invalid);
+
+				missing = missing || (invalid ? el : null);
+			});
+
+			return missing;
+		}
+
+		$('form').on('submit', function(e) {
+			var form = $(this),
+				missing = check(this);
+
+			form.data('ponzuRequired', true);
+			if (!missing) {
+				return;
+			}
+
+			e.preventDefault();
+			e.stopImmediatePropagation();
+
+			Materialize.toast(message, 4000);
+			$('html, body').animate({scrollTop: $(missing).closest('.input-field, p').add(missing).first().offset().top - 100}, 200);
+			if (missing.type !== 'hidden') {
+				missing.focus();
+			}
+		});
+
+		// once a form has been checked, its fields are checked again as they
+		// are filled in
+		$(document).on('input change', 'input, select, textarea', function() {
+			var form = $(this).closest('form');
+			if (form.data('ponzuRequired')) {
+				check(form);
+			}
+		});
+
+		// wait for RepeatController to name the items of repeaters
+		setTimeout(function() {
+			$('form').each(function() {
+				firstItems(this);
+			});
+		}, 0);
+	});
+</script>
+`
+}
@@ -0,0 +1,49 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	p := &testContact{Name: "Ada", Links: []string{"a", "b", "c"}}
+
+	view := string(Input("Name", p, map[string]string{"type": "text", "label": "Name", "required": "true"}))
+	if !strings.Contains(view, ` required `) || !strings.Contains(view, `<label class="active __ponzu-required"`) {
+		t.Errorf("Expected a required input with a marked label, got: %s", view)
+	}
+
+	if strings.Count(view, "window.__ponzuRequired = true") != 1 {
+		t.Errorf("Expected the script which blocks submitting an empty required field, got: %s", view)
+	}
+
+	view = string(Input("Name", p, map[string]string{"type": "text", "label": "Name", "required": "false"}))
+	if strings.Contains(view, "required") {
+		t.Errorf("Expected a field which isn't required, got: %s", view)
+	}
+
+	for name, view := range map[string]string{
+		"InputRepeater":  string(InputRepeater("Links", p, map[string]string{"type": "text", "label": "Links", "required": "true"})),
+		"SelectRepeater": string(SelectRepeater("Links", p, map[string]string{"label": "Links", "required": "true"}, map[string]string{"a": "A"})),
+		"FileRepeater":   string(FileRepeater("Links", p, map[string]string{"label": "Links", "required": "true"})),
+		"KeyValue":       string(KeyValue("Meta", &testMetadata{}, map[string]string{"label": "Meta", "required": "true"})),
+	} {
+		if !strings.Contains(view, ` data-required="true">`) {
+			t.Errorf("%s: expected the repeater to require its first item, got: %s", name, view)
+		}
+
+		markup := view[:strings.Index(view, "<script>")]
+		if strings.Contains(markup, " required ") || strings.Contains(markup, ` required="`) {
+			t.Errorf("%s: expected the items not to be required themselves, got: %s", name, markup)
+		}
+
+		if strings.Count(view, "window.__ponzuRequired = true") != 1 {
+			t.Errorf("%s: expected the script which blocks submitting an empty repeater, got: %s", name, view)
+		}
+	}
+
+	view = string(InputRepeater("Links", p, map[string]string{"type": "text"}))
+	if strings.Contains(view, "data-required") || strings.Contains(view, "__ponzuRequired") {
+		t.Errorf("Expected a repeater which isn't required, got: %s", view)
+	}
+}
@@ -0,0 +1,171 @@
+package editor
+
+import (
+	"bytes"
+	"html"
+	"log"
+	"strings"
+)
+
+// richTextTool is a toolbar button of the RichText editor, which runs the
+// editing command cmd with arg on the selection
+type richTextTool struct {
+	title string
+	icon  string
+	cmd   string
+	arg   string
+}
+
+// richTextTools are the toolbar buttons which can be chosen with
+// attrs["toolbar"], keyed by their names
+var richTextTools = map[string]richTextTool{
+	"bold":      {"Bold", "format_bold", "bold", ""},
+	"italic":    {"Italic", "format_italic", "italic", ""},
+	"underline": {"Underline", "format_underlined", "underline", ""},
+	"heading":   {"Heading", "title", "formatBlock", "h2"},
+	"quote":     {"Quote", "format_quote", "formatBlock", "blockquote"},
+	"ul":        {"Bulleted list", "format_list_bulleted", "insertUnorderedList", ""},
+	"ol":        {"Numbered list", "format_list_numbered", "insertOrderedList", ""},
+	"link":      {"Link", "insert_link", "createLink", ""},
+	"clear":     {"Clear formatting", "format_clear", "removeFormat", ""},
+}
+
+// DefaultRichTextToolbar are the toolbar buttons of a RichText editor which
+// doesn't set attrs["toolbar"]
+var DefaultRichTextToolbar = []string{"bold", "italic", "underline", "heading", "quote", "ul", "ol", "link", "clear"}
+
+// RichText returns the []byte of a WYSIWYG editor for HTML content, built on
+// the browser's own editing of a contenteditable element rather than the
+// bundled editor used by Richtext, whose HTML is synced into a hidden input
+// under the field's name. attrs["toolbar"] is a comma-separated list of the
+// toolbar buttons, out of bold, italic, underline, heading, quote, ul, ol, link
+// and clear, and defaults to DefaultRichTextToolbar. attrs["allowedTags"] is a
+// comma-separated list of the tags the stored HTML may contain, and defaults to
+// those allowed by SanitizeHTML. The stored HTML is sanitized before it is
+// loaded into the editor, but the submitted HTML must be sanitized too, using
+// SanitizeForm with the same tags in the content type's BeforeSave hook.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func RichText(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("RichText", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+
+	var allowed map[string][]string
+	if attrs["allowedTags"] != "" {
+		allowed = AllowedHTML(strings.Split(attrs["allowedTags"], ",")...)
+	}
+	value := SanitizeHTML(ValueFromStructField(fieldName, p), allowed)
+
+	tools := DefaultRichTextToolbar
+	if attrs["toolbar"] != "" {
+		tools = strings.Split(attrs["toolbar"], ",")
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="__ponzu-richtext ` + name + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to RichText buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to RichText buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<div class="__ponzu-richtext-toolbar">`)
+	if err != nil {
+		log.Println("Error writing HTML string to RichText buffer")
+		return nil
+	}
+
+	for _, t := range tools {
+		t = strings.TrimSpace(t)
+		tool, ok := richTextTools[t]
+		if !ok {
+			logf("editor: unknown RichText toolbar button", "button", t, "field", fieldName)
+			continue
+		}
+
+		_, err = view.WriteString(`<button type="button" class="btn-flat" data-cmd="` + tool.cmd + `" data-arg="` + tool.arg +
+			`" title="` + tool.title + `"><i class="material-icons">` + tool.icon + `</i></button>`)
+		if err != nil {
+			log.Println("Error writing HTML string to RichText buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`</div>` +
+		`<div class="__ponzu-richtext-editable card-panel" contenteditable="true" data-placeholder="` +
+		html.EscapeString(attrs["placeholder"]) + `">` + value + `</div>` +
+		`<input type="hidden" class="__ponzu-richtext-value" name="` + name + `" value="` + html.EscapeString(value) + `" />` +
+		`</div>` + richTextScript)
+	if err != nil {
+		log.Println("Error writing HTML string to RichText buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// richTextScript applies the toolbar buttons to, and syncs the HTML of, every
+// RichText editor of the page. Pasted content is inserted as plain text so that
+// markup from other documents isn't carried over.
+const richTextScript = `
+<script>
+	$(function() {
+		if (window.__ponzuRichText) {
+			return;
+		}
+		window.__ponzuRichText = true;
+
+		var sync = function(editable) {
+			$(editable).closest('.__ponzu-richtext').find('.__ponzu-richtext-value').val(editable.innerHTML);
+		}
+
+		$(document).on('input blur', '.__ponzu-richtext-editable', function() {
+			sync(this);
+		});
+
+		$(document).on('paste', '.__ponzu-richtext-editable', function(e) {
+			var data = e.originalEvent.clipboardData;
+			if (!data) {
+				return;
+			}
+
+			e.preventDefault();
+			document.execCommand('insertText', false, data.getData('text/plain'));
+			sync(this);
+		});
+
+		// keep the selection in the editable while a toolbar button is pressed
+		$(document).on('mousedown', '.__ponzu-richtext-toolbar button', function(e) {
+			e.preventDefault();
+		});
+
+		$(document).on('click', '.__ponzu-richtext-toolbar button', function(e) {
+			e.preventDefault();
+
+			var editable = $(this).closest('.__ponzu-richtext').find('.__ponzu-richtext-editable').get(0),
+				cmd = $(this).attr('data-cmd'),
+				arg = $(this).attr('data-arg') || null;
+
+			editable.focus();
+			if (cmd === 'createLink') {
+				arg = window.prompt('Link URL', 'https://');
+				if (!arg) {
+					return;
+				}
+			}
+
+			document.execCommand(cmd, false, arg);
+			sync(editable);
+		});
+	});
+</script>
+`
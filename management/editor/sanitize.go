@@ -0,0 +1,263 @@
+package editor
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// defaultAllowedHTML maps each HTML tag which survives sanitizing to the
+// attributes it may keep. Anything else is removed.
+var defaultAllowedHTML = map[string][]string{
+	"a":          {"href", "title", "target", "rel"},
+	"b":          nil,
+	"blockquote": nil,
+	"br":         nil,
+	"code":       nil,
+	"div":        nil,
+	"em":         nil,
+	"h1":         nil,
+	"h2":         nil,
+	"h3":         nil,
+	"h4":         nil,
+	"h5":         nil,
+	"h6":         nil,
+	"hr":         nil,
+	"i":          nil,
+	"img":        {"src", "alt", "title", "width", "height"},
+	"li":         nil,
+	"ol":         nil,
+	"p":          nil,
+	"pre":        nil,
+	"s":          nil,
+	"span":       nil,
+	"strike":     nil,
+	"strong":     nil,
+	"sub":        nil,
+	"sup":        nil,
+	"table":      nil,
+	"tbody":      nil,
+	"td":         {"colspan", "rowspan"},
+	"th":         {"colspan", "rowspan"},
+	"thead":      nil,
+	"tr":         nil,
+	"u":          nil,
+	"ul":         nil,
+}
+
+// droppedContentHTML are tags which are removed along with their content,
+// rather than just having the tag itself stripped
+var droppedContentHTML = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"template": true,
+	"noscript": true,
+	"textarea": true,
+	"select":   true,
+}
+
+var (
+	rxHTMLTagName = regexp.MustCompile(`^</?\s*([a-zA-Z][a-zA-Z0-9]*)`)
+	rxHTMLAttr    = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+)
+
+// AllowedHTML returns the allowed tags for SanitizeHTML limited to tags, each
+// keeping the same attributes as by default. Tags which aren't allowed by
+// default are allowed without any attributes. With no tags, all the tags
+// allowed by default are returned.
+func AllowedHTML(tags ...string) map[string][]string {
+	allowed := make(map[string][]string)
+	if len(tags) == 0 {
+		for tag, attrs := range defaultAllowedHTML {
+			allowed[tag] = attrs
+		}
+
+		return allowed
+	}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || droppedContentHTML[tag] {
+			continue
+		}
+
+		allowed[tag] = defaultAllowedHTML[tag]
+	}
+
+	return allowed
+}
+
+// SanitizeHTML returns s with every tag and attribute not in allowed removed,
+// as well as comments and the content of tags such as <script> and <style>.
+// URLs in href and src attributes are limited to relative, http(s) and mailto
+// URLs. A nil allowed uses the tags allowed by default, see AllowedHTML.
+func SanitizeHTML(s string, allowed map[string][]string) string {
+	if allowed == nil {
+		allowed = defaultAllowedHTML
+	}
+
+	return sanitizeHTML(s, allowed)
+}
+
+// SanitizeForm sanitizes the submitted values of the named fields, including
+// their indexed values (name.0, name.1, ...), with SanitizeHTML. It is meant to
+// be called from a content type's BeforeSave hook with the request's PostForm,
+// so HTML from fields such as RichText is clean before it is stored, even if
+// the editor was bypassed.
+func SanitizeForm(form url.Values, allowed map[string][]string, fields ...string) {
+	for key, vals := range form {
+		if !matchesField(key, fields) {
+			continue
+		}
+
+		for i := range vals {
+			vals[i] = SanitizeHTML(vals[i], allowed)
+		}
+	}
+}
+
+// sanitizeHTML returns s with every tag and attribute not in allowed removed,
+// comments stripped, and URLs in href/src attributes limited to safe schemes
+func sanitizeHTML(s string, allowed map[string][]string) string {
+	out := &strings.Builder{}
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i == -1 {
+			out.WriteString(s)
+			break
+		}
+
+		out.WriteString(s[:i])
+		s = s[i:]
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end == -1 {
+				break
+			}
+
+			s = s[end+len("-->"):]
+			continue
+		}
+
+		end := tagEnd(s)
+		if end == -1 {
+			// an unterminated tag, escape the rest rather than emit it
+			out.WriteString(html.EscapeString(s))
+			break
+		}
+
+		tag := s[:end+1]
+		s = s[end+1:]
+
+		m := rxHTMLTagName.FindStringSubmatch(tag)
+		if m == nil {
+			out.WriteString(html.EscapeString(tag))
+			continue
+		}
+
+		name := strings.ToLower(m[1])
+		closing := strings.HasPrefix(tag, "</")
+
+		if droppedContentHTML[name] {
+			if !closing {
+				s = skipPastClosingTag(s, name)
+			}
+			continue
+		}
+
+		attrs, ok := allowed[name]
+		if !ok {
+			continue
+		}
+
+		if closing {
+			out.WriteString("</" + name + ">")
+			continue
+		}
+
+		out.WriteString("<" + name)
+		for _, a := range rxHTMLAttr.FindAllStringSubmatch(tag[len(m[0]):], -1) {
+			attr := strings.ToLower(a[1])
+			if !hasString(attrs, attr) {
+				continue
+			}
+
+			val := html.UnescapeString(a[2] + a[3] + a[4])
+			if (attr == "href" || attr == "src") && !safeURL(val) {
+				continue
+			}
+
+			out.WriteString(" " + attr + `="` + html.EscapeString(val) + `"`)
+		}
+		out.WriteString(">")
+	}
+
+	return out.String()
+}
+
+// tagEnd returns the index of the '>' which closes the tag at the start of s,
+// ignoring any within quoted attribute values, or -1 if it isn't closed
+func tagEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '>':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// skipPastClosingTag returns s after the closing tag for name, or an empty
+// string if there is none
+func skipPastClosingTag(s, name string) string {
+	i := strings.Index(strings.ToLower(s), "</"+name)
+	if i == -1 {
+		return ""
+	}
+
+	s = s[i:]
+	end := tagEnd(s)
+	if end == -1 {
+		return ""
+	}
+
+	return s[end+1:]
+}
+
+// safeURL reports whether u is relative or uses an http(s) or mailto scheme
+func safeURL(u string) bool {
+	u = strings.ToLower(strings.TrimSpace(u))
+	u = strings.Map(func(r rune) rune {
+		// browsers ignore control characters and whitespace within schemes
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, u)
+
+	i := strings.IndexAny(u, ":/?#")
+	if i == -1 || u[i] != ':' {
+		return true
+	}
+
+	switch u[:i] {
+	case "http", "https", "mailto":
+		return true
+	}
+
+	return false
+}
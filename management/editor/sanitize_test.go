@@ -0,0 +1,69 @@
+package editor
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	in := `<p onclick="x()">Hi <b>there</b><script>alert(1)</script>` +
+		`<a href="javascript:alert(1)">bad</a> <a href="/ok" target="_blank">ok</a></p>`
+
+	got := SanitizeHTML(in, nil)
+	expected := `<p>Hi <b>there</b><a>bad</a> <a href="/ok" target="_blank">ok</a></p>`
+	if got != expected {
+		t.Errorf("Expected %q, got: %q", expected, got)
+	}
+
+	got = SanitizeHTML(in, AllowedHTML("p", " A "))
+	expected = `<p>Hi there<a>bad</a> <a href="/ok" target="_blank">ok</a></p>`
+	if got != expected {
+		t.Errorf("Expected %q, got: %q", expected, got)
+	}
+
+	if _, ok := AllowedHTML("script", "mark")["script"]; ok {
+		t.Error("Expected script to never be allowed")
+	}
+}
+
+func TestSanitizeForm(t *testing.T) {
+	form := url.Values{
+		"body":    {`<p>Body<img src="x" onerror="alert(1)"></p>`},
+		"notes.0": {`<i>note</i><style>p{}</style>`},
+		"title":   {`<b>Title</b>`},
+	}
+
+	SanitizeForm(form, AllowedHTML("p", "i"), "body", "notes")
+
+	expected := map[string]string{
+		"body":    `<p>Body</p>`,
+		"notes.0": `<i>note</i>`,
+		"title":   `<b>Title</b>`,
+	}
+
+	for k, v := range expected {
+		if form.Get(k) != v {
+			t.Errorf("Expected %s to be %q, got: %q", k, v, form.Get(k))
+		}
+	}
+}
+
+func TestRichTextSanitizesStoredHTML(t *testing.T) {
+	p := &testContact{Bio: `<h2>About</h2><img src="x" onerror="alert(1)">`}
+
+	view := string(RichText("Bio", p, map[string]string{"label": "Bio", "allowedTags": "h2,p"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	if !strings.Contains(view, `contenteditable="true" data-placeholder=""><h2>About</h2></div>`) {
+		t.Errorf("Expected the sanitized HTML in the editor, got: %s", view)
+	}
+
+	if !strings.Contains(view, `name="bio" value="&lt;h2&gt;About&lt;/h2&gt;"`) {
+		t.Errorf("Expected the escaped, sanitized HTML in the hidden input, got: %s", view)
+	}
+
+	if strings.Count(view, "<button") != len(DefaultRichTextToolbar) {
+		t.Errorf("Expected the default toolbar, got: %s", view)
+	}
+}
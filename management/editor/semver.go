@@ -0,0 +1,96 @@
+package editor
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// semVerPattern matches a semantic version (https://semver.org), e.g. "1.4.2"
+// or "2.0.0-rc.1+build.5". It is valid both as a Go regexp and as the pattern
+// attribute of an HTML input.
+const semVerPattern = `(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z\-][0-9a-zA-Z\-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z\-][0-9a-zA-Z\-]*))*))?` +
+	`(?:\+([0-9a-zA-Z\-]+(?:\.[0-9a-zA-Z\-]+)*))?`
+
+var rxSemVer = regexp.MustCompile(`^` + semVerPattern + `$`)
+
+// SemVer returns the []byte of an <input> HTML element for a semantic version,
+// such as "1.4.2", which is marked invalid unless it is a valid semantic
+// version. When attrs["bump"] is "true", buttons to bump the major, minor and
+// patch numbers are rendered alongside it. Check the same constraint on the
+// server with ValidateSemVer, since client-side checks can be bypassed.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func SemVer(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("SemVer", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+
+	inputAttrs := make(map[string]string, len(attrs)+3)
+	for k, v := range attrs {
+		inputAttrs[k] = v
+	}
+	inputAttrs["type"] = "text"
+	inputAttrs["pattern"] = semVerPattern
+	if inputAttrs["placeholder"] == "" {
+		inputAttrs["placeholder"] = "1.0.0"
+	}
+	if inputAttrs["title"] == "" {
+		inputAttrs["title"] = text("semver.title")
+	}
+
+	e := NewElement("input", attrs["label"], fieldName, p, inputAttrs)
+	view := `<div class="__ponzu-semver ` + name + `">` + string(DOMElementSelfClose(e))
+
+	if attrs["bump"] != "true" {
+		return []byte(view + `</div>`)
+	}
+
+	view += `<div class="__ponzu-semver-bump col s12">
+		<button type="button" class="btn-flat waves-effect" data-bump="major">` + htmlText("semver.major") + `</button>
+		<button type="button" class="btn-flat waves-effect" data-bump="minor">` + htmlText("semver.minor") + `</button>
+		<button type="button" class="btn-flat waves-effect" data-bump="patch">` + htmlText("semver.patch") + `</button>
+	</div></div>
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-semver.` + selectorClass(name) + `'),
+				input = scope.find('input[name="' + ` + jsString(name) + ` + '"]');
+
+			scope.on('click', '[data-bump]', function(e) {
+				e.preventDefault();
+
+				var m = /^(\d+)\.(\d+)\.(\d+)/.exec($.trim(input.val())) || [null, 0, 0, 0],
+					v = [parseInt(m[1], 10), parseInt(m[2], 10), parseInt(m[3], 10)];
+
+				// bumping drops any pre-release and build metadata
+				switch ($(this).attr('data-bump')) {
+				case 'major':
+					v = [v[0] + 1, 0, 0];
+					break;
+				case 'minor':
+					v = [v[0], v[1] + 1, 0];
+					break;
+				case 'patch':
+					v = [v[0], v[1], v[2] + 1];
+					break;
+				}
+
+				input.val(v.join('.')).trigger('change');
+			});
+		});
+	</script>`
+
+	return []byte(view)
+}
+
+// ValidateSemVer returns an error unless s is a valid semantic version, as
+// defined by https://semver.org, e.g. "1.4.2" or "2.0.0-rc.1+build.5". A
+// leading "v" is not accepted.
+func ValidateSemVer(s string) error {
+	if !rxSemVer.MatchString(s) {
+		return fmt.Errorf("%q is not a valid semantic version, e.g. 1.4.2", s)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package editor
+
+import "testing"
+
+func TestValidateSemVer(t *testing.T) {
+	valid := []string{
+		"0.0.0", "1.4.2", "10.20.30", "1.0.0-alpha", "1.0.0-alpha.1",
+		"1.0.0-0.3.7", "1.0.0-x-y-z.--", "1.0.0+build.5", "2.0.0-rc.1+build.5",
+	}
+
+	for _, v := range valid {
+		if err := ValidateSemVer(v); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", v, err)
+		}
+	}
+
+	invalid := []string{
+		"", "1", "1.2", "v1.2.3", "01.2.3", "1.02.3", "1.2.3-", "1.2.3+",
+		"1.2.3-01", "1.2.3.4", " 1.2.3", "1.2.3-alpha..1",
+	}
+
+	for _, v := range invalid {
+		if err := ValidateSemVer(v); err == nil {
+			t.Errorf("Expected %q to be invalid", v)
+		}
+	}
+}
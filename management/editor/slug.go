@@ -0,0 +1,108 @@
+package editor
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slug returns the []byte of an <input> HTML element for a URL slug, which is
+// derived from the field named sourceFieldName, e.g. a title, as it is typed.
+// Once the slug is edited by hand it is no longer derived, unless it is
+// emptied again, and a stored slug which doesn't match its source is never
+// replaced. Slugs are derived the same way as by Slugify, which should also be
+// applied on save, since client-side changes can be bypassed.
+// IMPORTANT:
+// The `fieldName` and `sourceFieldName` arguments will cause a panic if they
+// are not exactly the string form of the struct fields they represent
+func Slug(fieldName string, p interface{}, sourceFieldName string, attrs map[string]string) []byte {
+	checkAttrs("Slug", fieldName, attrs)
+
+	slugAttrs := make(map[string]string, len(attrs)+4)
+	for k, v := range attrs {
+		slugAttrs[k] = v
+	}
+	slugAttrs["type"] = "text"
+	slugAttrs["spellcheck"] = "false"
+	slugAttrs["data-ponzu-slug-source"] = TagNameFromStructField(sourceFieldName, p)
+	if slugAttrs["class"] != "" {
+		slugAttrs["class"] += " __ponzu-slug-input"
+	} else {
+		slugAttrs["class"] = "__ponzu-slug-input"
+	}
+
+	e := NewElement("input", attrs["label"], fieldName, p, slugAttrs)
+
+	return append(DOMElementSelfClose(e), slugScript...)
+}
+
+// Slugify returns s as a URL slug, e.g. "Hello, World!" becomes "hello-world".
+// It is lowercased, apostrophes are removed, and each run of characters other
+// than letters and numbers is replaced by a single hyphen, without leading or
+// trailing hyphens. It matches the slugs derived by Slug in the browser.
+func Slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r == '\'' || r == '’':
+			continue
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+
+	return b.String()
+}
+
+// slugScript derives every Slug of the page from its source field, until it is
+// edited by hand
+var slugScript = []byte(`
+<script>
+	$(function() {
+		if (window.__ponzuSlug) {
+			return;
+		}
+		window.__ponzuSlug = true;
+
+		// slugify matches the editor.Slugify func
+		var slugify = function(s) {
+			return s.toLowerCase()
+				.replace(/['’]/g, '')
+				.replace(/[^\p{L}\p{N}]+/gu, '-')
+				.replace(/^-+|-+$/g, '');
+		}
+
+		var source = function(slug) {
+			return $(slug.form || document).find('[name="' + $(slug).attr('data-ponzu-slug-source') + '"]');
+		}
+
+		// a stored slug is only derived while it matches its source
+		$('.__ponzu-slug-input').each(function(i, slug) {
+			var manual = slug.value !== '' && slug.value !== slugify(source(slug).val() || '');
+			$(slug).data('manual', manual);
+		});
+
+		$(document).on('input', '.__ponzu-slug-input', function() {
+			$(this).data('manual', this.value !== '');
+		});
+
+		$(document).on('change focusout', '.__ponzu-slug-input', function() {
+			this.value = slugify(this.value);
+		});
+
+		$(document).on('input change', '[name]', function(e) {
+			$('.__ponzu-slug-input').each(function(i, slug) {
+				if (!$(slug).data('manual') && source(slug).is(e.target)) {
+					slug.value = slugify(e.target.value);
+				}
+			});
+		});
+	});
+</script>
+`)
@@ -0,0 +1,36 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Hello, World!":           "hello-world",
+		"  Don't   stop -- now  ": "dont-stop-now",
+		"Ponzu’s 2nd Release":     "ponzus-2nd-release",
+		"Crème brûlée":            "crème-brûlée",
+		"already-a-slug":          "already-a-slug",
+		"--!!--":                  "",
+	}
+
+	for in, want := range cases {
+		if got := Slugify(in); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	p := &testContact{Name: "Ada Lovelace", Slug: "ada"}
+
+	view := string(Slug("Slug", p, "Name", map[string]string{"label": "Slug"}))
+	if !strings.Contains(view, `value="ada"`) || !strings.Contains(view, `data-ponzu-slug-source="name"`) {
+		t.Errorf("Expected the stored slug derived from its source, got: %s", view)
+	}
+
+	if !strings.Contains(view, `name="slug"`) || !strings.Contains(view, "__ponzu-slug-input") {
+		t.Errorf("Expected the slug input, got: %s", view)
+	}
+}
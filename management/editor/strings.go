@@ -0,0 +1,156 @@
+package editor
+
+import (
+	"html"
+	"strings"
+	"sync"
+)
+
+// DefaultStrings are the built-in strings of the editor's widgets, such as the
+// call to action of a select, in English, keyed by their names. They can be
+// translated with SetStrings. Field labels are not included, since they are
+// always set with attrs["label"]. The names in braces, such as the "{n}" of
+// "repeat.max", are replaced when a string is shown, and should be kept by its
+// translations.
+var DefaultStrings = map[string]string{
+	"select.cta":                 "Select an option...",
+	"select.none":                "None",
+	"select.unavailable":         "{value} (unavailable)",
+	"select.other":               "Other...",
+	"select.newValue":            "Enter a new value",
+	"field.required":             "Please fill in the required fields",
+	"file.upload":                "Upload",
+	"file.add":                   "Add {name}",
+	"file.tooLarge":              "The file is larger than {size}",
+	"file.notAccepted":           "This type of file is not accepted",
+	"file.view":                  "Download / View ",
+	"repeat.drag":                "Drag to reorder",
+	"repeat.max":                 "Limited to {n} items",
+	"repeat.min":                 "At least {n} items are required",
+	"repeat.unique":              "Each value must be different",
+	"group.add":                  "Add",
+	"block.add":                  "Add block",
+	"checkbox.all":               "Select all",
+	"checkbox.none":              "None",
+	"radio.none":                 "None",
+	"reference.search":           "Search...",
+	"tags.placeholder":           `Type and press "Enter"`,
+	"tokens.placeholder":         "Add...",
+	"tokens.remove":              "Remove",
+	"tokens.notSuggested":        "Please choose one of the suggestions",
+	"password.show":              "Show",
+	"password.hide":              "Hide",
+	"timezone.search":            "Search time zones...",
+	"keyvalue.key":               "Key",
+	"keyvalue.value":             "Value",
+	"money.invalid":              "Enter an amount, such as 1234.56",
+	"code.invalidJSON":           "Invalid JSON: {error}",
+	"time.hour":                  "Hour",
+	"time.minute":                "Minute",
+	"time.meridiem":              "AM or PM",
+	"time.am":                    "AM",
+	"time.pm":                    "PM",
+	"counter.words":              "{n} words",
+	"counter.tooLong":            "Limited to {n} characters",
+	"latlng.latitude":            "Latitude",
+	"latlng.longitude":           "Longitude",
+	"latlng.latRange":            "The latitude must be between -90 and 90",
+	"latlng.lngRange":            "The longitude must be between -180 and 180",
+	"latlng.both":                "Enter both a latitude and a longitude",
+	"latlng.zoomIn":              "Zoom in",
+	"latlng.zoomOut":             "Zoom out",
+	"toggle.on":                  "On",
+	"toggle.off":                 "Off",
+	"semver.title":               "A semantic version, e.g. 1.4.2",
+	"semver.major":               "Major",
+	"semver.minor":               "Minor",
+	"semver.patch":               "Patch",
+	"editor.save":                "Save",
+	"editor.delete":              "Delete",
+	"editor.cancel":              "Cancel",
+	"editor.approve":             "Approve",
+	"editor.reject":              "Reject",
+	"autosave.saving":            "Saving...",
+	"autosave.saved":             "Saved at {time}",
+	"autosave.failed":            "Autosave failed",
+	"validate.requireOneOf":      "Please fill in at least one of: {fields}",
+	"validate.mutuallyExclusive": "Only one of these may be set: {fields}",
+	"url.scheme":                 "URLs must begin with {schemes}",
+	"url.invalid":                "Please enter a valid URL",
+	"numberrange.order":          "The first value must not be greater than the second",
+	"image.width":                "width must be exactly {n}px",
+	"image.height":               "height must be exactly {n}px",
+	"image.minWidth":             "width must be at least {n}px",
+	"image.minHeight":            "height must be at least {n}px",
+	"image.and":                  " and ",
+	"image.rejected":             "The selected image is {size}px, but its {problems}.",
+	"ajax.saved":                 "Saved",
+	"ajax.failed":                "Unable to save: {error}",
+	"emoji.title":                "Insert an emoji or symbol",
+	"markdown.bold":              "Bold",
+	"markdown.italic":            "Italic",
+	"markdown.heading":           "Heading",
+	"markdown.link":              "Link",
+	"markdown.quote":             "Quote",
+	"markdown.code":              "Code",
+	"markdown.ul":                "Bulleted list",
+	"markdown.ol":                "Numbered list",
+	"markdown.preview":           "Preview",
+	"markdown.edit":              "Edit",
+	"links.label":                "Link text",
+	"links.url":                  "URL",
+	"weighted.weight":            "Weight",
+}
+
+var (
+	stringsMu sync.RWMutex
+	overrides = map[string]string{}
+)
+
+// SetStrings overrides the DefaultStrings keyed the same way by s, for example
+// to translate the editor's widgets:
+//
+//	editor.SetStrings(map[string]string{
+//		"select.cta":  "Choisissez une option...",
+//		"select.none": "Aucune",
+//	})
+//
+// Strings which s doesn't override stay in English. It should be set once,
+// before any editor is rendered. Passing nil restores the DefaultStrings.
+func SetStrings(s map[string]string) {
+	o := make(map[string]string, len(s))
+	for k, v := range s {
+		if _, ok := DefaultStrings[k]; !ok {
+			logf("editor: unknown string overridden", "key", k)
+		}
+		o[k] = v
+	}
+
+	stringsMu.Lock()
+	overrides = o
+	stringsMu.Unlock()
+}
+
+// text returns the built-in string keyed by key, as overridden by SetStrings,
+// with each "{name}" in it replaced by the string following that name in
+// replacements
+func text(key string, replacements ...string) string {
+	stringsMu.RLock()
+	s, ok := overrides[key]
+	stringsMu.RUnlock()
+
+	if !ok {
+		s = DefaultStrings[key]
+	}
+
+	for i := 0; i+1 < len(replacements); i += 2 {
+		s = strings.Replace(s, "{"+replacements[i]+"}", replacements[i+1], -1)
+	}
+
+	return s
+}
+
+// htmlText returns text(key, replacements...) escaped for use in HTML
+func htmlText(key string, replacements ...string) string {
+	return html.EscapeString(text(key, replacements...))
+}
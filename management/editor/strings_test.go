@@ -0,0 +1,100 @@
+package editor
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetStrings(t *testing.T) {
+	defer SetStrings(nil)
+
+	SetStrings(map[string]string{
+		"select.cta":  "Choisissez une option...",
+		"file.add":    "Ajouter <{name}>",
+		"file.upload": "Téléverser",
+	})
+
+	p := &testContact{Links: []string{""}}
+
+	view := string(SelectRepeater("Links", p, map[string]string{}, map[string]string{"a": "A"}))
+	if !strings.Contains(view, ">Choisissez une option...</option>") || !strings.Contains(view, ">None</option>") {
+		t.Errorf("Expected the translated call to action and the default None option, got: %s", view)
+	}
+
+	view = string(FileRepeater("Links", p, map[string]string{"label": "Images"}))
+	if !strings.Contains(view, "<span>Téléverser</span>") || !strings.Contains(view, `placeholder="Ajouter &lt;Images&gt;"`) {
+		t.Errorf("Expected the translated and escaped upload strings, got: %s", view)
+	}
+
+	SetStrings(nil)
+
+	view = string(FileRepeater("Links", p, map[string]string{"label": "Images"}))
+	if !strings.Contains(view, "<span>Upload</span>") || !strings.Contains(view, `placeholder="Add Images"`) {
+		t.Errorf("Expected the default strings to be restored, got: %s", view)
+	}
+}
+
+func TestDefaultStringsUsed(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var src string
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") || f == "strings.go" {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		src += string(b)
+	}
+
+	for k := range DefaultStrings {
+		if !strings.Contains(src, `"`+k+`"`) {
+			t.Errorf("DefaultStrings[%q] is never shown", k)
+		}
+	}
+}
+
+func TestSetStringsScripts(t *testing.T) {
+	defer SetStrings(nil)
+
+	SetStrings(map[string]string{
+		"url.invalid":     "Adresse invalide",
+		"semver.major":    "Majeure",
+		"markdown.bold":   "Gras",
+		"autosave.failed": `L'enregistrement a échoué`,
+	})
+
+	p := &testContact{Name: "1.0.0", Bio: "**Hi**"}
+
+	view := string(URL("Name", p, map[string]string{"label": "Site"}))
+	if !strings.Contains(view, `message = "Adresse invalide";`) {
+		t.Errorf("Expected the translated URL message, got: %s", view)
+	}
+
+	view = string(SemVer("Name", p, map[string]string{"bump": "true"}))
+	if !strings.Contains(view, `data-bump="major">Majeure</button>`) {
+		t.Errorf("Expected the translated bump, got: %s", view)
+	}
+
+	view = string(Markdown("Bio", p, map[string]string{}))
+	if !strings.Contains(view, `title="Gras"`) {
+		t.Errorf("Expected the translated toolbar, got: %s", view)
+	}
+
+	form, err := Form(&testPost{Title: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(form), `status.text("L'enregistrement a échoué");`) {
+		t.Errorf("Expected the translated autosave status, got: %s", form)
+	}
+}
@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// staticTemplate is markup or a script with dynamic values named in double
+// braces, e.g. "{{scope}}", which is split around them once, so that it can be
+// rendered for every field without being concatenated or formatted again
+type staticTemplate struct {
+	// parts are the static text of the template, with the value at
+	// values[i] following parts[i], and nothing following the last part
+	parts  []string
+	values []int
+}
+
+// parseTemplate returns the staticTemplate of s, whose values are named by keys
+// in the order they are given to render. It panics if s names any other value,
+// since templates are parsed when the package is initialized.
+func parseTemplate(s string, keys ...string) *staticTemplate {
+	t := &staticTemplate{}
+	for {
+		i := strings.Index(s, "{{")
+		if i == -1 {
+			t.parts = append(t.parts, s)
+			return t
+		}
+
+		j := strings.Index(s[i:], "}}")
+		if j == -1 {
+			panic(fmt.Sprintf("editor: unclosed template value in %q", s[i:]))
+		}
+
+		key := s[i+2 : i+j]
+		index := -1
+		for k := range keys {
+			if keys[k] == key {
+				index = k
+			}
+		}
+
+		if index == -1 {
+			panic(fmt.Sprintf("editor: unknown template value %q", key))
+		}
+
+		t.parts = append(t.parts, s[:i])
+		t.values = append(t.values, index)
+		s = s[i+j+2:]
+	}
+}
+
+// render returns the template with each of its values replaced by values, in
+// the order of the keys it was parsed with
+func (t *staticTemplate) render(values ...string) []byte {
+	n := 0
+	for _, p := range t.parts {
+		n += len(p)
+	}
+	for _, v := range t.values {
+		n += len(values[v])
+	}
+
+	out := make([]byte, 0, n)
+	for i, v := range t.values {
+		out = append(out, t.parts[i]...)
+		out = append(out, values[v]...)
+	}
+
+	return append(out, t.parts[len(t.parts)-1]...)
+}
+
+// execute writes the template to w like render, without building it in memory
+// first
+func (t *staticTemplate) execute(w io.Writer, values ...string) error {
+	for i, v := range t.values {
+		_, err := io.WriteString(w, t.parts[i])
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, values[v])
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, t.parts[len(t.parts)-1])
+	return err
+}
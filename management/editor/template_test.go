@@ -0,0 +1,16 @@
+package editor
+
+import "testing"
+
+func TestStaticTemplate(t *testing.T) {
+	tmpl := parseTemplate(`<div class="{{class}}">{{label}}</div>{{class}}`, "label", "class")
+
+	out := string(tmpl.render("Name", "field"))
+	if out != `<div class="field">Name</div>field` {
+		t.Errorf("Expected the values to be substituted, got: %s", out)
+	}
+
+	if out := string(parseTemplate("static").render()); out != "static" {
+		t.Errorf("Expected a template without values to render as is, got: %s", out)
+	}
+}
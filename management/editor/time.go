@@ -0,0 +1,60 @@
+package editor
+
+import (
+	"reflect"
+	"time"
+)
+
+// TimeLayout is the layout used to format time.Time fields when they are
+// rendered by the editor, and to parse submitted values back. The date and time
+// fields accept attrs["layout"] to override it for a single field.
+var TimeLayout = time.RFC3339
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// FormatTime returns t formatted with layout, or an empty string if t is the
+// zero time so that unset fields render empty rather than as "0001-01-01".
+func FormatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+// ParseTime parses s with layout, returning the zero time for an empty string.
+// It is the inverse of FormatTime.
+func ParseTime(s, layout string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(layout, s)
+}
+
+// timeLayout returns the layout set in attrs, or TimeLayout
+func timeLayout(attrs map[string]string) string {
+	if layout := attrs["layout"]; layout != "" {
+		return layout
+	}
+
+	return TimeLayout
+}
+
+// timeValue returns the time held by v if it is a time.Time or *time.Time. A
+// nil *time.Time is returned as the zero time.
+func timeValue(v reflect.Value) (time.Time, bool) {
+	if v.Kind() == reflect.Ptr && v.Type().Elem() == timeType {
+		if v.IsNil() {
+			return time.Time{}, true
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Type() != timeType {
+		return time.Time{}, false
+	}
+
+	return v.Interface().(time.Time), true
+}
@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	Starts   time.Time   `json:"starts"`
+	Ends     *time.Time  `json:"ends"`
+	Sessions []time.Time `json:"sessions"`
+}
+
+func TestFormatTime(t *testing.T) {
+	nyc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Time zone database unavailable: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		time     time.Time
+		expected string
+	}{
+		{"zero", time.Time{}, ""},
+		{"utc", time.Date(2018, 3, 4, 15, 30, 0, 0, time.UTC), "2018-03-04T15:30:00Z"},
+		{"zoned", time.Date(2018, 3, 4, 15, 30, 0, 0, nyc), "2018-03-04T15:30:00-05:00"},
+	}
+
+	for _, c := range cases {
+		s := FormatTime(c.time, TimeLayout)
+		if s != c.expected {
+			t.Errorf("%s: expected %q, got: %q", c.name, c.expected, s)
+		}
+
+		parsed, err := ParseTime(s, TimeLayout)
+		if err != nil {
+			t.Errorf("%s: failed to parse %q: %s", c.name, s, err)
+		}
+
+		if !parsed.Equal(c.time) {
+			t.Errorf("%s: expected %s to round-trip, got: %s", c.name, c.time, parsed)
+		}
+	}
+}
+
+func TestValueFromStructFieldTime(t *testing.T) {
+	e := &testEvent{}
+	if v := ValueFromStructField("Starts", e); v != "" {
+		t.Errorf("Expected zero time to render empty, got: %q", v)
+	}
+
+	if v := ValueFromStructField("Ends", e); v != "" {
+		t.Errorf("Expected nil time to render empty, got: %q", v)
+	}
+
+	ends := time.Date(2018, 3, 4, 17, 0, 0, 0, time.UTC)
+	e.Starts = time.Date(2018, 3, 4, 15, 30, 0, 0, time.UTC)
+	e.Ends = &ends
+	e.Sessions = []time.Time{e.Starts, ends}
+
+	if v := ValueFromStructField("Starts", e); v != "2018-03-04T15:30:00Z" {
+		t.Errorf("Expected UTC time to be formatted, got: %q", v)
+	}
+
+	if v := ValueFromStructField("Ends", e); v != "2018-03-04T17:00:00Z" {
+		t.Errorf("Expected time pointer to be formatted, got: %q", v)
+	}
+
+	vals := ValuesFromStructField("Sessions", e)
+	if len(vals) != 2 || vals[1] != "2018-03-04T17:00:00Z" {
+		t.Errorf("Expected time slice to be formatted, got: %v", vals)
+	}
+}
@@ -0,0 +1,227 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeStep is the number of minutes between the minutes offered by a
+// Time without attrs["step"]
+const DefaultTimeStep = 5
+
+// clockLayout is the layout of the values stored by a Time, e.g. "09:30"
+const clockLayout = "15:04"
+
+// Time returns the []byte of a time picker for a time of day without a date,
+// such as the opening hour of a store, which is stored as "HH:MM" on a 24 hour
+// clock, e.g. "17:30". attrs["clock"] is "12h" to pick the hour on a 12 hour
+// clock with AM and PM, or "24h", the default. attrs["step"] is the number of
+// minutes between the minutes offered, and defaults to DefaultTimeStep, while a
+// stored minute off the step is still offered. An unset value shows empty
+// pickers rather than a default time, and clearing the hour clears the value.
+// The field should be a string.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Time(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Time", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := clockValue(fieldName, ValueFromStructField(fieldName, p))
+
+	return []byte(timePicker(name, name, attrs["label"], value, false, attrs) + helpText(attrs) + timeScript())
+}
+
+// TimeRepeater returns the []byte of a Time for each of the times of a slice
+// field, such as the time slots of a day. It also includes repeat controllers
+// (+ / -) so the times can be dynamically multiplied or reduced.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func TimeRepeater(fieldName string, p interface{}, attrs map[string]string) []byte {
+	view := &bytes.Buffer{}
+	err := TimeRepeaterTo(view, fieldName, p, attrs)
+	if err != nil {
+		log.Println("Error writing HTML string to TimeRepeater buffer")
+		return nil
+	}
+
+	return view.Bytes()
+}
+
+// TimeRepeaterTo is like TimeRepeater, but writes the element to w as it is
+// rendered, rather than returning it (see FormTo)
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func TimeRepeaterTo(w io.Writer, fieldName string, p interface{}, attrs map[string]string) error {
+	checkAttrs("TimeRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		_, err := w.Write(view)
+		return err
+	}
+
+	scope := TagNameFromStructField(fieldName, p)
+	r := repeater{
+		class:  "__ponzu-time-repeat " + scope,
+		values: ValuesFromStructField(fieldName, p),
+		script: timeScript(),
+		input:  "input.__ponzu-time-value",
+		clone:  "div.__ponzu-time",
+	}
+
+	return renderRepeater(w, fieldName, p, attrs, r, func(w io.Writer, item repeatItem) error {
+		_, err := io.WriteString(w, timePicker(scope, item.Name, item.Label, clockValue(fieldName, item.Value), item.Error != "", attrs))
+		return err
+	})
+}
+
+// clockValue returns value if it is a time of day stored by a Time, and
+// otherwise reports it and returns an empty string, so that it is shown unset
+func clockValue(fieldName, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if _, err := time.Parse(clockLayout, value); err != nil {
+		logf("editor: Time value is not HH:MM", "value", value, "field", fieldName)
+		return ""
+	}
+
+	return value
+}
+
+// timeStep returns the number of minutes set by attrs["step"], or
+// DefaultTimeStep
+func timeStep(attrs map[string]string) int {
+	if attrs["step"] == "" {
+		return DefaultTimeStep
+	}
+
+	step, err := strconv.Atoi(attrs["step"])
+	if err != nil || step < 1 || step > 60 {
+		logf("editor: invalid Time step ignored", "step", attrs["step"])
+		return DefaultTimeStep
+	}
+
+	return step
+}
+
+// timePicker returns the markup of a Time named name holding value, which is
+// either empty or "HH:MM", with its pickers marked invalid when invalid is
+// true. They are marked with data-ponzu-display, so that only the hidden input
+// holding the value is named by RepeatController.
+func timePicker(class, name, label, value string, invalid bool, attrs map[string]string) string {
+	twelve := attrs["clock"] == "12h"
+	step := timeStep(attrs)
+	id := fieldID(name)
+
+	hour, minute := -1, -1
+	if t, err := time.Parse(clockLayout, value); err == nil {
+		hour, minute = t.Hour(), t.Minute()
+	}
+
+	clock := "24h"
+	if twelve {
+		clock = "12h"
+	}
+
+	var marked string
+	if invalid {
+		marked = " invalid"
+	}
+
+	view := `<div class="__ponzu-time ` + class + ` input-field col s12" data-clock="` + clock + `">`
+	if label != "" {
+		view += `<label class="active" for="` + id + `">` + label + `</label>`
+	}
+
+	view += `<div class="__ponzu-time-pickers">` +
+		`<select id="` + id + `" class="__ponzu-time-hour browser-default` + marked + `" data-ponzu-display="true" aria-label="` + htmlText("time.hour") + `">` +
+		timeOption("", "--", hour == -1)
+
+	if twelve {
+		for i := 0; i < 12; i++ {
+			h := i
+			if h == 0 {
+				h = 12
+			}
+			view += timeOption(fmt.Sprintf("%02d", h), strconv.Itoa(h), hour != -1 && hour%12 == i)
+		}
+	} else {
+		for h := 0; h < 24; h++ {
+			view += timeOption(fmt.Sprintf("%02d", h), fmt.Sprintf("%02d", h), hour == h)
+		}
+	}
+
+	view += `</select><span class="__ponzu-time-separator">:</span>` +
+		`<select class="__ponzu-time-minute browser-default` + marked + `" data-ponzu-display="true" aria-label="` + htmlText("time.minute") + `">` +
+		timeOption("", "--", minute == -1)
+
+	for m := 0; m < 60; m++ {
+		if m%step == 0 || m == minute {
+			view += timeOption(fmt.Sprintf("%02d", m), fmt.Sprintf("%02d", m), minute == m)
+		}
+	}
+	view += `</select>`
+
+	if twelve {
+		view += `<select class="__ponzu-time-meridiem browser-default" data-ponzu-display="true" aria-label="` + htmlText("time.meridiem") + `">` +
+			timeOption("am", htmlText("time.am"), hour < 12) +
+			timeOption("pm", htmlText("time.pm"), hour >= 12) +
+			`</select>`
+	}
+
+	return view + `</div><input type="hidden" class="__ponzu-time-value" name="` + name + `" value="` + html.EscapeString(value) + `" /></div>`
+}
+
+// timeOption returns the markup of an option of a Time's pickers
+func timeOption(value, display string, selected bool) string {
+	if selected {
+		return `<option value="` + value + `" selected>` + display + `</option>`
+	}
+
+	return `<option value="` + value + `">` + display + `</option>`
+}
+
+// timeScript returns the script which composes the "HH:MM" value of every Time
+// of the page when its pickers change, including those added by
+// RepeatController. A time without an hour is unset, and one without a minute
+// is on the hour.
+func timeScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuTime) {
+			return;
+		}
+		window.__ponzuTime = true;
+
+		$(document).on('change', '.__ponzu-time-hour, .__ponzu-time-minute, .__ponzu-time-meridiem', function() {
+			var field = $(this).closest('.__ponzu-time'),
+				stored = field.find('.__ponzu-time-value'),
+				hour = field.find('.__ponzu-time-hour').val(),
+				minute = field.find('.__ponzu-time-minute').val() || '00';
+
+			if (!hour) {
+				stored.val('').trigger('change');
+				return;
+			}
+
+			var h = Number(hour);
+			if (field.attr('data-clock') === '12h') {
+				h = h % 12 + (field.find('.__ponzu-time-meridiem').val() === 'pm' ? 12 : 0);
+			}
+
+			stored.val((h < 10 ? '0' : '') + String(h) + ':' + minute).trigger('change');
+		});
+	});
+</script>
+`
+}
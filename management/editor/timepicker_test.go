@@ -0,0 +1,85 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+type testShop struct {
+	Opens string   `json:"opens"`
+	Slots []string `json:"slots"`
+}
+
+func TestTime(t *testing.T) {
+	view := string(Time("Opens", &testShop{Opens: "17:05"}, map[string]string{"label": "Opens", "step": "15"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	for _, s := range []string{
+		`<div class="__ponzu-time opens input-field col s12" data-clock="24h">`,
+		`<label class="active" for="field-opens">Opens</label>`,
+		`<option value="17" selected>17</option>`,
+		`<option value="05" selected>05</option>`,
+		`<option value="15">15</option>`,
+		`name="opens" value="17:05"`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	minutes := view[strings.Index(view, "__ponzu-time-minute"):]
+	if strings.Contains(minutes, `<option value="10">`) || strings.Contains(view, "meridiem") {
+		t.Errorf("Expected only the minutes of the step, on a 24 hour clock, got: %s", view)
+	}
+
+	view = string(Time("Opens", &testShop{Opens: "00:30"}, map[string]string{"clock": "12h"}))
+	for _, s := range []string{
+		`data-clock="12h"`,
+		`<option value="12" selected>12</option>`,
+		`<option value="am" selected>AM</option>`,
+		`name="opens" value="00:30"`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	view = string(Time("Opens", &testShop{Opens: "13:00"}, map[string]string{"clock": "12h"}))
+	if !strings.Contains(view, `<option value="01" selected>1</option>`) || !strings.Contains(view, `<option value="pm" selected>PM</option>`) {
+		t.Errorf("Expected 13:00 as 1 PM, got: %s", view)
+	}
+}
+
+func TestTimeUnset(t *testing.T) {
+	for _, opens := range []string{"", "5pm"} {
+		view := string(Time("Opens", &testShop{Opens: opens}, map[string]string{}))
+
+		if strings.Count(view, `<option value="" selected>--</option>`) != 2 || strings.Count(view, " selected") != 2 {
+			t.Errorf("%q: expected no default time, got: %s", opens, view)
+		}
+
+		if !strings.Contains(view, `name="opens" value=""`) {
+			t.Errorf("%q: expected an empty value, got: %s", opens, view)
+		}
+	}
+}
+
+func TestTimeRepeater(t *testing.T) {
+	view := string(TimeRepeater("Slots", &testShop{Slots: []string{"09:00", "13:30"}}, map[string]string{"label": "Slots"}))
+
+	for _, s := range []string{
+		`__ponzu-time-repeat slots"`,
+		`<label class="active" for="field-slots-0">Slots</label>`,
+		`name="slots.0" value="09:00"`,
+		`name="slots.1" value="13:30"`,
+		`input.__ponzu-time-value`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	if strings.Count(view, "<label") != 1 {
+		t.Errorf("Expected only the first time to be labeled, got: %s", view)
+	}
+}
@@ -0,0 +1,453 @@
+package editor
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// TimezoneSelect returns the []byte of a searchable <select> HTML element with
+// an <option> for each of the IANA time zones in Timezones, plus a label. The
+// stored zone is pre-selected, and when the field is empty the server's local
+// time zone is selected instead.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func TimezoneSelect(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("TimezoneSelect", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+	if value == "" {
+		value = serverTimezone()
+	}
+
+	// add the browser-default class, without changing attrs
+	selAttrs := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		selAttrs[k] = v
+	}
+
+	if selAttrs["class"] != "" {
+		selAttrs["class"] += " browser-default"
+	} else {
+		selAttrs["class"] = "browser-default"
+	}
+
+	sel := NewElement("select", attrs["label"], fieldName, p, selAttrs)
+	var opts []*Element
+
+	zones := Timezones
+	if !hasString(zones, value) {
+		// keep a stored zone which isn't in the built-in set so it isn't lost
+		zones = append([]string{value}, zones...)
+	}
+
+	for _, zone := range zones {
+		optAttrs := map[string]string{"value": zone}
+		if zone == value {
+			optAttrs["selected"] = "true"
+		}
+
+		opts = append(opts, &Element{
+			TagName: "option",
+			Attrs:   optAttrs,
+			Data:    zone,
+			ViewBuf: &bytes.Buffer{},
+		})
+	}
+
+	html := &bytes.Buffer{}
+	_, err := html.WriteString(`<div class="__ponzu-timezone ` + name + `">` +
+		`<div class="input-field col s6">` +
+		`<input type="text" class="__ponzu-timezone-search" placeholder="` + htmlText("timezone.search") + `" />` +
+		`</div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to TimezoneSelect buffer")
+		return nil
+	}
+
+	_, err = html.Write(DOMElementWithChildrenSelect(sel, opts))
+	if err != nil {
+		log.Println("Error writing DOMElementWithChildrenSelect to TimezoneSelect buffer")
+		return nil
+	}
+
+	script := `</div>
+	<script>
+		$(function() {
+			var tz = $('.__ponzu-timezone.` + selectorClass(name) + `'),
+				search = tz.find('.__ponzu-timezone-search'),
+				sel = tz.find('select');
+
+			search.on('input', function(e) {
+				var q = search.val().toLowerCase().replace(/ /g, '_');
+
+				sel.find('option').each(function(i, opt) {
+					var $opt = $(opt);
+					$opt.toggle($opt.is(':selected') || $opt.val().toLowerCase().indexOf(q) !== -1);
+				});
+			});
+		});
+	</script>`
+
+	_, err = html.WriteString(script)
+	if err != nil {
+		log.Println("Error writing HTML string to TimezoneSelect buffer")
+		return nil
+	}
+
+	return html.Bytes()
+}
+
+// serverTimezone returns the IANA name of the server's local time zone,
+// falling back to UTC when it cannot be determined
+func serverTimezone() string {
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" {
+		return tz
+	}
+
+	if name := time.Local.String(); name != "Local" {
+		return name
+	}
+
+	link, err := os.Readlink("/etc/localtime")
+	if err == nil {
+		if i := strings.Index(link, "zoneinfo/"); i != -1 {
+			return link[i+len("zoneinfo/"):]
+		}
+	}
+
+	return "UTC"
+}
+
+func hasString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Timezones is the built-in, ordered set of IANA time zone names offered by
+// TimezoneSelect. It contains UTC followed by the canonical zones of the IANA
+// tz database (zone1970.tab) in alphabetical order.
+var Timezones = []string{
+	"UTC",
+	"Africa/Abidjan",
+	"Africa/Algiers",
+	"Africa/Bissau",
+	"Africa/Cairo",
+	"Africa/Casablanca",
+	"Africa/Ceuta",
+	"Africa/El_Aaiun",
+	"Africa/Johannesburg",
+	"Africa/Juba",
+	"Africa/Khartoum",
+	"Africa/Lagos",
+	"Africa/Maputo",
+	"Africa/Monrovia",
+	"Africa/Nairobi",
+	"Africa/Ndjamena",
+	"Africa/Sao_Tome",
+	"Africa/Tripoli",
+	"Africa/Tunis",
+	"Africa/Windhoek",
+	"America/Adak",
+	"America/Anchorage",
+	"America/Araguaina",
+	"America/Argentina/Buenos_Aires",
+	"America/Argentina/Catamarca",
+	"America/Argentina/Cordoba",
+	"America/Argentina/Jujuy",
+	"America/Argentina/La_Rioja",
+	"America/Argentina/Mendoza",
+	"America/Argentina/Rio_Gallegos",
+	"America/Argentina/Salta",
+	"America/Argentina/San_Juan",
+	"America/Argentina/San_Luis",
+	"America/Argentina/Tucuman",
+	"America/Argentina/Ushuaia",
+	"America/Asuncion",
+	"America/Bahia",
+	"America/Bahia_Banderas",
+	"America/Barbados",
+	"America/Belem",
+	"America/Belize",
+	"America/Boa_Vista",
+	"America/Bogota",
+	"America/Boise",
+	"America/Cambridge_Bay",
+	"America/Campo_Grande",
+	"America/Cancun",
+	"America/Caracas",
+	"America/Cayenne",
+	"America/Chicago",
+	"America/Chihuahua",
+	"America/Ciudad_Juarez",
+	"America/Costa_Rica",
+	"America/Coyhaique",
+	"America/Cuiaba",
+	"America/Danmarkshavn",
+	"America/Dawson",
+	"America/Dawson_Creek",
+	"America/Denver",
+	"America/Detroit",
+	"America/Edmonton",
+	"America/Eirunepe",
+	"America/El_Salvador",
+	"America/Fort_Nelson",
+	"America/Fortaleza",
+	"America/Glace_Bay",
+	"America/Goose_Bay",
+	"America/Grand_Turk",
+	"America/Guatemala",
+	"America/Guayaquil",
+	"America/Guyana",
+	"America/Halifax",
+	"America/Havana",
+	"America/Hermosillo",
+	"America/Indiana/Indianapolis",
+	"America/Indiana/Knox",
+	"America/Indiana/Marengo",
+	"America/Indiana/Petersburg",
+	"America/Indiana/Tell_City",
+	"America/Indiana/Vevay",
+	"America/Indiana/Vincennes",
+	"America/Indiana/Winamac",
+	"America/Inuvik",
+	"America/Iqaluit",
+	"America/Jamaica",
+	"America/Juneau",
+	"America/Kentucky/Louisville",
+	"America/Kentucky/Monticello",
+	"America/La_Paz",
+	"America/Lima",
+	"America/Los_Angeles",
+	"America/Maceio",
+	"America/Managua",
+	"America/Manaus",
+	"America/Martinique",
+	"America/Matamoros",
+	"America/Mazatlan",
+	"America/Menominee",
+	"America/Merida",
+	"America/Metlakatla",
+	"America/Mexico_City",
+	"America/Miquelon",
+	"America/Moncton",
+	"America/Monterrey",
+	"America/Montevideo",
+	"America/New_York",
+	"America/Nome",
+	"America/Noronha",
+	"America/North_Dakota/Beulah",
+	"America/North_Dakota/Center",
+	"America/North_Dakota/New_Salem",
+	"America/Nuuk",
+	"America/Ojinaga",
+	"America/Panama",
+	"America/Paramaribo",
+	"America/Phoenix",
+	"America/Port-au-Prince",
+	"America/Porto_Velho",
+	"America/Puerto_Rico",
+	"America/Punta_Arenas",
+	"America/Rankin_Inlet",
+	"America/Recife",
+	"America/Regina",
+	"America/Resolute",
+	"America/Rio_Branco",
+	"America/Santarem",
+	"America/Santiago",
+	"America/Santo_Domingo",
+	"America/Sao_Paulo",
+	"America/Scoresbysund",
+	"America/Sitka",
+	"America/St_Johns",
+	"America/Swift_Current",
+	"America/Tegucigalpa",
+	"America/Thule",
+	"America/Tijuana",
+	"America/Toronto",
+	"America/Vancouver",
+	"America/Whitehorse",
+	"America/Winnipeg",
+	"America/Yakutat",
+	"Antarctica/Casey",
+	"Antarctica/Davis",
+	"Antarctica/Macquarie",
+	"Antarctica/Mawson",
+	"Antarctica/Palmer",
+	"Antarctica/Rothera",
+	"Antarctica/Troll",
+	"Antarctica/Vostok",
+	"Asia/Almaty",
+	"Asia/Amman",
+	"Asia/Anadyr",
+	"Asia/Aqtau",
+	"Asia/Aqtobe",
+	"Asia/Ashgabat",
+	"Asia/Atyrau",
+	"Asia/Baghdad",
+	"Asia/Baku",
+	"Asia/Bangkok",
+	"Asia/Barnaul",
+	"Asia/Beirut",
+	"Asia/Bishkek",
+	"Asia/Chita",
+	"Asia/Colombo",
+	"Asia/Damascus",
+	"Asia/Dhaka",
+	"Asia/Dili",
+	"Asia/Dubai",
+	"Asia/Dushanbe",
+	"Asia/Famagusta",
+	"Asia/Gaza",
+	"Asia/Hebron",
+	"Asia/Ho_Chi_Minh",
+	"Asia/Hong_Kong",
+	"Asia/Hovd",
+	"Asia/Irkutsk",
+	"Asia/Jakarta",
+	"Asia/Jayapura",
+	"Asia/Jerusalem",
+	"Asia/Kabul",
+	"Asia/Kamchatka",
+	"Asia/Karachi",
+	"Asia/Kathmandu",
+	"Asia/Khandyga",
+	"Asia/Kolkata",
+	"Asia/Krasnoyarsk",
+	"Asia/Kuching",
+	"Asia/Macau",
+	"Asia/Magadan",
+	"Asia/Makassar",
+	"Asia/Manila",
+	"Asia/Nicosia",
+	"Asia/Novokuznetsk",
+	"Asia/Novosibirsk",
+	"Asia/Omsk",
+	"Asia/Oral",
+	"Asia/Pontianak",
+	"Asia/Pyongyang",
+	"Asia/Qatar",
+	"Asia/Qostanay",
+	"Asia/Qyzylorda",
+	"Asia/Riyadh",
+	"Asia/Sakhalin",
+	"Asia/Samarkand",
+	"Asia/Seoul",
+	"Asia/Shanghai",
+	"Asia/Singapore",
+	"Asia/Srednekolymsk",
+	"Asia/Taipei",
+	"Asia/Tashkent",
+	"Asia/Tbilisi",
+	"Asia/Tehran",
+	"Asia/Thimphu",
+	"Asia/Tokyo",
+	"Asia/Tomsk",
+	"Asia/Ulaanbaatar",
+	"Asia/Urumqi",
+	"Asia/Ust-Nera",
+	"Asia/Vladivostok",
+	"Asia/Yakutsk",
+	"Asia/Yangon",
+	"Asia/Yekaterinburg",
+	"Asia/Yerevan",
+	"Atlantic/Azores",
+	"Atlantic/Bermuda",
+	"Atlantic/Canary",
+	"Atlantic/Cape_Verde",
+	"Atlantic/Faroe",
+	"Atlantic/Madeira",
+	"Atlantic/South_Georgia",
+	"Atlantic/Stanley",
+	"Australia/Adelaide",
+	"Australia/Brisbane",
+	"Australia/Broken_Hill",
+	"Australia/Darwin",
+	"Australia/Eucla",
+	"Australia/Hobart",
+	"Australia/Lindeman",
+	"Australia/Lord_Howe",
+	"Australia/Melbourne",
+	"Australia/Perth",
+	"Australia/Sydney",
+	"Europe/Andorra",
+	"Europe/Astrakhan",
+	"Europe/Athens",
+	"Europe/Belgrade",
+	"Europe/Berlin",
+	"Europe/Brussels",
+	"Europe/Bucharest",
+	"Europe/Budapest",
+	"Europe/Chisinau",
+	"Europe/Dublin",
+	"Europe/Gibraltar",
+	"Europe/Helsinki",
+	"Europe/Istanbul",
+	"Europe/Kaliningrad",
+	"Europe/Kirov",
+	"Europe/Kyiv",
+	"Europe/Lisbon",
+	"Europe/London",
+	"Europe/Madrid",
+	"Europe/Malta",
+	"Europe/Minsk",
+	"Europe/Moscow",
+	"Europe/Paris",
+	"Europe/Prague",
+	"Europe/Riga",
+	"Europe/Rome",
+	"Europe/Samara",
+	"Europe/Saratov",
+	"Europe/Simferopol",
+	"Europe/Sofia",
+	"Europe/Tallinn",
+	"Europe/Tirane",
+	"Europe/Ulyanovsk",
+	"Europe/Vienna",
+	"Europe/Vilnius",
+	"Europe/Volgograd",
+	"Europe/Warsaw",
+	"Europe/Zurich",
+	"Indian/Chagos",
+	"Indian/Maldives",
+	"Indian/Mauritius",
+	"Pacific/Apia",
+	"Pacific/Auckland",
+	"Pacific/Bougainville",
+	"Pacific/Chatham",
+	"Pacific/Easter",
+	"Pacific/Efate",
+	"Pacific/Fakaofo",
+	"Pacific/Fiji",
+	"Pacific/Galapagos",
+	"Pacific/Gambier",
+	"Pacific/Guadalcanal",
+	"Pacific/Guam",
+	"Pacific/Honolulu",
+	"Pacific/Kanton",
+	"Pacific/Kiritimati",
+	"Pacific/Kosrae",
+	"Pacific/Kwajalein",
+	"Pacific/Marquesas",
+	"Pacific/Nauru",
+	"Pacific/Niue",
+	"Pacific/Norfolk",
+	"Pacific/Noumea",
+	"Pacific/Pago_Pago",
+	"Pacific/Palau",
+	"Pacific/Pitcairn",
+	"Pacific/Port_Moresby",
+	"Pacific/Rarotonga",
+	"Pacific/Tahiti",
+	"Pacific/Tarawa",
+	"Pacific/Tongatapu",
+}
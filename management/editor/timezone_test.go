@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testAccount struct {
+	Zone string `json:"zone"`
+}
+
+func TestTimezonesLoad(t *testing.T) {
+	seen := make(map[string]bool, len(Timezones))
+	for i, zone := range Timezones {
+		if zone == "" {
+			t.Errorf("Timezones[%d] is empty", i)
+			continue
+		}
+
+		if seen[zone] {
+			t.Errorf("Timezones[%d] %s is repeated", i, zone)
+		}
+		seen[zone] = true
+
+		if _, err := time.LoadLocation(zone); err != nil {
+			t.Errorf("Timezones[%d] %s doesn't load: %v", i, zone, err)
+		}
+
+		if i > 1 && zone < Timezones[i-1] {
+			t.Errorf("Timezones[%d] %s is out of order after %s", i, zone, Timezones[i-1])
+		}
+	}
+
+	if Timezones[0] != "UTC" {
+		t.Errorf("Expected UTC first, got %s", Timezones[0])
+	}
+}
+
+func TestTimezoneSelect(t *testing.T) {
+	attrs := map[string]string{"label": "Zone"}
+	view := string(TimezoneSelect("Zone", &testAccount{Zone: "Europe/Paris"}, attrs))
+
+	if strings.Count(view, " selected") != 1 || !strings.Contains(view, `<option selected value="Europe/Paris"`) {
+		t.Errorf("Expected only the stored zone to be selected, got: %s", view)
+	}
+
+	if strings.Contains(view, `value=""`) || strings.Contains(view, `value="TZ"`) {
+		t.Errorf("Expected no blank or bogus options, got: %s", view)
+	}
+
+	if strings.Count(view, "<option") != len(Timezones) {
+		t.Errorf("Expected an option per zone, got %d", strings.Count(view, "<option"))
+	}
+
+	if _, ok := attrs["class"]; ok {
+		t.Errorf("Expected attrs not to be changed, got: %v", attrs)
+	}
+
+	view = string(TimezoneSelect("Zone", &testAccount{Zone: "Mars/Olympus_Mons"}, nil))
+	if !strings.Contains(view, `<option selected value="Mars/Olympus_Mons"`) || !strings.Contains(view, `class="browser-default"`) {
+		t.Errorf("Expected an unknown stored zone to be kept, got: %s", view)
+	}
+}
@@ -0,0 +1,57 @@
+package editor
+
+import (
+	"html"
+	"strconv"
+)
+
+// Toggle returns the []byte of a switch for a boolean field, which stores
+// "true" when it is on and "false" when it is off, or attrs["on"] and
+// attrs["off"] when they are set, e.g. "yes" and "no" for a string field. The
+// switch is on when the stored value is the on value. A hidden input holding
+// the off value precedes the switch under the same name, so that a switch
+// which is off, and isn't submitted like any unchecked checkbox, still submits
+// its off value, while one which is on submits its on value last, which is the
+// value the field is decoded from.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Toggle(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Toggle", fieldName, attrs)
+
+	name := TagNameFromStructField(fieldName, p)
+	value := ValueFromStructField(fieldName, p)
+	id := fieldID(name)
+
+	on, off := attrs["on"], attrs["off"]
+	if on == "" {
+		on = "true"
+	}
+	if off == "" {
+		off = "false"
+	}
+
+	checked := value == on
+	if b, err := strconv.ParseBool(value); err == nil && on == "true" && off == "false" {
+		checked = b
+	}
+
+	var check string
+	if checked {
+		check = " checked"
+	}
+
+	view := `<div class="__ponzu-toggle ` + name + ` input-field col s12">`
+	if attrs["label"] != "" {
+		view += `<label class="active" for="` + id + `">` + attrs["label"] + `</label>`
+	}
+
+	view += `<div class="switch">` +
+		`<input type="hidden" name="` + name + `" value="` + html.EscapeString(off) + `" />` +
+		`<label>` + htmlText("toggle.off") +
+		`<input type="checkbox" id="` + id + `" name="` + name + `" value="` + html.EscapeString(on) + `"` + check + ` />` +
+		`<span class="lever"></span>` + htmlText("toggle.on") + `</label>` +
+		`</div>` + helpText(attrs) + `</div>`
+
+	return []byte(view)
+}
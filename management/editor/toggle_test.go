@@ -0,0 +1,73 @@
+package editor
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testFlags struct {
+	Public bool   `json:"public"`
+	Answer string `json:"answer"`
+}
+
+func TestToggle(t *testing.T) {
+	view := string(Toggle("Public", &testFlags{Public: true}, map[string]string{"label": "Public"}))
+	for _, s := range []string{
+		`<label class="active" for="field-public">Public</label>`,
+		`<input type="hidden" name="public" value="false" />`,
+		`<input type="checkbox" id="field-public" name="public" value="true" checked />`,
+	} {
+		if !strings.Contains(view, s) {
+			t.Errorf("Expected %s, got: %s", s, view)
+		}
+	}
+
+	// the off value comes first, so that the value decoded is the last one
+	if strings.Index(view, `value="false"`) > strings.Index(view, `value="true"`) {
+		t.Errorf("Expected the hidden off value before the switch, got: %s", view)
+	}
+
+	view = string(Toggle("Public", &testFlags{}, map[string]string{}))
+	if strings.Contains(view, "checked") {
+		t.Errorf("Expected the switch to be off, got: %s", view)
+	}
+
+	attrs := map[string]string{"on": "yes", "off": `"no"`}
+	view = string(Toggle("Answer", &testFlags{Answer: "yes"}, attrs))
+	if !strings.Contains(view, `value="&#34;no&#34;"`) || !strings.Contains(view, `value="yes" checked`) {
+		t.Errorf("Expected the escaped on and off values, with the switch on, got: %s", view)
+	}
+
+	view = string(Toggle("Answer", &testFlags{Answer: "true"}, attrs))
+	if strings.Contains(view, "checked") {
+		t.Errorf("Expected the switch to be off unless the value is the on value, got: %s", view)
+	}
+}
+
+// rxToggleInput matches the inputs of a Toggle, with their type, name, value
+// and whether they are checked
+var rxToggleInput = regexp.MustCompile(`<input type="(\w+)"(?: id="[^"]*")? name="([^"]*)" value="([^"]*)"( checked)? />`)
+
+func TestToggleRoundTrip(t *testing.T) {
+	for _, public := range []bool{false, true} {
+		view := string(Toggle("Public", &testFlags{Public: public}, map[string]string{}))
+
+		// submit the inputs as a browser would, leaving out an unchecked
+		// checkbox, and decode the field from the last value, as the admin does
+		form := url.Values{}
+		for _, m := range rxToggleInput.FindAllStringSubmatch(view, -1) {
+			if m[1] == "checkbox" && m[4] == "" {
+				continue
+			}
+			form.Add(m[2], m[3])
+		}
+
+		vals := form["public"]
+		if len(vals) == 0 || vals[len(vals)-1] != strconv.FormatBool(public) {
+			t.Errorf("Expected %v to round-trip, got: %v", public, vals)
+		}
+	}
+}
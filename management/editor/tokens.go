@@ -0,0 +1,267 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+)
+
+// TokenInput returns the []byte of a token input for a string slice field,
+// such as the categories of a post. Each value is shown as a removable token,
+// and new tokens are added by typing into the input, which suggests matching
+// values from suggestions. Pressing Enter or a comma adds the typed value.
+// Values which are not among the suggestions are only accepted when
+// attrs["allowNew"] is "true". Duplicate values are ignored, and the values
+// are submitted in order as "name.0", "name.1", and so on.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func TokenInput(fieldName string, p interface{}, suggestions []string, attrs map[string]string) []byte {
+	checkAttrs("TokenInput", fieldName, attrs)
+
+	return tokenInput(fieldName, p, attrs, tokenOptions{
+		suggestions: suggestions,
+		allowNew:    attrs["allowNew"] == "true",
+		placeholder: text("tokens.placeholder"),
+	})
+}
+
+// Tags returns the []byte of a tag input for a string slice field, which shows
+// each stored tag as a removable chip. New tags are added by typing and pressing
+// Enter or a comma. Tags are trimmed, empty tags are ignored, and tags which
+// differ only by case from an existing one are ignored. The tags are submitted
+// in order as "name.0", "name.1", and so on, like the values of InputRepeater.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func Tags(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("Tags", fieldName, attrs)
+
+	return tokenInput(fieldName, p, attrs, tokenOptions{
+		class:       "__ponzu-tags",
+		allowNew:    true,
+		foldCase:    true,
+		placeholder: text("tags.placeholder"),
+	})
+}
+
+// tokenOptions configure the token input rendered by tokenInput
+type tokenOptions struct {
+	// class is added to the class of the token input's container
+	class string
+
+	// suggestions are offered as the editor types, and unless allowNew is
+	// true, are the only values which can be added
+	suggestions []string
+	allowNew    bool
+
+	// foldCase ignores values which differ only by case from an existing one
+	foldCase bool
+
+	// placeholder is shown in the entry unless attrs["placeholder"] is set
+	placeholder string
+}
+
+// tokenInput returns the []byte of the token input used by TokenInput and Tags
+func tokenInput(fieldName string, p interface{}, attrs map[string]string, opts tokenOptions) []byte {
+	name := TagNameFromStructField(fieldName, p)
+
+	var values []string
+	for _, v := range ValuesFromStructField(fieldName, p) {
+		if opts.foldCase {
+			v = strings.TrimSpace(v)
+		}
+
+		if v != "" && !hasToken(values, v, opts.foldCase) {
+			values = append(values, v)
+		}
+	}
+
+	class := "__ponzu-tokens " + name
+	if opts.class != "" {
+		class = "__ponzu-tokens " + opts.class + " " + name
+	}
+
+	view := &bytes.Buffer{}
+	_, err := view.WriteString(`<div class="` + class + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to TokenInput buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to TokenInput buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`<div class="__ponzu-token-list">`)
+	if err != nil {
+		log.Println("Error writing HTML string to TokenInput buffer")
+		return nil
+	}
+
+	for i, v := range values {
+		_, err = view.WriteString(tokenChip(fmt.Sprintf("%s.%d", name, i), v))
+		if err != nil {
+			log.Println("Error writing HTML string to TokenInput buffer")
+			return nil
+		}
+	}
+
+	placeholder := attrs["placeholder"]
+	if placeholder == "" {
+		placeholder = opts.placeholder
+	}
+
+	_, err = view.WriteString(`</div>` +
+		`<input type="text" class="__ponzu-token-entry" autocomplete="off" placeholder="` + html.EscapeString(placeholder) + `" />` +
+		`<ul class="__ponzu-token-suggestions collection"></ul>` +
+		`<span class="__ponzu-token-error red-text"></span>` +
+		`</div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to TokenInput buffer")
+		return nil
+	}
+
+	list, err := json.Marshal(opts.suggestions)
+	if err != nil || opts.suggestions == nil {
+		list = []byte("[]")
+	}
+
+	script := `
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-tokens.` + selectorClass(name) + `'),
+				tokens = scope.find('.__ponzu-token-list'),
+				entry = scope.find('.__ponzu-token-entry'),
+				menu = scope.find('.__ponzu-token-suggestions'),
+				error = scope.find('.__ponzu-token-error'),
+				name = ` + jsString(name) + `,
+				suggestions = ` + string(list) + `,
+				allowNew = ` + fmt.Sprintf("%t", opts.allowNew) + `,
+				foldCase = ` + fmt.Sprintf("%t", opts.foldCase) + `;
+
+			var current = function() {
+				return tokens.find('input[type=hidden]').map(function() {
+					return this.value;
+				}).get();
+			}
+
+			// has reports whether value is one of values, ignoring case if
+			// foldCase is true
+			var has = function(values, value) {
+				for (var i = 0; i < values.length; i++) {
+					if (values[i] === value || (foldCase && values[i].toLowerCase() === value.toLowerCase())) {
+						return true;
+					}
+				}
+
+				return false;
+			}
+
+			var reindex = function() {
+				tokens.find('input[type=hidden]').each(function(i, el) {
+					$(el).attr('name', name + '.' + String(i));
+				});
+			}
+
+			var add = function(value) {
+				value = $.trim(value);
+				if (value === '' || has(current(), value)) {
+					entry.val('');
+					return;
+				}
+
+				if (!allowNew && $.inArray(value, suggestions) === -1) {
+					error.text(` + jsString(text("tokens.notSuggested")) + `);
+					return;
+				}
+
+				var chip = $('<div class="chip"></div>').text(value)
+					.append($('<i class="material-icons close">close</i>').attr('title', ` + jsString(text("tokens.remove")) + `))
+					.append($('<input type="hidden" />').val(value));
+
+				tokens.append(chip);
+				reindex();
+				entry.val('');
+				error.text('');
+				menu.empty();
+			}
+
+			var suggest = function() {
+				var q = $.trim(entry.val()).toLowerCase(),
+					chosen = current();
+
+				menu.empty();
+				if (q === '') {
+					return;
+				}
+
+				$.each(suggestions, function(i, s) {
+					if (s.toLowerCase().indexOf(q) === -1 || has(chosen, s)) {
+						return;
+					}
+
+					menu.append($('<li class="collection-item"></li>').text(s));
+				});
+			}
+
+			entry.on('input', suggest);
+
+			entry.on('keydown', function(e) {
+				// Enter or comma adds the typed value, Backspace on an empty
+				// entry removes the last token
+				if (e.which === 13 || e.which === 188) {
+					e.preventDefault();
+					add(entry.val());
+				} else if (e.which === 8 && entry.val() === '') {
+					tokens.find('.chip').last().remove();
+					reindex();
+				}
+			});
+
+			entry.on('blur', function() {
+				// leave time for a click on a suggestion to register
+				setTimeout(function() { menu.empty(); }, 200);
+			});
+
+			menu.on('mousedown', 'li', function(e) {
+				e.preventDefault();
+				add($(this).text());
+			});
+
+			tokens.on('click', '.close', function(e) {
+				e.preventDefault();
+				$(this).closest('.chip').remove();
+				reindex();
+			});
+		});
+	</script>`
+
+	return append(view.Bytes(), script...)
+}
+
+// hasToken reports whether value is one of values, ignoring case if foldCase is
+// true
+func hasToken(values []string, value string, foldCase bool) bool {
+	for _, v := range values {
+		if v == value || (foldCase && strings.EqualFold(v, value)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tokenChip returns the markup of a single token named name holding value
+func tokenChip(name, value string) string {
+	return `<div class="chip">` + html.EscapeString(value) +
+		`<i class="material-icons close" title="` + htmlText("tokens.remove") + `">close</i>` +
+		`<input type="hidden" name="` + name + `" value="` + html.EscapeString(value) + `" /></div>`
+}
@@ -0,0 +1,41 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	p := &testContact{Links: []string{" Go ", "", "go", "<b>web</b>", "GO", "Web"}}
+
+	view := string(Tags("Links", p, map[string]string{"label": "Tags"}))
+	view = view[:strings.Index(view, "<script>")]
+
+	if strings.Count(view, `<div class="chip">`) != 3 {
+		t.Errorf("Expected 3 chips, got: %s", view)
+	}
+
+	for _, chip := range []string{
+		`<input type="hidden" name="links.0" value="Go" />`,
+		`<input type="hidden" name="links.1" value="&lt;b&gt;web&lt;/b&gt;" />`,
+		`<input type="hidden" name="links.2" value="Web" />`,
+	} {
+		if !strings.Contains(view, chip) {
+			t.Errorf("Expected %s, got: %s", chip, view)
+		}
+	}
+
+	if !strings.Contains(view, `class="__ponzu-tokens __ponzu-tags links input-field col s12"`) {
+		t.Errorf("Expected the tags container, got: %s", view)
+	}
+}
+
+func TestTokenInputKeepsCase(t *testing.T) {
+	p := &testContact{Links: []string{"Go", "go"}}
+
+	view := string(TokenInput("Links", p, nil, map[string]string{}))
+	view = view[:strings.Index(view, "<script>")]
+	if strings.Count(view, `<div class="chip">`) != 2 {
+		t.Errorf("Expected values differing by case to be kept, got: %s", view)
+	}
+}
@@ -0,0 +1,99 @@
+package editor
+
+// repeatUnique returns the data attribute which requires the values of a
+// repeater to be different from each other, when attrs["unique"] is "true", or
+// "ignorecase" to compare them regardless of case. Repeated values are flagged
+// as invalid, and block the editor from being saved, while options chosen in
+// one select of a repeater are disabled in the others.
+func repeatUnique(attrs map[string]string) string {
+	switch attrs["unique"] {
+	case "true":
+		return ` data-unique="true"`
+	case "ignorecase":
+		return ` data-unique="ignorecase"`
+	}
+
+	return ""
+}
+
+// uniqueScript returns the script which checks the values of every repeater of
+// the page with a data-unique attribute, see repeatUnique
+func uniqueScript() string {
+	return `
+<script>
+	$(function() {
+		if (window.__ponzuUnique) {
+			return;
+		}
+		window.__ponzuUnique = true;
+
+		var message = ` + jsString(text("repeat.unique")) + `;
+
+		// key returns the value compared for uniqueness in the repeater scope
+		var key = function(scope, value) {
+			value = $.trim(value || '');
+			return scope.attr('data-unique') === 'ignorecase' ? value.toLowerCase() : value;
+		}
+
+		// check flags the repeated values of the repeater scope, disables the
+		// options already chosen by its other selects, and returns whether any
+		// value is repeated
+		var check = function(scope) {
+			var els = scope.find('input, select').filter(function() {
+					return this.name && this.type !== 'hidden';
+				}),
+				counts = {},
+				repeated = false;
+
+			els.each(function(i, el) {
+				var k = key(scope, $(el).val());
+				if (k !== '') {
+					counts[k] = (counts[k] || 0) + 1;
+				}
+			});
+
+			els.each(function(i, el) {
+				var k = key(scope, $(el).val()),
+					invalid = k !== '' && counts[k] > 1;
+
+				el.setCustomValidity(invalid ? message : '');
+				$(el).toggleClass('invalid', invalid);
+				repeated = repeated || invalid;
+			});
+
+			// the call to action has no value attribute, and "None" is empty
+			els.filter('select').each(function(i, sel) {
+				var own = key(scope, $(sel).val());
+
+				$(sel).find('option[value]').each(function(j, opt) {
+					var k = key(scope, opt.value);
+					opt.disabled = k !== '' && k !== own && counts[k] > 0;
+				});
+			});
+
+			return repeated;
+		}
+
+		$(document).on('change focusout', '.__ponzu-repeat[data-unique] input, .__ponzu-repeat[data-unique] select', function(e) {
+			check($(e.target).closest('.__ponzu-repeat'));
+		});
+
+		$(document).on('submit', 'form', function(e) {
+			var repeated = false;
+			$(this).find('.__ponzu-repeat[data-unique]').each(function() {
+				repeated = check($(this)) || repeated;
+			});
+
+			if (repeated) {
+				e.preventDefault();
+				e.stopImmediatePropagation();
+			}
+		});
+
+		$('.__ponzu-repeat[data-unique]').each(function() {
+			check($(this));
+		});
+	});
+</script>
+`
+}
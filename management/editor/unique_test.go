@@ -0,0 +1,31 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepeaterUnique(t *testing.T) {
+	p := &testContact{Links: []string{"a", "b"}}
+
+	view := string(InputRepeater("Links", p, map[string]string{"unique": "true"}))
+	if !strings.Contains(view, `data-unique="true"`) || !strings.Contains(view, "window.__ponzuUnique") {
+		t.Errorf("Expected the repeater to require unique values, got: %s", view)
+	}
+
+	if strings.Count(view, `unique="true"`) != 1 {
+		t.Errorf("Expected the unique attr not to be rendered on the inputs, got: %s", view)
+	}
+
+	view = string(SelectRepeater("Links", p, map[string]string{"unique": "ignorecase"}, map[string]string{"a": "A", "b": "B"}))
+	if !strings.Contains(view, `data-unique="ignorecase"`) {
+		t.Errorf("Expected values to be compared regardless of case, got: %s", view)
+	}
+
+	for _, unique := range []string{"", "false", "yes"} {
+		view = string(InputRepeater("Links", p, map[string]string{"unique": unique}))
+		if strings.Contains(view, "data-unique") || strings.Contains(view, "__ponzuUnique") {
+			t.Errorf("Expected unique %q to be ignored, got: %s", unique, view)
+		}
+	}
+}
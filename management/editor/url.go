@@ -0,0 +1,174 @@
+package editor
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultURLSchemes are the schemes allowed by URL and ValidateURL when none
+// are given
+var DefaultURLSchemes = []string{"http", "https"}
+
+// rxURLScheme matches a leading URL scheme, e.g. "https:" or "mailto:"
+var rxURLScheme = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// URL returns the []byte of an <input type="url"> HTML element with a label.
+// When the field loses focus, a value without a scheme is normalized by
+// prepending "https://", and values which are not valid URLs or which use a
+// scheme other than those allowed are marked invalid. The allowed schemes are
+// set by attrs["schemes"] as a comma separated list, and default to
+// DefaultURLSchemes. Check the same constraints on the server with NormalizeURL
+// and ValidateURL, since client-side checks can be bypassed.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func URL(fieldName string, p interface{}, attrs map[string]string) []byte {
+	checkAttrs("URL", fieldName, attrs)
+
+	schemes := DefaultURLSchemes
+	if attrs["schemes"] != "" {
+		schemes = strings.Split(attrs["schemes"], ",")
+	}
+
+	urlAttrs := make(map[string]string, len(attrs)+2)
+	for k, v := range attrs {
+		if k != "schemes" {
+			urlAttrs[k] = v
+		}
+	}
+	urlAttrs["type"] = "url"
+	urlAttrs["data-ponzu-url"] = strings.ToLower(strings.Join(schemes, ","))
+
+	e := NewElement("input", attrs["label"], fieldName, p, urlAttrs)
+
+	return append(DOMElementSelfClose(e), urlScript()...)
+}
+
+// urlScript returns the script which normalizes and validates every URL field
+// on the page
+func urlScript() []byte {
+	return []byte(`
+<script>
+	$(function() {
+		if (window.__ponzuURL) {
+			return;
+		}
+		window.__ponzuURL = true;
+
+		var check = function(el) {
+			var v = $.trim(el.value);
+			if (v === '') {
+				el.setCustomValidity('');
+				return;
+			}
+
+			// prepend a scheme when there is none, including to "host:port"
+			if (!/^[a-zA-Z][a-zA-Z0-9+.-]*:/.test(v) || /^(localhost|[^:\/]*\.[^:\/]*):\d/i.test(v)) {
+				v = 'https://' + v.replace(/^\/\//, '');
+			}
+			el.value = v;
+
+			var schemes = $(el).attr('data-ponzu-url').split(','),
+				scheme = v.slice(0, v.indexOf(':')).toLowerCase(),
+				message = '';
+
+			if ($.inArray(scheme, schemes) === -1) {
+				message = ` + jsString(text("url.scheme")) + `.replace('{schemes}', schemes.join(':, ') + ':');
+			} else if ((scheme === 'http' || scheme === 'https') && !/^https?:\/\/[^\/\s?#]+/i.test(v)) {
+				message = ` + jsString(text("url.invalid")) + `;
+			}
+
+			el.setCustomValidity(message);
+			$(el).toggleClass('invalid', message !== '');
+		}
+
+		$(document).on('change focusout', 'input[data-ponzu-url]', function(e) {
+			check(e.target);
+		});
+
+		$(document).on('submit', 'form', function(e) {
+			$(this).find('input[data-ponzu-url]').each(function(i, el) {
+				check(el);
+			});
+		});
+	});
+</script>`)
+}
+
+// NormalizeURL trims whitespace from s and prepends "https://" when it does not
+// begin with a scheme, e.g. "example.com/about" becomes
+// "https://example.com/about". An empty s is returned unchanged.
+func NormalizeURL(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+
+	if hasURLScheme(s) {
+		return s
+	}
+
+	return "https://" + strings.TrimPrefix(s, "//")
+}
+
+// hasURLScheme reports whether s begins with a scheme, rather than a host and
+// port such as "localhost:8080"
+func hasURLScheme(s string) bool {
+	m := rxURLScheme.FindString(s)
+	if m == "" {
+		return false
+	}
+
+	// a host such as "example.com" or "localhost" followed by a port
+	host := strings.ToLower(m[:len(m)-1])
+	rest := s[len(m):]
+	port := rest != "" && rest[0] >= '0' && rest[0] <= '9'
+
+	return !(port && (strings.Contains(host, ".") || host == "localhost"))
+}
+
+// ValidateURL returns an error unless s is an absolute URL using one of the
+// allowed schemes, which default to DefaultURLSchemes. http and https URLs must
+// also have a host. Use it to reject URLs such as "javascript:..." which would
+// become an XSS vector when rendered as links.
+func ValidateURL(s string, schemes ...string) error {
+	if len(schemes) == 0 {
+		schemes = DefaultURLSchemes
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %v", s, err)
+	}
+
+	if u.Scheme == "" {
+		return fmt.Errorf("URL %q has no scheme", s)
+	}
+
+	var allowed bool
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, strings.TrimSpace(scheme)) {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return fmt.Errorf("URL %q must use one of the schemes: %s", s, strings.Join(schemes, ", "))
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("URL %q has no host", s)
+		}
+	default:
+		if u.Host == "" && u.Opaque == "" && u.Path == "" {
+			return fmt.Errorf("URL %q is empty after its scheme", s)
+		}
+	}
+
+	return nil
+}
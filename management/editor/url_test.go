@@ -0,0 +1,68 @@
+package editor
+
+import "testing"
+
+func TestNormalizeURL(t *testing.T) {
+	cases := map[string]string{
+		"":                        "",
+		"  ":                      "",
+		"example.com":             "https://example.com",
+		" example.com/about?a=b ": "https://example.com/about?a=b",
+		"//example.com/x":         "https://example.com/x",
+		"localhost:8080/admin":    "https://localhost:8080/admin",
+		"example.com:8443":        "https://example.com:8443",
+		"http://example.com":      "http://example.com",
+		"HTTPS://example.com":     "HTTPS://example.com",
+		"mailto:jane@example.com": "mailto:jane@example.com",
+		"tel:5551234":             "tel:5551234",
+		"javascript:alert(1)":     "javascript:alert(1)",
+	}
+
+	for in, expected := range cases {
+		if got := NormalizeURL(in); got != expected {
+			t.Errorf("NormalizeURL(%q): expected %q, got: %q", in, expected, got)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	cases := []struct {
+		url     string
+		schemes []string
+		valid   bool
+	}{
+		{"https://example.com", nil, true},
+		{"http://example.com/a?b=c#d", nil, true},
+		{"HTTPS://example.com", nil, true},
+		{"javascript:alert(1)", nil, false},
+		{"JavaScript:alert(1)", []string{"http", "https"}, false},
+		{"data:text/html;base64,PHNjcmlwdD4=", nil, false},
+		{"example.com", nil, false},
+		{"https://", nil, false},
+		{"http:///path", nil, false},
+		{"mailto:jane@example.com", nil, false},
+		{"mailto:jane@example.com", []string{"mailto"}, true},
+		{"https://example.com", []string{"mailto"}, false},
+		{"ftp://example.com/file", []string{"ftp", "https"}, true},
+		{"mailto:", []string{"mailto"}, false},
+	}
+
+	for _, c := range cases {
+		err := ValidateURL(c.url, c.schemes...)
+		if c.valid && err != nil {
+			t.Errorf("Expected %q to be valid with schemes %v, got: %v", c.url, c.schemes, err)
+		}
+
+		if !c.valid && err == nil {
+			t.Errorf("Expected %q to be invalid with schemes %v", c.url, c.schemes)
+		}
+	}
+}
+
+func TestNormalizedURLIsValid(t *testing.T) {
+	for _, in := range []string{"example.com", "www.example.com/a", "localhost:8080"} {
+		if err := ValidateURL(NormalizeURL(in)); err != nil {
+			t.Errorf("Expected normalized %q to be valid, got: %v", in, err)
+		}
+	}
+}
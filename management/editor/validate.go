@@ -0,0 +1,283 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// RequireOneOf returns a Field which ensures at least one of the named fields
+// has a value before the editor form is submitted, e.g. a phone number or an
+// email address. The names are those submitted by the form (the `json` struct
+// tags), and repeated values such as "emails.0" count towards their field.
+// Add it to Form alongside the fields it constrains, and check the same
+// constraint on the server with ValidateRequireOneOf, since client-side
+// checks can be bypassed.
+func RequireOneOf(fields ...string) Field {
+	script := `
+			var message = ` + jsString(text("validate.requireOneOf")) + `.replace('{fields}', fields.join(', '));
+
+			form.on('input change', selector, function() {
+				if (filledFields().length > 0) {
+					group.find('.error').text('');
+					form.find(selector).removeClass('invalid');
+				}
+			});
+
+			form.on('submit', function(e) {
+				if (filledFields().length > 0) {
+					return;
+				}
+
+				e.preventDefault();
+				e.stopImmediatePropagation();
+
+				group.find('.error').text(message);
+				form.find(selector).addClass('invalid');
+			});`
+
+	return fieldGroup("require-one-of", fields, script)
+}
+
+// MutuallyExclusive returns a Field which ensures at most one of the named
+// fields has a value, e.g. either a file upload or an external URL but not
+// both. Once one of the fields is filled in the others are disabled, and if
+// more than one already has a value an error is shown and the editor form
+// cannot be submitted. The names are those submitted by the form (the `json`
+// struct tags). Check the same constraint on the server with
+// ValidateMutuallyExclusive, since client-side checks can be bypassed.
+func MutuallyExclusive(fields ...string) Field {
+	script := `
+			var message = ` + jsString(text("validate.mutuallyExclusive")) + `.replace('{fields}', fields.join(', '));
+
+			var update = function() {
+				var filled = filledFields();
+
+				if (filled.length > 1) {
+					group.find('.error').text(message);
+					form.find(selector).prop('disabled', false);
+					$.each(filled, function(i, f) {
+						form.find(selectorFor(f)).addClass('invalid');
+					});
+
+					return;
+				}
+
+				group.find('.error').text('');
+				form.find(selector).removeClass('invalid');
+
+				$.each(fields, function(i, f) {
+					form.find(selectorFor(f)).prop('disabled', filled.length === 1 && filled[0] !== f);
+				});
+			}
+
+			form.on('input change', selector, update);
+			update();
+
+			form.on('submit', function(e) {
+				if (filledFields().length < 2) {
+					return;
+				}
+
+				e.preventDefault();
+				e.stopImmediatePropagation();
+				update();
+			});`
+
+	return fieldGroup("mutually-exclusive", fields, script)
+}
+
+// fieldGroup returns a Field holding an error message container for a
+// constraint across the named fields, and the script which enforces it. The
+// script has access to the group container, its form, the field names, and
+// helpers to select and check the fields.
+func fieldGroup(kind string, fields []string, script string) Field {
+	id := kind + "-" + strings.Join(fields, "-")
+	names, err := json.Marshal(fields)
+	if err != nil {
+		return Field{}
+	}
+
+	view := `<div class="__ponzu-` + kind + ` col s12" id="` + html.EscapeString(id) + `">
+		<span class="error red-text"></span>
+	</div>
+	<script>
+		$(function() {
+			var group = $(document.getElementById(` + jsString(id) + `)),
+				form = group.closest('form'),
+				fields = ` + string(names) + `;
+
+			var selectorFor = function(f) {
+				return '[name="' + f + '"], [name^="' + f + '."]';
+			}
+
+			var selector = $.map(fields, selectorFor).join(', ');
+
+			// hasValue reports whether any input of the field f has a value
+			var hasValue = function(f) {
+				var ok = false;
+				form.find(selectorFor(f)).each(function(i, el) {
+					var $el = $(el);
+					if ($el.is(':checkbox, :radio') && !$el.is(':checked')) {
+						return;
+					}
+
+					if ($.trim($el.val() || '') !== '') {
+						ok = true;
+					}
+				});
+
+				return ok;
+			}
+
+			var filledFields = function() {
+				return $.grep(fields, hasValue);
+			}
+` + script + `
+		});
+	</script>`
+
+	return Field{View: []byte(view)}
+}
+
+// ValidateRequireOneOf checks the submitted form values and returns an error
+// unless at least one of the named fields has a non-empty value. It is the
+// server-side counterpart to RequireOneOf.
+func ValidateRequireOneOf(form url.Values, fields ...string) error {
+	for _, field := range fields {
+		if formHasValue(form, field) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("at least one of %s is required", strings.Join(fields, ", "))
+}
+
+// ValidateMutuallyExclusive checks the submitted form values and returns an
+// error if more than one of the named fields has a non-empty value. It is the
+// server-side counterpart to MutuallyExclusive.
+func ValidateMutuallyExclusive(form url.Values, fields ...string) error {
+	var set []string
+	for _, field := range fields {
+		if formHasValue(form, field) {
+			set = append(set, field)
+		}
+	}
+
+	if len(set) > 1 {
+		return fmt.Errorf("only one of %s may be set, got values for %s",
+			strings.Join(fields, ", "), strings.Join(set, ", "))
+	}
+
+	return nil
+}
+
+// TrimForm trims leading and trailing whitespace from the submitted values of
+// the named fields, including their indexed values (name.0, name.1, ...), so
+// that stored values are clean even if the editor's client-side trimming was
+// bypassed. When no fields are named, every value in the form is trimmed.
+func TrimForm(form url.Values, fields ...string) {
+	for key, vals := range form {
+		if len(fields) > 0 && !matchesField(key, fields) {
+			continue
+		}
+
+		for i := range vals {
+			vals[i] = strings.TrimSpace(vals[i])
+		}
+	}
+}
+
+// matchesField reports whether the form key is one of fields or one of their
+// indexed names
+func matchesField(key string, fields []string) bool {
+	for _, field := range fields {
+		if key == field || strings.HasPrefix(key, field+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formHasValue reports whether the form contains a non-empty value for name,
+// either under the name itself or under one of its indexed names (name.0, ...)
+func formHasValue(form url.Values, name string) bool {
+	for key, vals := range form {
+		if !matchesField(key, []string{name}) {
+			continue
+		}
+
+		for _, v := range vals {
+			if strings.TrimSpace(v) != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// jsString returns s as a quoted JavaScript string literal which is safe to
+// embed inside a <script> element
+func jsString(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return `""`
+	}
+
+	return string(b)
+}
+
+// validationConstraints are the attrs which constrain the value of a field, and
+// which enable its inline validation state when present
+var validationConstraints = []string{
+	"required", "pattern", "minlength", "maxlength", "min", "max",
+}
+
+// hasConstraints reports whether attrs contain any validation constraints
+func hasConstraints(attrs map[string]string) bool {
+	for _, c := range validationConstraints {
+		if v, ok := attrs[c]; ok && (!booleanAttrs[c] || boolAttr(v)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validIndicator returns the success indicator placed after a constrained
+// field, along with the script which sets the 'valid' and 'invalid' classes on
+// constrained fields once they have been interacted with. The indicator is only
+// visible while its field is valid and non-empty.
+func validIndicator() string {
+	var sel []string
+	for _, tag := range []string{"input", "textarea", "select"} {
+		for _, c := range validationConstraints {
+			sel = append(sel, tag+"["+c+"]")
+		}
+	}
+
+	return `<i class="material-icons tiny green-text __ponzu-valid-indicator">check</i>
+	<script>
+		$(function() {
+			if (window.__ponzuValidIndicator) {
+				return;
+			}
+			window.__ponzuValidIndicator = true;
+
+			$(document).on('focusout change', '` + strings.Join(sel, ", ") + `', function(e) {
+				var el = e.target, $el = $(el);
+				if (typeof el.checkValidity !== 'function') {
+					return;
+				}
+
+				var valid = el.checkValidity();
+				$el.toggleClass('invalid', !valid);
+				$el.toggleClass('valid', valid && $.trim($el.val() || '') !== '');
+			});
+		});
+	</script>`
+}
@@ -0,0 +1,156 @@
+package editor
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type testContact struct {
+	Name  string   `json:"name"`
+	Slug  string   `json:"slug"`
+	Bio   string   `json:"bio"`
+	Links []string `json:"links"`
+}
+
+func TestTrimForm(t *testing.T) {
+	form := url.Values{
+		"name":    {"  Jane Doe \t"},
+		"slug":    {" jane-doe\n"},
+		"links.0": {" https://example.com "},
+		"bio":     {"  Keep me  "},
+	}
+
+	TrimForm(form, "name", "slug", "links")
+
+	expected := map[string]string{
+		"name":    "Jane Doe",
+		"slug":    "jane-doe",
+		"links.0": "https://example.com",
+		"bio":     "  Keep me  ",
+	}
+
+	for k, v := range expected {
+		if form.Get(k) != v {
+			t.Errorf("Expected %s to be %q, got: %q", k, v, form.Get(k))
+		}
+	}
+
+	TrimForm(form)
+	if form.Get("bio") != "Keep me" {
+		t.Errorf("Expected all values to be trimmed, got: %q", form.Get("bio"))
+	}
+}
+
+func TestInputTrimOnSubmit(t *testing.T) {
+	c := &testContact{}
+
+	view := string(Input("Slug", c, map[string]string{"type": "text"}))
+	if !strings.Contains(view, `data-ponzu-trim="true"`) {
+		t.Errorf("Expected text input to be trimmed on submit, got: %s", view)
+	}
+
+	view = string(Input("Slug", c, map[string]string{"type": "text", "trim": "false"}))
+	if strings.Contains(view, `data-ponzu-trim`) || strings.Contains(view, `trim="false"`) {
+		t.Errorf("Expected text input to opt out of trimming, got: %s", view)
+	}
+
+	view = string(Textarea("Bio", c, map[string]string{}))
+	if strings.Contains(view, `data-ponzu-trim`) {
+		t.Errorf("Expected textarea not to be trimmed by default, got: %s", view)
+	}
+
+	view = string(Input("Name", c, map[string]string{"type": "text"}))
+	if strings.Contains(view, `data-ponzu-trim`) {
+		t.Errorf("Expected a text input which isn't slug-like not to be trimmed by default, got: %s", view)
+	}
+
+	view = string(Input("Name", c, map[string]string{"type": "text", "trim": "true"}))
+	if !strings.Contains(view, `data-ponzu-trim="true" name="name"`) {
+		t.Errorf("Expected text input to opt in to trimming, got: %s", view)
+	}
+
+	view = string(Slug("Name", c, "Bio", map[string]string{}))
+	if !strings.Contains(view, `data-ponzu-trim="true" name="name"`) {
+		t.Errorf("Expected a slug to be trimmed on submit, got: %s", view)
+	}
+}
+
+func TestValidateRequireOneOf(t *testing.T) {
+	cases := []struct {
+		form url.Values
+		fail bool
+	}{
+		{url.Values{}, true},
+		{url.Values{"phone": {""}, "emails.0": {""}}, true},
+		{url.Values{"phone": {"  "}, "emails.0": {"\t"}}, true},
+		{url.Values{"phones": {"555-0100"}}, true},
+		{url.Values{"phone": {"555-0100"}}, false},
+		{url.Values{"phone": {""}, "emails.0": {""}, "emails.1": {"ada@example.com"}}, false},
+		{url.Values{"phone": {"555-0100"}, "emails.0": {"ada@example.com"}}, false},
+	}
+
+	for _, c := range cases {
+		err := ValidateRequireOneOf(c.form, "phone", "emails")
+		if c.fail && err == nil {
+			t.Errorf("Expected error without any of the fields set in %v, got nil", c.form)
+		}
+
+		if !c.fail && err != nil {
+			t.Errorf("Expected no error for %v, got: %s", c.form, err)
+		}
+	}
+}
+
+func TestValidateMutuallyExclusive(t *testing.T) {
+	cases := []struct {
+		form url.Values
+		fail bool
+	}{
+		{url.Values{}, false},
+		{url.Values{"upload": {"/api/uploads/a.png"}}, false},
+		{url.Values{"upload": {"/api/uploads/a.png"}, "external_url": {" "}}, false},
+		{url.Values{"upload": {"/api/uploads/a.png"}, "external_url": {"https://example.com/a.png"}}, true},
+		{url.Values{"upload": {""}, "external_url.0": {"x"}, "embed": {"y"}}, true},
+	}
+
+	for _, c := range cases {
+		err := ValidateMutuallyExclusive(c.form, "upload", "external_url", "embed")
+		if c.fail && err == nil {
+			t.Errorf("Expected error for multiple set fields in %v, got nil", c.form)
+		}
+
+		if !c.fail && err != nil {
+			t.Errorf("Expected no error for %v, got: %s", c.form, err)
+		}
+	}
+}
+
+func TestValidateNumberRange(t *testing.T) {
+	cases := []struct {
+		min, max string
+		valid    bool
+	}{
+		{"", "", true},
+		{"10", "", true},
+		{"", "10", true},
+		{"10", "10", true},
+		{"1.5", "20", true},
+		{"-5", "-1", true},
+		{"20", "10", false},
+		{"ten", "20", false},
+		{"1", "x", false},
+	}
+
+	for _, c := range cases {
+		form := url.Values{"price.min": {c.min}, "price.max": {c.max}}
+		err := ValidateNumberRange(form, "price")
+		if c.valid && err != nil {
+			t.Errorf("Expected %q-%q to be valid, got: %v", c.min, c.max, err)
+		}
+
+		if !c.valid && err == nil {
+			t.Errorf("Expected %q-%q to be invalid", c.min, c.max)
+		}
+	}
+}
@@ -2,12 +2,87 @@ package editor
 
 import (
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 )
 
+// DefaultRepeatDelimiter separates the values of a repeated field when they are
+// joined into a single string, e.g. by ValueFromStructField
+const DefaultRepeatDelimiter = "__ponzu"
+
+var (
+	repeatDelimiterMu sync.RWMutex
+	repeatDelimiter   = DefaultRepeatDelimiter
+)
+
+// SetRepeatDelimiter sets the string which separates the values of a repeated
+// field when they are joined into a single string, for content whose values
+// may legitimately contain the DefaultRepeatDelimiter. It should be set once,
+// before any editor is rendered, and d must not be empty.
+func SetRepeatDelimiter(d string) error {
+	if d == "" {
+		return fmt.Errorf("the repeat delimiter must not be empty")
+	}
+
+	repeatDelimiterMu.Lock()
+	repeatDelimiter = d
+	repeatDelimiterMu.Unlock()
+
+	return nil
+}
+
+// RepeatDelimiter returns the string which separates the values of a repeated
+// field when they are joined into a single string
+func RepeatDelimiter() string {
+	repeatDelimiterMu.RLock()
+	defer repeatDelimiterMu.RUnlock()
+
+	return repeatDelimiter
+}
+
+// ValidateRepeated returns an error if any of vals contains the RepeatDelimiter,
+// since such a value would be split into several values once joined. Use it to
+// reject these values before they are stored, e.g. in a BeforeSave hook.
+func ValidateRepeated(vals []string) error {
+	d := RepeatDelimiter()
+	for i, v := range vals {
+		if strings.Contains(v, d) {
+			return fmt.Errorf("value %d (%q) must not contain %q", i, v, d)
+		}
+	}
+
+	return nil
+}
+
+// ParseRepeated returns the values submitted by a repeater named fieldName,
+// e.g. "tags", in order of their indexed names (name.0, name.1, ...), whatever
+// order they were submitted in. The gaps left by removed items, e.g. a form
+// holding only "tags.0" and "tags.2", are skipped. Once the admin has folded the
+// indexed values into the field's name, those values are returned instead.
+func ParseRepeated(form url.Values, fieldName string) []string {
+	vals := indexedValues(form, fieldName)
+	if len(vals) == 0 {
+		vals = append(vals, form[fieldName]...)
+	}
+
+	return vals
+}
+
+// JoinRepeated joins vals into a single string with the RepeatDelimiter, as
+// repeated values are stored when a field holds a string rather than a slice,
+// and split again by ValuesFromStructField. Check vals with ValidateRepeated
+// first, since a value containing the delimiter would not round-trip.
+func JoinRepeated(vals []string) string {
+	return strings.Join(vals, RepeatDelimiter())
+}
+
 // TagNameFromStructField does a lookup on the `json` struct tag for a given
-// field of a struct
+// field of a struct. Fields of nested structs may be addressed with a dotted
+// name, e.g. "SEO.Title", in which case the json tags along the path are joined
+// with a dot, e.g. "seo.title". It panics when name can't be resolved, while
+// post which isn't a non-nil pointer to a struct is logged and has no tag name.
 func TagNameFromStructField(name string, post interface{}) string {
 	// sometimes elements in these environments will not have a name,
 	// and thus no tag name in the struct which correlates to it.
@@ -15,19 +90,52 @@ func TagNameFromStructField(name string, post interface{}) string {
 		return name
 	}
 
-	field, ok := reflect.TypeOf(post).Elem().FieldByName(name)
-	if !ok {
-		panic("Couldn't get struct field for: " + name + ". Make sure you pass the right field name to editor field elements.")
+	// content which isn't a pointer to a struct has no fields to name
+	err := checkContent(name, post)
+	if err != nil {
+		logResolveFailure(name, post, err)
+		return ""
 	}
 
-	tag, ok := field.Tag.Lookup("json")
-	if !ok {
-		panic("Couldn't get json struct tag for: " + name + ". Struct fields for content types must have 'json' tags.")
+	tag, err := tagNameFromPath(name, reflect.TypeOf(post).Elem())
+	if err != nil {
+		logResolveFailure(name, post, err)
+		panic(err.Error())
 	}
 
 	return tag
 }
 
+// tagNameFromPath walks the (possibly dotted) field name through the struct
+// type t and returns the json tags of each field on the path joined by a dot
+func tagNameFromPath(name string, t reflect.Type) (string, error) {
+	var tags []string
+	for _, part := range strings.Split(name, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return "", fmt.Errorf("Couldn't get struct field for: %s. '%s' is not a struct field, so '%s' cannot be resolved.", name, strings.Join(tags, "."), part)
+		}
+
+		field, ok := t.FieldByName(part)
+		if !ok {
+			return "", fmt.Errorf("Couldn't get struct field for: %s. Make sure you pass the right field name to editor field elements.", name)
+		}
+
+		tag, ok := field.Tag.Lookup("json")
+		if !ok {
+			return "", fmt.Errorf("Couldn't get json struct tag for: %s. Struct fields for content types must have 'json' tags.", name)
+		}
+
+		tags = append(tags, tag)
+		t = field.Type
+	}
+
+	return strings.Join(tags, "."), nil
+}
+
 // TagNameFromStructFieldMulti calls TagNameFromStructField and formats is for
 // use with gorilla/schema
 // due to the format in which gorilla/schema expects form names to be when
@@ -39,9 +147,26 @@ func TagNameFromStructFieldMulti(name string, i int, post interface{}) string {
 	return fmt.Sprintf("%s.%d", tag, i)
 }
 
-// ValueFromStructField returns the string value of a field in a struct
+// ValueFromStructField returns the string value of a field in a struct. Fields
+// of nested structs may be addressed with a dotted name, e.g. "SEO.Title". The
+// value is empty when post isn't a non-nil pointer to a struct, which is logged.
 func ValueFromStructField(name string, post interface{}) string {
-	field := reflect.Indirect(reflect.ValueOf(post)).FieldByName(name)
+	// content which isn't a pointer to a struct has no values
+	err := checkContent(name, post)
+	if err != nil {
+		logResolveFailure(name, post, err)
+		return ""
+	}
+
+	field, err := fieldByPath(name, post)
+	if err != nil {
+		logResolveFailure(name, post, err)
+		panic(err.Error())
+	}
+
+	if t, ok := timeValue(field); ok {
+		return FormatTime(t, TimeLayout)
+	}
 
 	switch field.Kind() {
 	case reflect.String:
@@ -67,12 +192,99 @@ func ValueFromStructField(name string, post interface{}) string {
 
 		for i := 0; i < field.Len(); i++ {
 			pos := field.Index(i)
+			if t, ok := timeValue(pos); ok {
+				s = append(s, FormatTime(t, TimeLayout))
+				continue
+			}
+
 			s = append(s, fmt.Sprintf("%v", pos))
 		}
 
-		return strings.Join(s, "__ponzu")
+		for _, v := range s {
+			if strings.Contains(v, RepeatDelimiter()) {
+				logf("editor: repeated value contains the repeat delimiter",
+					"field", name, "value", v, "delimiter", RepeatDelimiter())
+			}
+		}
+
+		return strings.Join(s, RepeatDelimiter())
 
 	default:
-		panic(fmt.Sprintf("Ponzu: Type '%s' for field '%s' not supported.", field.Type(), name))
+		err := fmt.Errorf("Ponzu: Type '%s' for field '%s' not supported.", field.Type(), name)
+		logResolveFailure(name, post, err)
+		panic(err.Error())
+	}
+}
+
+// ValuesFromStructField returns the string values of a field in a struct. Slice
+// fields (such as []string or []int) are read element by element, avoiding the
+// lossy round-trip through a joined string, while any other field is split on
+// the RepeatDelimiter as before. The result always contains at least one value
+// so that repeaters can render an empty element for new content, or when post
+// isn't a non-nil pointer to a struct, which is logged.
+func ValuesFromStructField(name string, post interface{}) []string {
+	err := checkContent(name, post)
+	if err != nil {
+		logResolveFailure(name, post, err)
+		return []string{""}
+	}
+
+	field, err := fieldByPath(name, post)
+	if err != nil {
+		logResolveFailure(name, post, err)
+		panic(err.Error())
+	}
+
+	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+		return strings.Split(ValueFromStructField(name, post), RepeatDelimiter())
+	}
+
+	if field.Len() == 0 {
+		return []string{""}
 	}
+
+	vals := make([]string, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		if t, ok := timeValue(field.Index(i)); ok {
+			vals = append(vals, FormatTime(t, TimeLayout))
+			continue
+		}
+
+		vals = append(vals, fmt.Sprintf("%v", field.Index(i)))
+	}
+
+	return vals
+}
+
+// fieldByPath walks the (possibly dotted) field name through post and returns
+// the value of the field it resolves to. A nil pointer to a nested struct along
+// the path resolves to the zero value of the field.
+func fieldByPath(name string, post interface{}) (reflect.Value, error) {
+	err := checkContent(name, post)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(post))
+	for _, part := range strings.Split(name, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v = reflect.Zero(v.Type().Elem())
+				continue
+			}
+
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("Couldn't get struct field for: %s. '%s' is not a struct.", name, v.Type())
+		}
+
+		v = v.FieldByName(part)
+		if !v.IsValid() {
+			return reflect.Value{}, fmt.Errorf("Couldn't get struct field for: %s. Make sure you pass the right field name to editor field elements.", name)
+		}
+	}
+
+	return v, nil
 }
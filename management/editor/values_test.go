@@ -0,0 +1,162 @@
+package editor
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testMeta struct {
+	Description string `json:"description"`
+}
+
+type testSEO struct {
+	Title string    `json:"title"`
+	Meta  testMeta  `json:"meta"`
+	Extra *testMeta `json:"extra"`
+}
+
+type testPage struct {
+	Name string  `json:"name"`
+	SEO  testSEO `json:"seo"`
+}
+
+func TestStructFieldNestedPaths(t *testing.T) {
+	p := &testPage{
+		Name: "Home",
+		SEO: testSEO{
+			Title: "Welcome",
+			Meta:  testMeta{Description: "The home page"},
+		},
+	}
+
+	cases := []struct {
+		field, tag, value string
+	}{
+		{"Name", "name", "Home"},
+		{"SEO.Title", "seo.title", "Welcome"},
+		{"SEO.Meta.Description", "seo.meta.description", "The home page"},
+		{"SEO.Extra.Description", "seo.extra.description", ""},
+	}
+
+	for _, c := range cases {
+		if tag := TagNameFromStructField(c.field, p); tag != c.tag {
+			t.Errorf("Expected tag %s for %s, got: %s", c.tag, c.field, tag)
+		}
+
+		if val := ValueFromStructField(c.field, p); val != c.value {
+			t.Errorf("Expected value %s for %s, got: %s", c.value, c.field, val)
+		}
+	}
+}
+
+func TestStructFieldNestedMissing(t *testing.T) {
+	p := &testPage{}
+
+	for _, field := range []string{"SEO.Missing.Description", "SEO.Title.Missing"} {
+		if _, err := tagNameFromPath(field, reflect.TypeOf(p).Elem()); err == nil {
+			t.Errorf("Expected error resolving tag for %s, got nil", field)
+		}
+
+		if _, err := fieldByPath(field, p); err == nil {
+			t.Errorf("Expected error resolving value for %s, got nil", field)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for missing intermediate field")
+		}
+	}()
+
+	ValueFromStructField("SEO.Missing.Description", p)
+}
+
+func TestStructFieldResolveFailureLogged(t *testing.T) {
+	var logged map[string]interface{}
+	SetLogger(LoggerFunc(func(msg string, keyvals ...interface{}) {
+		logged = make(map[string]interface{})
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			logged[keyvals[i].(string)] = keyvals[i+1]
+		}
+	}))
+	defer SetLogger(nil)
+
+	func() {
+		defer func() { recover() }()
+		TagNameFromStructField("Naem", &testPage{})
+	}()
+
+	if logged == nil {
+		t.Fatal("Expected the resolve failure to be logged")
+	}
+
+	if logged["field"] != "Naem" {
+		t.Errorf("Expected logged field Naem, got: %v", logged["field"])
+	}
+
+	if logged["type"] != "*editor.testPage" {
+		t.Errorf("Expected logged type *editor.testPage, got: %v", logged["type"])
+	}
+
+	if caller, _ := logged["caller"].(string); !strings.Contains(caller, "values_test.go") {
+		t.Errorf("Expected logged caller in values_test.go, got: %v", logged["caller"])
+	}
+}
+
+func TestRepeatDelimiter(t *testing.T) {
+	p := &testContact{Links: []string{"a", "b"}, Bio: "one|two"}
+
+	if err := SetRepeatDelimiter(""); err == nil {
+		t.Error("Expected an empty delimiter to be rejected")
+	}
+
+	if err := SetRepeatDelimiter("|"); err != nil {
+		t.Fatalf("Expected no error setting the delimiter, got: %v", err)
+	}
+	defer SetRepeatDelimiter(DefaultRepeatDelimiter)
+
+	if v := ValueFromStructField("Links", p); v != "a|b" {
+		t.Errorf("Expected values joined by the delimiter, got: %s", v)
+	}
+
+	if vals := ValuesFromStructField("Bio", p); !reflect.DeepEqual(vals, []string{"one", "two"}) {
+		t.Errorf("Expected value split on the delimiter, got: %v", vals)
+	}
+
+	if err := ValidateRepeated([]string{"ok", "not|ok"}); err == nil {
+		t.Error("Expected a value containing the delimiter to be rejected")
+	}
+
+	if err := ValidateRepeated([]string{"ok", "also__ponzu ok"}); err != nil {
+		t.Errorf("Expected the default delimiter to be allowed once changed, got: %v", err)
+	}
+}
+
+func TestParseRepeated(t *testing.T) {
+	cases := []struct {
+		name     string
+		form     url.Values
+		expected []string
+	}{
+		{"in order", url.Values{"tags.0": {"a"}, "tags.1": {"b"}}, []string{"a", "b"}},
+		{"deletion gaps", url.Values{"tags.0": {"a"}, "tags.2": {"c"}, "tags.5": {"f"}}, []string{"a", "c", "f"}},
+		{"out of order", url.Values{"tags.10": {"k"}, "tags.2": {"c"}, "tags.0": {"a"}}, []string{"a", "c", "k"}},
+		{"other fields", url.Values{"tags.0": {"a"}, "tags.x": {"x"}, "tagsy.1": {"y"}, "title": {"t"}}, []string{"a"}},
+		{"folded", url.Values{"tags": {"a", "b"}}, []string{"a", "b"}},
+		{"empty", url.Values{}, []string{}},
+	}
+
+	for _, c := range cases {
+		vals := ParseRepeated(c.form, "tags")
+		if !reflect.DeepEqual(vals, c.expected) {
+			t.Errorf("%s: expected %v, got: %v", c.name, c.expected, vals)
+		}
+	}
+
+	p := &testContact{Bio: JoinRepeated(ParseRepeated(url.Values{"bio.1": {"b"}, "bio.0": {"a"}}, "bio"))}
+	if vals := ValuesFromStructField("Bio", p); !reflect.DeepEqual(vals, []string{"a", "b"}) {
+		t.Errorf("Expected the joined values to round-trip, got: %v", vals)
+	}
+}
@@ -0,0 +1,267 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// WeightedItem is a single item of a WeightedSelectRepeater, selecting ID at
+// position Weight. It can be used as the element type of the field's slice.
+type WeightedItem struct {
+	ID     string `json:"id"`
+	Weight int    `json:"weight"`
+}
+
+// WeightedSelectRepeater returns the []byte of a repeatable <select> HTML element
+// where each item also carries an editable numeric weight, such as a curated
+// list of featured items in order. Items can be dragged by their handle to
+// reorder them, which rewrites the weights to match the new order, and editing
+// a weight moves its item into position. Each item submits its selected option
+// and weight as "name.0.id" and "name.0.weight", which can be read back with
+// ParseWeighted.
+// The field must be a slice of WeightedItem, or of a map or struct with "id"
+// and "weight" `json` keys. Options are displayed in order of their labels.
+// IMPORTANT:
+// The `fieldName` argument will cause a panic if it is not exactly the string
+// form of the struct field that this editor input is representing
+func WeightedSelectRepeater(fieldName string, p interface{}, attrs, options map[string]string) []byte {
+	checkAttrs("WeightedSelectRepeater", fieldName, attrs)
+
+	if view := contentError(fieldName, p); view != nil {
+		return view
+	}
+
+	scope := TagNameFromStructField(fieldName, p)
+	field, err := fieldByPath(fieldName, p)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var items []map[string]string
+	if field.Kind() == reflect.Slice {
+		for i := 0; i < field.Len(); i++ {
+			items = append(items, blockValues(field.Index(i)))
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		wi, _ := strconv.Atoi(items[i]["weight"])
+		wj, _ := strconv.Atoi(items[j]["weight"])
+		return wi < wj
+	})
+
+	if len(items) == 0 {
+		items = append(items, map[string]string{})
+	}
+
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if options[keys[i]] == options[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return options[keys[i]] < options[keys[j]]
+	})
+
+	view := &bytes.Buffer{}
+	_, err = view.WriteString(`<div class="__ponzu-weighted ` + scope + ` input-field col s12">`)
+	if err != nil {
+		log.Println("Error writing HTML string to WeightedSelectRepeater buffer")
+		return nil
+	}
+
+	if attrs["label"] != "" {
+		_, err = view.WriteString(`<label class="active">` + attrs["label"] + `</label>`)
+		if err != nil {
+			log.Println("Error writing HTML string to WeightedSelectRepeater buffer")
+			return nil
+		}
+	}
+
+	for i, item := range items {
+		weight := item["weight"]
+		if weight == "" {
+			weight = strconv.Itoa(i + 1)
+		}
+
+		row := `<div class="__ponzu-weighted-item row">` +
+			`<i class="material-icons __ponzu-drag-handle col s1" title="` + htmlText("repeat.drag") + `">drag_handle</i>` +
+			`<div class="col s7"><select class="browser-default" name="` + fmt.Sprintf("%s.%d.id", scope, i) + `">` +
+			`<option value="" disabled` + selectedIf(item["id"] == "") + `>` + htmlText("select.cta") + `</option>`
+
+		for _, k := range keys {
+			row += `<option value="` + html.EscapeString(k) + `"` + selectedIf(k == item["id"]) + `>` +
+				html.EscapeString(options[k]) + `</option>`
+		}
+
+		row += `</select></div>` +
+			`<div class="col s2"><input type="number" class="__ponzu-weight" step="1" title="` + htmlText("weighted.weight") + `" name="` +
+			fmt.Sprintf("%s.%d.weight", scope, i) + `" value="` + html.EscapeString(weight) + `" /></div>` +
+			`<span class="controls col s2">` +
+			`<button class="repeater-add btn-flat waves-effect waves-green">+</button>` +
+			`<button class="repeater-del btn-flat waves-effect waves-red">-</button>` +
+			`</span></div>`
+
+		_, err = view.WriteString(row)
+		if err != nil {
+			log.Println("Error writing HTML string to WeightedSelectRepeater buffer")
+			return nil
+		}
+	}
+
+	_, err = view.WriteString(`</div>`)
+	if err != nil {
+		log.Println("Error writing HTML string to WeightedSelectRepeater buffer")
+		return nil
+	}
+
+	return append(view.Bytes(), weightedController(scope)...)
+}
+
+func selectedIf(selected bool) string {
+	if selected {
+		return ` selected`
+	}
+
+	return ""
+}
+
+// weightedController generates the javascript to add, remove, drag to reorder
+// and re-weight the items of a WeightedSelectRepeater, keeping the indexes of
+// the names and the weights consistent with the visual order
+func weightedController(scope string) []byte {
+	script := `
+	<script>
+		$(function() {
+			var scope = $('.__ponzu-weighted.` + selectorClass(scope) + `'),
+				dragging = null;
+
+			var getItems = function() {
+				return scope.children('.__ponzu-weighted-item');
+			}
+
+			// reset the names of each item to its position, and when setWeights
+			// is true, rewrite the weights to match the order too
+			var reindex = function(setWeights) {
+				getItems().each(function(i, item) {
+					var $item = $(item),
+						name = ` + jsString(scope) + ` + '.' + String(i);
+
+					$item.find('select').attr('name', name + '.id');
+					$item.find('.__ponzu-weight').attr('name', name + '.weight');
+
+					if (setWeights) {
+						$item.find('.__ponzu-weight').val(i + 1);
+					}
+				});
+			}
+
+			// move items into the order of their weights
+			var sortByWeight = function() {
+				var items = getItems().get();
+				items.sort(function(a, b) {
+					var wa = parseInt($(a).find('.__ponzu-weight').val(), 10) || 0,
+						wb = parseInt($(b).find('.__ponzu-weight').val(), 10) || 0;
+
+					return wa - wb;
+				});
+
+				scope.append(items);
+				reindex(false);
+			}
+
+			scope.on('change', '.__ponzu-weight', sortByWeight);
+
+			scope.on('click', '.repeater-add', function(e) {
+				e.preventDefault();
+
+				var item = $(e.target).closest('.__ponzu-weighted-item'),
+					clone = item.clone();
+
+				clone.find('select').val('');
+				item.after(clone);
+				reindex(true);
+			});
+
+			scope.on('click', '.repeater-del', function(e) {
+				e.preventDefault();
+
+				if (getItems().length === 1) {
+					getItems().find('select').val('');
+					return;
+				}
+
+				$(e.target).closest('.__ponzu-weighted-item').remove();
+				reindex(true);
+			});
+
+			// only make an item draggable while its handle is held, so that the
+			// select and weight inputs still work normally
+			scope.on('mousedown', '.__ponzu-drag-handle', function(e) {
+				$(e.target).closest('.__ponzu-weighted-item').attr('draggable', 'true');
+			});
+
+			scope.on('dragstart', '.__ponzu-weighted-item', function(e) {
+				dragging = this;
+				e.originalEvent.dataTransfer.effectAllowed = 'move';
+				e.originalEvent.dataTransfer.setData('text/plain', '');
+			});
+
+			scope.on('dragover', '.__ponzu-weighted-item', function(e) {
+				e.preventDefault();
+				if (!dragging || dragging === this) {
+					return;
+				}
+
+				var rect = this.getBoundingClientRect();
+				if (e.originalEvent.clientY - rect.top > rect.height / 2) {
+					$(this).after(dragging);
+				} else {
+					$(this).before(dragging);
+				}
+			});
+
+			scope.on('drop dragend', '.__ponzu-weighted-item', function(e) {
+				e.preventDefault();
+				if (!dragging) {
+					return;
+				}
+
+				$(dragging).removeAttr('draggable');
+				dragging = null;
+				reindex(true);
+			});
+		});
+	</script>`
+
+	return []byte(script)
+}
+
+// ParseWeighted reconstructs the items submitted by a WeightedSelectRepeater
+// from the form values, ordered by weight. Items without a selected ID are
+// left out.
+func ParseWeighted(form url.Values, fieldName string) []WeightedItem {
+	var items []WeightedItem
+	for _, values := range ParseBlocks(form, fieldName) {
+		if values["id"] == "" {
+			continue
+		}
+
+		weight, _ := strconv.Atoi(values["weight"])
+		items = append(items, WeightedItem{ID: values["id"], Weight: weight})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Weight < items[j].Weight
+	})
+
+	return items
+}
@@ -0,0 +1,101 @@
+package editor
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type testFeatured struct {
+	Featured []WeightedItem `json:"featured"`
+}
+
+var testWeightedOptions = map[string]string{"a": "Alpha", "b": "Beta", "c": "Gamma"}
+
+func TestWeightedSelectRepeater(t *testing.T) {
+	p := &testFeatured{Featured: []WeightedItem{{"c", 30}, {"a", 10}, {"b", 20}}}
+
+	view := string(WeightedSelectRepeater("Featured", p, map[string]string{"label": "Featured"}, testWeightedOptions))
+	view = view[:strings.Index(view, "<script>")]
+
+	// the items are rendered in order of their weights, with contiguous indexes
+	for i, want := range []string{
+		`name="featured.0.id"><option value="" disabled>Select an option...</option><option value="a" selected>Alpha</option>`,
+		`name="featured.0.weight" value="10"`,
+		`name="featured.1.id"><option value="" disabled>Select an option...</option><option value="a">Alpha</option><option value="b" selected>Beta</option>`,
+		`name="featured.1.weight" value="20"`,
+		`<option value="c" selected>Gamma</option>`,
+		`name="featured.2.weight" value="30"`,
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("%d: expected %s, got: %s", i, want, view)
+		}
+	}
+
+	view = string(WeightedSelectRepeater("Featured", &testFeatured{}, map[string]string{}, testWeightedOptions))
+	if !strings.Contains(view, `<option value="" disabled selected>Select an option...</option>`) ||
+		!strings.Contains(view, `name="featured.0.weight" value="1"`) || strings.Contains(view, "featured.1.") {
+		t.Errorf("Expected a single empty item weighted 1, got: %s", view)
+	}
+}
+
+func TestParseWeighted(t *testing.T) {
+	form := url.Values{
+		"featured.4.id":     {"a"},
+		"featured.4.weight": {"1"},
+		"featured.0.id":     {"c"},
+		"featured.0.weight": {"3"},
+		"featured.9.id":     {"b"},
+		"featured.9.weight": {"2"},
+		"featured.2.id":     {""},
+		"featured.2.weight": {"0"},
+		"featured.7.id":     {"d"},
+		"featured.-1.id":    {"e"},
+	}
+
+	// d has no weight, so it is weighted 0 and comes first
+	want := []WeightedItem{{"d", 0}, {"a", 1}, {"b", 2}, {"c", 3}}
+	if got := ParseWeighted(form, "featured"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseWeighted() = %v, want %v", got, want)
+	}
+
+	// items of the same weight keep the order of their indexes
+	form = url.Values{
+		"featured.1.id": {"b"}, "featured.1.weight": {"5"},
+		"featured.0.id": {"a"}, "featured.0.weight": {"5"},
+	}
+	want = []WeightedItem{{"a", 5}, {"b", 5}}
+	if got := ParseWeighted(form, "featured"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseWeighted() = %v, want %v", got, want)
+	}
+}
+
+func TestWeightedRoundTrip(t *testing.T) {
+	stored := []WeightedItem{{"b", 2}, {"c", 3}, {"a", 1}}
+
+	view := string(WeightedSelectRepeater("Featured", &testFeatured{Featured: stored}, map[string]string{}, testWeightedOptions))
+	view = view[:strings.Index(view, "<script>")]
+
+	// submit the selected option and weight of every rendered item
+	form := url.Values{}
+	selected := regexp.MustCompile(`<select[^>]* name="([^"]+)">.*?<option value="([^"]*)"[^>]* selected>`)
+	for _, m := range selected.FindAllStringSubmatch(view, -1) {
+		form.Add(m[1], m[2])
+	}
+
+	weights := regexp.MustCompile(`<input type="number"[^>]* name="([^"]+)" value="([^"]*)"`)
+	for _, m := range weights.FindAllStringSubmatch(view, -1) {
+		form.Add(m[1], m[2])
+	}
+
+	want := []WeightedItem{{"a", 1}, {"b", 2}, {"c", 3}}
+	if got := ParseWeighted(form, "featured"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseWeighted() = %v, want %v, from %v", got, want, form)
+	}
+
+	if form.Get("featured.0.id") != "a" || form.Get("featured.2.id") != "c" {
+		t.Errorf("Expected the items to be reindexed in order of their weights, got: %v", form)
+	}
+}
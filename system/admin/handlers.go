@@ -1583,6 +1583,89 @@ func adminPostListItem(e editor.Editable, typeName, status string) []byte {
 	return []byte(post)
 }
 
+func autosaveHandler(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := editor.ValidateCSRF(req)
+	if err != nil {
+		log.Println("Error validating CSRF token in autosaveHandler:", err)
+		res.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	t := req.URL.Query().Get("type")
+	if strings.Contains(t, "__") {
+		t = strings.Split(t, "__")[0]
+	}
+
+	contentType, ok := item.Types[t]
+	if !ok {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	a, ok := contentType().(editor.Autosaver)
+	if !ok {
+		log.Println("Content type", t, "must implement editor.Autosaver before it can be autosaved.")
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = a.Autosave(res, req)
+	if err != nil {
+		log.Println("Error running Autosave method in autosaveHandler for:", t, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+func referenceHandler(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := editor.ValidateCSRF(req); err != nil {
+		log.Println("Error validating CSRF token in referenceHandler:", err)
+		res.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	q := req.URL.Query()
+	t := q.Get("type")
+	if _, ok := item.Types[t]; !ok {
+		res.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	display := q.Get("display")
+	if display == "" {
+		display = "title"
+	}
+
+	count, err := strconv.Atoi(q.Get("count"))
+	if err != nil {
+		count = editor.DefaultReferenceCount
+	}
+
+	opts := editor.ReferenceOptions(db.ContentAll(t), display, q.Get("store"), q.Get("q"), q.Get("value"), count)
+
+	j, err := json.Marshal(map[string]interface{}{"data": opts})
+	if err != nil {
+		log.Println("Error encoding references in referenceHandler for:", t, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json")
+	res.Write(j)
+}
+
 func approveContentHandler(res http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodPost {
 		res.WriteHeader(http.StatusMethodNotAllowed)
@@ -1788,6 +1871,20 @@ func editHandler(res http.ResponseWriter, req *http.Request) {
 			item.SetItemID(-1)
 		}
 
+		// the editor submits the token of this cookie, see editor.ValidateCSRF
+		_, err := editor.SetCSRFCookie(res, req)
+		if err != nil {
+			log.Println(err)
+			res.WriteHeader(http.StatusInternalServerError)
+			errView, err := Error500()
+			if err != nil {
+				return
+			}
+
+			res.Write(errView)
+			return
+		}
+
 		m, err := manager.Manage(post.(editor.Editable), t)
 		if err != nil {
 			log.Println(err)
@@ -1825,6 +1922,19 @@ func editHandler(res http.ResponseWriter, req *http.Request) {
 			return
 		}
 
+		err = editor.ValidateCSRF(req)
+		if err != nil {
+			log.Println("Error validating CSRF token in editHandler:", err)
+			res.WriteHeader(http.StatusForbidden)
+			errView, err := ErrorMessage("Forbidden", "The form has expired or was not submitted from the editor. Please reload the page and try again.")
+			if err != nil {
+				return
+			}
+
+			res.Write(errView)
+			return
+		}
+
 		cid := req.FormValue("id")
 		t := req.FormValue("type")
 		ts := req.FormValue("timestamp")
@@ -1862,9 +1972,11 @@ func editHandler(res http.ResponseWriter, req *http.Request) {
 		// fieldX.0: value1, fieldX.1: value2 => fieldX: []string{value1, value2}
 		fieldOrderValue := make(map[string]map[string][]string)
 		for k, v := range req.PostForm {
-			if strings.Contains(k, ".") {
-				fo := strings.Split(k, ".")
+			fo := strings.Split(k, ".")
 
+			// only fold repeated values (name.N) into their field, and leave
+			// other dotted names (e.g. price.min, links.0.url) to the decoder
+			if len(fo) == 2 && isIndex(fo[1]) {
 				// put the order and the field value into map
 				field := string(fo[0])
 				order := string(fo[1])
@@ -2228,6 +2340,13 @@ func deleteUploadHandler(res http.ResponseWriter, req *http.Request) {
 	http.Redirect(res, req, redir, http.StatusFound)
 }
 
+// isIndex reports whether s is the index of a repeated form value, e.g. the
+// "2" of "name.2"
+func isIndex(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil && !strings.HasPrefix(s, "-")
+}
+
 func editUploadHandler(res http.ResponseWriter, req *http.Request) {
 	switch req.Method {
 	case http.MethodGet:
@@ -43,6 +43,8 @@ func Run() {
 	http.HandleFunc("/admin/edit", user.Auth(editHandler))
 	http.HandleFunc("/admin/edit/delete", user.Auth(deleteHandler))
 	http.HandleFunc("/admin/edit/approve", user.Auth(approveContentHandler))
+	http.HandleFunc("/admin/edit/autosave", user.Auth(autosaveHandler))
+	http.HandleFunc("/admin/edit/reference", user.Auth(referenceHandler))
 	http.HandleFunc("/admin/edit/upload", user.Auth(editUploadHandler))
 	http.HandleFunc("/admin/edit/upload/delete", user.Auth(deleteUploadHandler))
 